@@ -0,0 +1,90 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AllowedRoles returns the roles the field's acl tag permits for op (usually
+// "read" or "write"), e.g. []string{"admin", "self"} for "read" with
+// `acl:"read:admin|self,write:admin"`. It returns false if the field places no
+// restriction on op, either because it has no acl tag or because the tag
+// doesn't mention op.
+func (f *Field) AllowedRoles(op string) ([]string, bool) {
+	t := f.tags.Get("acl")
+	if t == nil {
+		return nil, false
+	}
+
+	for _, rule := range t.ValueList(",") {
+		name, roles, _ := strings.Cut(rule, ":")
+		if name == op {
+			return SplitList(roles, "|"), true
+		}
+	}
+
+	return nil, false
+}
+
+// Allows reports whether role may perform op on the field. The role "*" in an
+// acl tag allows everyone.
+func (f *Field) Allows(op, role string) bool {
+	roles, restricted := f.AllowedRoles(op)
+	if !restricted {
+		return true
+	}
+
+	for _, e := range roles {
+		if e == role || e == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *Field) ReadableBy(role string) bool { return f.Allows("read", role) }
+func (f *Field) WritableBy(role string) bool { return f.Allows("write", role) }
+
+func (l FieldList) ReadableBy(role string) FieldList { return l.allowing("read", role) }
+func (l FieldList) WritableBy(role string) FieldList { return l.allowing("write", role) }
+
+func (l FieldList) allowing(op, role string) FieldList {
+	r := make(FieldList, 0, len(l))
+
+	for _, f := range l {
+		if f.Allows(op, role) {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
+// FilterForRole sets every field of v that role may not read to its zero
+// value. v must be a pointer to a struct; nested structs, and slices and
+// arrays of them, are filtered too.
+func FilterForRole(v interface{}, role string) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FilterForRole: %w", err)
+	}
+
+	err = walkValue(rv, "", func(f *Field, v reflect.Value, path string) (bool, error) {
+		if f.ReadableBy(role) {
+			return true, nil
+		}
+
+		if v.CanSet() {
+			v.Set(reflect.Zero(v.Type()))
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("reflectutil.FilterForRole: %w", err)
+	}
+
+	return nil
+}
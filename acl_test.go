@@ -0,0 +1,83 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type aclTestProfile struct {
+	Bio   string
+	Phone string `acl:"read:admin|self"`
+}
+
+type aclTestUser struct {
+	ID       string
+	Email    string `acl:"read:admin|self,write:admin"`
+	Password string `acl:"read:nobody,write:self"`
+	Name     string `acl:"read:*,write:admin|self"`
+	Profile  aclTestProfile
+	Others   []aclTestProfile
+}
+
+func TestFieldACL(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(aclTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	roles, restricted := d.Field("Email").AllowedRoles("read")
+	a.True(restricted)
+	a.Equal([]string{"admin", "self"}, roles)
+
+	_, restricted = d.Field("ID").AllowedRoles("read")
+	a.False(restricted)
+
+	for _, tc := range []struct {
+		role            string
+		readable, write []string
+	}{
+		{"admin", []string{"ID", "Email", "Name", "Profile", "Others"}, []string{"ID", "Email", "Name", "Profile", "Others"}},
+		{"self", []string{"ID", "Email", "Name", "Profile", "Others"}, []string{"ID", "Password", "Name", "Profile", "Others"}},
+		{"guest", []string{"ID", "Name", "Profile", "Others"}, []string{"ID", "Profile", "Others"}},
+	} {
+		a.Equal(tc.readable, d.Fields().ReadableBy(tc.role).Names(), tc.role)
+		a.Equal(tc.write, d.Fields().WritableBy(tc.role).Names(), tc.role)
+	}
+}
+
+func TestFilterForRole(t *testing.T) {
+	a := assert.New(t)
+
+	get := func() aclTestUser {
+		return aclTestUser{
+			ID:       "1",
+			Email:    "e",
+			Password: "p",
+			Name:     "n",
+			Profile:  aclTestProfile{Bio: "b", Phone: "123"},
+			Others:   []aclTestProfile{{Bio: "o", Phone: "456"}},
+		}
+	}
+
+	v := get()
+	if a.NoError(FilterForRole(&v, "guest")) {
+		a.Equal(aclTestUser{
+			ID:      "1",
+			Name:    "n",
+			Profile: aclTestProfile{Bio: "b"},
+			Others:  []aclTestProfile{{Bio: "o"}},
+		}, v)
+	}
+
+	v = get()
+	if a.NoError(FilterForRole(&v, "admin")) {
+		expected := get()
+		expected.Password = ""
+		a.Equal(expected, v)
+	}
+
+	a.Error(FilterForRole(get(), "admin"))
+}
@@ -0,0 +1,122 @@
+package reflectutil
+
+import (
+	"fmt"
+)
+
+// describeArenaChunk is the minimum number of items in each backing array
+// allocated by a describeArena.
+const describeArenaChunk = 256
+
+// describeArena hands out pieces of larger backing arrays, so that describing
+// many types makes a few large allocations rather than many small ones. A nil
+// *describeArena allocates normally.
+type describeArena struct {
+	descriptions []StructDescription
+	fields       []Field
+	tags         []Tag
+	parameters   []Parameter
+}
+
+func (a *describeArena) newDescription() *StructDescription {
+	if a == nil {
+		return &StructDescription{}
+	}
+
+	if len(a.descriptions) == 0 {
+		a.descriptions = make([]StructDescription, describeArenaChunk)
+	}
+
+	d := &a.descriptions[0]
+	a.descriptions = a.descriptions[1:]
+
+	return d
+}
+
+// The list methods return empty lists with room for n items. Their capacity
+// is capped at n, so appending more than that moves the list out of the arena
+// rather than overwriting its neighbours.
+
+func (a *describeArena) fieldList(n int) FieldList {
+	if a == nil || n == 0 {
+		return make(FieldList, 0, n)
+	}
+
+	if len(a.fields) < n {
+		a.fields = make([]Field, maxInt(n, describeArenaChunk))
+	}
+
+	r := a.fields[:0:n]
+	a.fields = a.fields[n:]
+
+	return r
+}
+
+func (a *describeArena) tagList(n int) TagList {
+	if a == nil || n == 0 {
+		return make(TagList, 0, n)
+	}
+
+	if len(a.tags) < n {
+		a.tags = make([]Tag, maxInt(n, describeArenaChunk))
+	}
+
+	r := a.tags[:0:n]
+	a.tags = a.tags[n:]
+
+	return r
+}
+
+func (a *describeArena) parameterList(n int) ParameterList {
+	if a == nil || n == 0 {
+		return make(ParameterList, 0, n)
+	}
+
+	if len(a.parameters) < n {
+		a.parameters = make([]Parameter, maxInt(n, describeArenaChunk))
+	}
+
+	r := a.parameters[:0:n]
+	a.parameters = a.parameters[n:]
+
+	return r
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// WithArena makes the descriptions built with these options share large
+// backing arrays for their fields, tags and parameters, instead of allocating
+// each list separately. It pays off when describing many types at once, as
+// with DescribeAll; the catch is that the arrays stay alive for as long as any
+// description built from them does.
+func WithArena() Option {
+	return func(o *options) {
+		o.arena = &describeArena{}
+	}
+}
+
+// DescribeAll describes each of inputs (anything GetDescription accepts) with
+// the same options, so that WithArena shares one arena across the batch. It
+// stops at the first input that can't be described.
+func DescribeAll(inputs []interface{}, opts ...Option) ([]*StructDescription, error) {
+	o := getOptions(opts)
+
+	r := make([]*StructDescription, len(inputs))
+
+	for i, input := range inputs {
+		d, err := getDescription(input, opts, o)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.DescribeAll: input %d (%T): %w", i, input, err)
+		}
+
+		r[i] = d
+	}
+
+	return r, nil
+}
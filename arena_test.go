@@ -0,0 +1,62 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type arenaTestA struct {
+	ID   string `json:"id,omitempty" db:"id,pk"`
+	Name string `json:"name"`
+}
+
+type arenaTestB struct {
+	arenaTestA
+	Tags []string `json:"tags,omitempty,alias:labels"`
+	Next *arenaTestB
+}
+
+func TestDescribeAll(t *testing.T) {
+	a := assert.New(t)
+
+	inputs := []interface{}{arenaTestA{}, &arenaTestB{}, reflect.TypeOf(arenaTestB{})}
+
+	plain, err := DescribeAll(inputs, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	arena, err := DescribeAll(inputs, WithArena(), WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	for i, d := range plain {
+		want, _ := d.MarshalText()
+		got, _ := arena[i].MarshalText()
+		a.Equal(string(want), string(got))
+	}
+
+	a.True(arena[2] == arena[2].Field("Next").Description())
+
+	fields := arena[0].Fields()
+	fields = append(fields, Field{name: "Extra"})
+	a.Equal([]string{"arenaTestA", "ID", "Name", "Tags", "Next"}, arena[1].Fields().Names())
+	a.Len(fields, 3)
+
+	_, err = DescribeAll([]interface{}{arenaTestA{}, 1})
+	a.ErrorContains(err, "input 1 (int)")
+}
+
+func TestArenaAllocations(t *testing.T) {
+	a := assert.New(t)
+
+	inputs := []interface{}{arenaTestA{}, arenaTestB{}, arenaTestA{}, arenaTestB{}, arenaTestA{}, arenaTestB{}}
+
+	plain := testing.AllocsPerRun(20, func() { _, _ = DescribeAll(inputs, WithoutCache()) })
+	arena := testing.AllocsPerRun(20, func() { _, _ = DescribeAll(inputs, WithArena()) })
+
+	a.Less(arena, plain)
+}
@@ -0,0 +1,167 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Attribute is a key/value pair for telemetry, such as an OpenTelemetry span
+// attribute. Value is always a bool, int64, float64 or string, or a slice of
+// one of those, so it can be passed to the matching attribute constructor.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns an Attribute for every field of v, a struct or pointer to
+// one, that has the named tag, e.g. `otel:"user.id"` or `log:"user_id"`. The
+// key is the tag value (or the field name), and a value of "-" leaves the field
+// out, as does an omitempty parameter when the field is empty. Tagged nested
+// structs contribute their own tagged fields, with keys prefixed by the nested
+// field's key and a dot. Sensitive fields are included with RedactionMask as
+// their value. Values that aren't one of the basic types are formatted as
+// strings, the same way they would be parsed, or with fmt otherwise.
+func Attributes(v interface{}, tag string) ([]Attribute, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Attributes: %w", err)
+	}
+
+	var r []Attribute
+	if err := attributes(&r, rv, tag, ""); err != nil {
+		return nil, fmt.Errorf("reflectutil.Attributes: %w", err)
+	}
+
+	return r, nil
+}
+
+func attributes(r *[]Attribute, rv reflect.Value, tag, prefix string) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		t := f.tags.Get(tag)
+		if !f.Exported() || t == nil || t.value == "-" {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (t.parameters.Has("omitempty") && fv.IsZero()) {
+			continue
+		}
+
+		key := f.name
+		if t.value != "" {
+			key = t.value
+		}
+		key = prefix + key
+
+		if f.Sensitive() {
+			*r = append(*r, Attribute{Key: key, Value: RedactionMask})
+			continue
+		}
+
+		if fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if isConfigSection(fv) {
+			if err := attributes(r, fv, tag, key+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		*r = append(*r, Attribute{Key: key, Value: attributeValue(f, fv)})
+	}
+
+	return nil
+}
+
+func attributeValue(f *Field, v reflect.Value) interface{} {
+	if v.Type() != durationType && !v.Type().Implements(textMarshalerType) {
+		switch v.Kind() {
+		case reflect.Bool:
+			return v.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if u := v.Uint(); u <= 1<<63-1 {
+				return int64(u)
+			}
+		case reflect.Float32, reflect.Float64:
+			return v.Float()
+		case reflect.String:
+			return v.String()
+		case reflect.Slice, reflect.Array:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				return attributeSlice(f, v)
+			}
+		}
+	}
+
+	if s, err := formatString(f, v); err == nil {
+		return s
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// attributeSlice converts the items of v with attributeValue, returning a
+// typed slice if they all have the same type and a []string otherwise.
+func attributeSlice(f *Field, v reflect.Value) interface{} {
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		e := v.Index(i)
+		if e.Kind() == reflect.Ptr || e.Kind() == reflect.Interface {
+			if !e.IsNil() {
+				items[i] = attributeValue(f, e.Elem())
+			}
+			continue
+		}
+		items[i] = attributeValue(f, e)
+	}
+
+	var typ reflect.Type
+	for _, e := range items {
+		if e == nil || (typ != nil && reflect.TypeOf(e) != typ) || reflect.TypeOf(e).Kind() == reflect.Slice {
+			typ = nil
+			break
+		}
+		typ = reflect.TypeOf(e)
+	}
+
+	if typ == nil {
+		return attributeStrings(items)
+	}
+
+	st, ok := sliceOf(typ)
+	if !ok {
+		return attributeStrings(items)
+	}
+
+	r := reflect.MakeSlice(st, len(items), len(items))
+	for i, e := range items {
+		r.Index(i).Set(reflect.ValueOf(e))
+	}
+
+	return r.Interface()
+}
+
+func attributeStrings(items []interface{}) []string {
+	r := make([]string, len(items))
+	for i, e := range items {
+		if e != nil {
+			r[i] = fmt.Sprint(e)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,68 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type attributesTestTenant struct {
+	ID   string `otel:"id"`
+	Plan string
+}
+
+type attributesTestRequest struct {
+	UserID   int                   `otel:"user.id"`
+	Admin    bool                  `otel:"user.admin"`
+	Score    float32               `otel:"score"`
+	Roles    []string              `otel:"roles"`
+	Sizes    []uint8               `otel:"sizes"`
+	Timeout  time.Duration         `otel:"timeout"`
+	Token    string                `otel:"token" sensitive:"true"`
+	Tenant   *attributesTestTenant `otel:"tenant"`
+	Note     string                `otel:"note,omitempty"`
+	Mixed    []interface{}         `otel:"mixed"`
+	Ignored  string                `otel:"-"`
+	Untagged string
+}
+
+func TestAttributes(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := Attributes(&attributesTestRequest{
+		UserID:   7,
+		Admin:    true,
+		Score:    0.5,
+		Roles:    []string{"a", "b"},
+		Sizes:    []uint8{1, 2},
+		Timeout:  time.Second,
+		Token:    "hunter2",
+		Tenant:   &attributesTestTenant{ID: "t1", Plan: "pro"},
+		Mixed:    []interface{}{1, "x"},
+		Ignored:  "x",
+		Untagged: "x",
+	}, "otel")
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]Attribute{
+		{Key: "user.id", Value: int64(7)},
+		{Key: "user.admin", Value: true},
+		{Key: "score", Value: float64(0.5)},
+		{Key: "roles", Value: []string{"a", "b"}},
+		{Key: "sizes", Value: "\x01\x02"},
+		{Key: "timeout", Value: "1s"},
+		{Key: "token", Value: RedactionMask},
+		{Key: "tenant.id", Value: "t1"},
+		{Key: "mixed", Value: []string{"1", "x"}},
+	}, r)
+
+	r, err = Attributes(attributesTestRequest{}, "log")
+	a.NoError(err)
+	a.Empty(r)
+
+	_, err = Attributes(1, "otel")
+	a.Error(err)
+}
@@ -0,0 +1,304 @@
+package reflectutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BinaryField is one entry in a struct's fixed binary layout.
+type BinaryField struct {
+	Field  string
+	Offset int
+	Len    int
+	Order  binary.ByteOrder
+
+	field *Field
+}
+
+// BinaryLayout describes the fixed byte layout of a struct, built from these
+// tags on its exported fields:
+//
+//	Magic   [4]byte
+//	Version uint16 `endian:"little"`
+//	Flags   uint8  `offset:"8"`
+//	Name    string `len:"16"`
+//	Pad     int    `binary:"-"`
+//
+// Fields are laid out in declaration order, each one starting where the last
+// ended unless it has an offset tag, so skipped bytes are left as gaps.
+// Numbers and bools take their natural size, byte arrays their length, and
+// strings and byte slices need a len tag. The byte order is big endian unless
+// an endian tag says "little".
+type BinaryLayout struct {
+	Fields []BinaryField
+	Size   int
+}
+
+// BinaryLayout returns the fixed binary layout of the described struct.
+func (s *StructDescription) BinaryLayout() (*BinaryLayout, error) {
+	l := &BinaryLayout{}
+
+	var errs []error
+
+	offset := 0
+
+	for i := range s.fields {
+		f := &s.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		if t := f.tags.Get("binary"); t != nil && t.value == "-" {
+			continue
+		}
+
+		b, err := binaryField(f, offset)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		l.Fields = append(l.Fields, b)
+
+		offset = b.Offset + b.Len
+		if offset > l.Size {
+			l.Size = offset
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.StructDescription.BinaryLayout: %w", err)
+	}
+
+	return l, nil
+}
+
+func binaryField(f *Field, offset int) (BinaryField, error) {
+	b := BinaryField{Field: f.name, Offset: offset, Order: binary.BigEndian, field: f}
+
+	if t := f.tags.Get("offset"); t != nil {
+		n, err := strconv.Atoi(t.value)
+		if err != nil || n < 0 {
+			return b, fmt.Errorf("invalid offset %q", t.value)
+		}
+		b.Offset = n
+	}
+
+	if t := f.tags.Get("endian"); t != nil {
+		switch strings.ToLower(t.value) {
+		case "big":
+		case "little":
+			b.Order = binary.LittleEndian
+		default:
+			return b, fmt.Errorf("invalid endian %q", t.value)
+		}
+	}
+
+	typ := f.typ
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		b.Len = 1
+	case reflect.Int16, reflect.Uint16:
+		b.Len = 2
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		b.Len = 4
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		b.Len = 8
+	case reflect.Array:
+		if typ.Elem().Kind() != reflect.Uint8 {
+			return b, fmt.Errorf("can't lay out %s", typ)
+		}
+		b.Len = typ.Len()
+	case reflect.String, reflect.Slice:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+			return b, fmt.Errorf("can't lay out %s", typ)
+		}
+		t := f.tags.Get("len")
+		if t == nil {
+			return b, fmt.Errorf("%s needs a len tag", typ)
+		}
+		n, err := strconv.Atoi(t.value)
+		if err != nil || n < 0 {
+			return b, fmt.Errorf("invalid len %q", t.value)
+		}
+		b.Len = n
+	default:
+		return b, fmt.Errorf("can't lay out %s", typ)
+	}
+
+	if t := f.tags.Get("len"); t != nil && typ.Kind() != reflect.String && typ.Kind() != reflect.Slice {
+		if n, err := strconv.Atoi(t.value); err != nil || n != b.Len {
+			return b, fmt.Errorf("len %q doesn't match the size of %s", t.value, typ)
+		}
+	}
+
+	return b, nil
+}
+
+// EncodeBinary returns v, a struct or pointer to one, encoded using its
+// binary layout. Gaps are filled with zeros, as are strings and byte slices
+// shorter than their len; longer ones are an error.
+func EncodeBinary(v interface{}) ([]byte, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.EncodeBinary: %w", err)
+	}
+
+	l, err := binaryLayout(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.EncodeBinary: %w", err)
+	}
+
+	r := make([]byte, l.Size)
+
+	var errs []error
+
+	for _, b := range l.Fields {
+		fv, ok := fieldValue(rv, b.field.index)
+		if !ok {
+			continue
+		}
+
+		if err := encodeBinaryField(r[b.Offset:b.Offset+b.Len], fv, b.Order); err != nil {
+			errs = append(errs, &FieldError{Field: b.Field, Err: err})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.EncodeBinary: %w", err)
+	}
+
+	return r, nil
+}
+
+func encodeBinaryField(dst []byte, v reflect.Value, order binary.ByteOrder) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			dst[0] = 1
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		putBinaryUint(dst, uint64(v.Int()), order)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		putBinaryUint(dst, v.Uint(), order)
+	case reflect.Float32:
+		putBinaryUint(dst, uint64(math.Float32bits(float32(v.Float()))), order)
+	case reflect.Float64:
+		putBinaryUint(dst, math.Float64bits(v.Float()), order)
+	case reflect.Array:
+		// byte by byte, since the element type may be a named byte type
+		for i := range dst {
+			dst[i] = byte(v.Index(i).Uint())
+		}
+	case reflect.String, reflect.Slice:
+		if v.Len() > len(dst) {
+			return fmt.Errorf("%d bytes don't fit in %d", v.Len(), len(dst))
+		}
+		if v.Kind() == reflect.String {
+			copy(dst, v.String())
+		} else {
+			copy(dst, v.Bytes())
+		}
+	}
+
+	return nil
+}
+
+func putBinaryUint(dst []byte, n uint64, order binary.ByteOrder) {
+	switch len(dst) {
+	case 1:
+		dst[0] = byte(n)
+	case 2:
+		order.PutUint16(dst, uint16(n))
+	case 4:
+		order.PutUint32(dst, uint32(n))
+	case 8:
+		order.PutUint64(dst, n)
+	}
+}
+
+// DecodeBinary fills v, which must be a pointer to a struct, from data using
+// its binary layout. Trailing zero bytes are trimmed from strings, but byte
+// slices are kept as they are. data must be at least as long as the layout.
+func DecodeBinary(data []byte, v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.DecodeBinary: %w", err)
+	}
+
+	l, err := binaryLayout(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.DecodeBinary: %w", err)
+	}
+
+	if len(data) < l.Size {
+		return fmt.Errorf("reflectutil.DecodeBinary: got %d bytes; layout needs %d", len(data), l.Size)
+	}
+
+	var errs []error
+
+	for _, b := range l.Fields {
+		fv, err := fieldByIndexAlloc(b.field, rv)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: b.Field, Err: err})
+			continue
+		}
+
+		decodeBinaryField(fv, data[b.Offset:b.Offset+b.Len], b.Order)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.DecodeBinary: %w", err)
+	}
+
+	return nil
+}
+
+func decodeBinaryField(v reflect.Value, src []byte, order binary.ByteOrder) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(src[0] != 0)
+	case reflect.Int8:
+		v.SetInt(int64(int8(src[0])))
+	case reflect.Int16:
+		v.SetInt(int64(int16(order.Uint16(src))))
+	case reflect.Int32:
+		v.SetInt(int64(int32(order.Uint32(src))))
+	case reflect.Int64:
+		v.SetInt(int64(order.Uint64(src)))
+	case reflect.Uint8:
+		v.SetUint(uint64(src[0]))
+	case reflect.Uint16:
+		v.SetUint(uint64(order.Uint16(src)))
+	case reflect.Uint32:
+		v.SetUint(uint64(order.Uint32(src)))
+	case reflect.Uint64:
+		v.SetUint(order.Uint64(src))
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(order.Uint32(src))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(order.Uint64(src)))
+	case reflect.Array:
+		for i, c := range src {
+			v.Index(i).SetUint(uint64(c))
+		}
+	case reflect.String:
+		v.SetString(strings.TrimRight(string(src), "\x00"))
+	case reflect.Slice:
+		v.SetBytes(append([]byte(nil), src...))
+	}
+}
+
+func binaryLayout(typ reflect.Type) (*BinaryLayout, error) {
+	d, err := GetDescription(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.BinaryLayout()
+}
@@ -0,0 +1,132 @@
+package reflectutil
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type binaryTestHeader struct {
+	Magic   [4]byte
+	Version uint16 `endian:"little"`
+	Length  int32
+	Flags   uint8   `offset:"12"`
+	Ratio   float32 `endian:"little"`
+	Name    string  `len:"6"`
+	Payload []byte  `len:"2"`
+	OK      bool
+	Note    string `binary:"-"`
+	hidden  int
+}
+
+func TestBinaryLayout(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(binaryTestHeader{})
+	if !a.NoError(err) {
+		return
+	}
+
+	l, err := d.BinaryLayout()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(26, l.Size)
+
+	var offsets, lens []int
+	for _, f := range l.Fields {
+		offsets = append(offsets, f.Offset)
+		lens = append(lens, f.Len)
+	}
+	a.Equal([]int{0, 4, 6, 12, 13, 17, 23, 25}, offsets)
+	a.Equal([]int{4, 2, 4, 1, 4, 6, 2, 1}, lens)
+	a.Equal(binary.LittleEndian, l.Fields[1].Order)
+	a.Equal(binary.BigEndian, l.Fields[2].Order)
+
+	d, err = GetDescription(struct {
+		A string
+		B int
+		C uint16 `endian:"middle"`
+		D uint16 `len:"4"`
+		E []int  `len:"4"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, err = d.BinaryLayout()
+	a.ErrorContains(err, "A: string needs a len tag")
+	a.ErrorContains(err, "B: can't lay out int")
+	a.ErrorContains(err, `C: invalid endian "middle"`)
+	a.ErrorContains(err, `D: len "4" doesn't match the size of uint16`)
+	a.ErrorContains(err, "E: can't lay out []int")
+}
+
+func TestEncodeDecodeBinary(t *testing.T) {
+	a := assert.New(t)
+
+	v := binaryTestHeader{
+		Magic:   [4]byte{'R', 'U', 'T', 'L'},
+		Version: 2,
+		Length:  -2,
+		Flags:   0x81,
+		Ratio:   1,
+		Name:    "abc",
+		Payload: []byte{9, 8},
+		OK:      true,
+		Note:    "x",
+	}
+
+	b, err := EncodeBinary(&v)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]byte{
+		'R', 'U', 'T', 'L',
+		2, 0,
+		0xff, 0xff, 0xff, 0xfe,
+		0, 0,
+		0x81,
+		0, 0, 0x80, 0x3f,
+		'a', 'b', 'c', 0, 0, 0,
+		9, 8,
+		1,
+	}, b)
+
+	var w binaryTestHeader
+	a.NoError(DecodeBinary(b, &w))
+	v.Note = ""
+	a.Equal(v, w)
+
+	a.EqualError(DecodeBinary(b[:10], &w), "reflectutil.DecodeBinary: got 10 bytes; layout needs 26")
+
+	_, err = EncodeBinary(binaryTestHeader{Name: "too long"})
+	a.ErrorContains(err, "Name: 8 bytes don't fit in 6")
+}
+
+func TestEncodeDecodeBinaryNamedBytes(t *testing.T) {
+	a := assert.New(t)
+
+	type B byte
+
+	type T struct {
+		Magic [4]B
+		Data  []B `len:"2"`
+	}
+
+	in := T{Magic: [4]B{'a', 'b', 'c', 'd'}, Data: []B{1, 2}}
+
+	data, err := EncodeBinary(in)
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal([]byte{'a', 'b', 'c', 'd', 1, 2}, data)
+
+	var out T
+	if a.NoError(DecodeBinary(data, &out)) {
+		a.Equal(in, out)
+	}
+}
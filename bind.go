@@ -0,0 +1,284 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// BindSource identifies the part of an HTTP request that a field is bound
+// from. Its value is also the name of the tag that selects it.
+type BindSource string
+
+const (
+	BindPath   BindSource = "path"
+	BindQuery  BindSource = "query"
+	BindForm   BindSource = "form"
+	BindHeader BindSource = "header"
+	BindCookie BindSource = "cookie"
+	BindFile   BindSource = "file"
+)
+
+// BindSources lists the sources BindRequest reads from, in the order they're
+// consulted when a field has more than one source tag.
+var BindSources = []BindSource{BindPath, BindQuery, BindForm, BindHeader, BindCookie, BindFile}
+
+// BindError reports a value that couldn't be bound to a field, along with
+// where in the request it came from.
+type BindError struct {
+	Source BindSource
+	Key    string
+	Field  string
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s %q: %s", e.Source, e.Key, e.Err.Error())
+	}
+
+	return fmt.Sprintf("%s (%s %q): %s", e.Field, e.Source, e.Key, e.Err.Error())
+}
+
+func (e *BindError) Unwrap() error { return e.Err }
+
+// BindSource returns the first source in BindSources that the field has a tag
+// for, along with the key to look up. The key defaults to the field name, and
+// a tag value of "-" excludes that source.
+func (f *Field) BindSource() (BindSource, string, bool) {
+	for _, s := range BindSources {
+		t := f.tags.Get(string(s))
+		if t == nil || t.value == "-" {
+			continue
+		}
+
+		if t.value == "" {
+			return s, f.name, true
+		}
+
+		return s, t.value, true
+	}
+
+	return "", "", false
+}
+
+// BindRequest fills v, which must be a pointer to a struct, from r. Fields are
+// bound according to their path, query, form, header, cookie, or file tags,
+// with path parameters supplied by the caller's router. Values are parsed the
+// same way as everywhere else in this package; repeated values are bound to
+// slice fields one item at a time. File fields take uploads from multipart
+// requests as *multipart.FileHeader or []byte (or slices of those); their
+// max parameters are only checked once the body has been read, so the size of
+// the body itself has to be limited by the caller (see MaxFileSize). Missing
+// values leave fields untouched, and every failure is reported as a
+// *BindError. Path, query and form keys that no field asked for are subject
+// to WithUnknownKeyPolicy, and are collected as e.g. "query.page"; values for
+// read only fields are subject to WithReadOnlyPolicy.
+func BindRequest(r *http.Request, params map[string]string, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.BindRequest: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.BindRequest: %w", err)
+	}
+
+	o := getOptions(opts)
+
+	b := requestBinder{r: r, params: params, used: make(map[BindSource]map[string]bool)}
+
+	var errs []error
+
+	for _, f := range d.fields {
+		if !f.Exported() {
+			continue
+		}
+
+		source, key, ok := f.BindSource()
+		if !ok {
+			continue
+		}
+
+		var values []string
+		var files []*multipart.FileHeader
+		if source == BindFile {
+			files, err = b.files(key)
+		} else {
+			values, err = b.values(source, key)
+		}
+		if err != nil {
+			return fmt.Errorf("reflectutil.BindRequest: %w", err)
+		}
+		if len(values) == 0 && len(files) == 0 {
+			continue
+		}
+
+		if ok, err := o.readOnly(&f); !ok {
+			if err != nil {
+				errs = append(errs, &BindError{Source: source, Key: key, Field: f.name, Err: err})
+			}
+			continue
+		}
+
+		fv, err := fieldByIndexAlloc(&f, rv)
+		if err == nil && files != nil {
+			err = bindFile(&f, fv, files)
+		} else if err == nil {
+			err = setFromStrings(&f, fv, values)
+		}
+		if err != nil {
+			errs = append(errs, &BindError{Source: source, Key: key, Field: f.name, Err: err})
+			continue
+		}
+
+		o.record(f.name, key, string(source))
+	}
+
+	if err := b.unknownKeys(o); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.BindRequest: %w", err)
+	}
+
+	return nil
+}
+
+type requestBinder struct {
+	r      *http.Request
+	params map[string]string
+	query  url.Values
+	parsed bool
+	used   map[BindSource]map[string]bool
+}
+
+func (b *requestBinder) values(source BindSource, key string) ([]string, error) {
+	if b.used[source] == nil {
+		b.used[source] = make(map[string]bool)
+	}
+	b.used[source][key] = true
+
+	switch source {
+	case BindPath:
+		if s, ok := b.params[key]; ok {
+			return []string{s}, nil
+		}
+	case BindQuery:
+		if b.query == nil {
+			b.query = b.r.URL.Query()
+		}
+		return b.query[key], nil
+	case BindForm:
+		if err := b.parseForm(); err != nil {
+			return nil, err
+		}
+		return b.r.PostForm[key], nil
+	case BindHeader:
+		return b.r.Header.Values(key), nil
+	case BindCookie:
+		if c, err := b.r.Cookie(key); err == nil {
+			return []string{c.Value}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *requestBinder) unknownKeys(o *options) error {
+	if o.unknownKeys == UnknownKeysIgnore {
+		return nil
+	}
+
+	if b.query == nil {
+		b.query = b.r.URL.Query()
+	}
+
+	if err := b.parseForm(); err != nil {
+		return err
+	}
+
+	sources := []struct {
+		source BindSource
+		values interface{}
+	}{
+		{BindPath, b.params},
+		{BindQuery, b.query},
+		{BindForm, b.r.PostForm},
+	}
+	if b.r.MultipartForm != nil {
+		sources = append(sources, struct {
+			source BindSource
+			values interface{}
+		}{BindFile, b.r.MultipartForm.File})
+	}
+
+	var errs []error
+
+	for _, s := range sources {
+		for _, k := range unusedKeys(s.values, b.used[s.source]) {
+			if o.unknownKeys == UnknownKeysError {
+				errs = append(errs, &BindError{Source: s.source, Key: k, Err: ErrUnknownKey})
+			} else {
+				_ = o.forKey(string(s.source), "").unknownKey(k)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *requestBinder) files(key string) ([]*multipart.FileHeader, error) {
+	if b.used[BindFile] == nil {
+		b.used[BindFile] = make(map[string]bool)
+	}
+	b.used[BindFile][key] = true
+
+	if err := b.parseForm(); err != nil {
+		return nil, err
+	}
+
+	if b.r.MultipartForm == nil {
+		return nil, nil
+	}
+
+	return b.r.MultipartForm.File[key], nil
+}
+
+func (b *requestBinder) parseForm() error {
+	if b.parsed {
+		return nil
+	}
+
+	b.parsed = true
+
+	if err := b.r.ParseMultipartForm(DefaultMultipartMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+
+	return nil
+}
+
+// setFromStrings is like setFromString, but binds repeated values to slices
+// one item each. A single value is passed through, so it can still be split
+// as a list.
+func setFromStrings(f *Field, v reflect.Value, values []string) error {
+	if len(values) == 1 || v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return setFromString(f, v, values[len(values)-1])
+	}
+
+	r := reflect.MakeSlice(v.Type(), len(values), len(values))
+	for i, s := range values {
+		if err := setFromString(f, r.Index(i), s); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	v.Set(r)
+
+	return nil
+}
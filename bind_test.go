@@ -0,0 +1,95 @@
+package reflectutil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindTestRequest struct {
+	OrgID   int      `path:"org_id"`
+	Page    int      `query:"page"`
+	Tags    []string `query:"tag"`
+	Name    string   `form:"name"`
+	Token   string   `header:"X-Token"`
+	Session string   `cookie:"session"`
+	Either  string   `path:"-" query:"either"`
+	Limit   *int     `query:"limit"`
+	Ignored string
+}
+
+func TestBindSource(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(bindTestRequest{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field  string
+		source BindSource
+		key    string
+		ok     bool
+	}{
+		{"OrgID", BindPath, "org_id", true},
+		{"Token", BindHeader, "X-Token", true},
+		{"Either", BindQuery, "either", true},
+		{"Ignored", "", "", false},
+	} {
+		source, key, ok := d.Field(tc.field).BindSource()
+		a.Equal(tc.source, source, tc.field)
+		a.Equal(tc.key, key, tc.field)
+		a.Equal(tc.ok, ok, tc.field)
+	}
+}
+
+func TestBindRequest(t *testing.T) {
+	a := assert.New(t)
+
+	r := httptest.NewRequest("POST", "/orgs/7?page=2&tag=a&tag=b&either=q&limit=5", strings.NewReader("name=Jo"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Token", "secret")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	var v bindTestRequest
+	if !a.NoError(BindRequest(r, map[string]string{"org_id": "7"}, &v)) {
+		return
+	}
+
+	limit := 5
+	a.Equal(bindTestRequest{
+		OrgID:   7,
+		Page:    2,
+		Tags:    []string{"a", "b"},
+		Name:    "Jo",
+		Token:   "secret",
+		Session: "abc",
+		Either:  "q",
+		Limit:   &limit,
+	}, v)
+}
+
+func TestBindRequestErrors(t *testing.T) {
+	a := assert.New(t)
+
+	r := httptest.NewRequest("GET", "/?page=x", nil)
+	r.Header.Set("X-Token", "t")
+
+	var v bindTestRequest
+	err := BindRequest(r, map[string]string{"org_id": "y"}, &v)
+	a.ErrorContains(err, `OrgID (path "org_id")`)
+	a.ErrorContains(err, `Page (query "page")`)
+	a.Equal("t", v.Token)
+
+	var be *BindError
+	if a.True(errors.As(err, &be)) {
+		a.Equal(BindPath, be.Source)
+	}
+
+	a.Error(BindRequest(r, nil, v))
+}
@@ -0,0 +1,219 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strconv"
+)
+
+// BitField describes where a field lives within a packed integer: its value
+// is (u >> Offset) & Mask.
+type BitField struct {
+	Offset int
+	Mask   uint64
+}
+
+// Bits returns the field's position within a packed integer, given by a bit
+// tag with the number of its lowest bit and a mask tag with the bits it
+// covers. With only a bit tag (`bit:"3"`) the field is a single bit; with only
+// a mask tag (`mask:"0xF0"`) it covers the set bits of the mask; with both
+// (`bit:"4" mask:"0x0F"`) the mask is shifted up by the bit number.
+func (f *Field) Bits() (BitField, bool, error) {
+	bt, mt := f.tags.Get("bit"), f.tags.Get("mask")
+	if bt == nil && mt == nil {
+		return BitField{}, false, nil
+	}
+
+	b := BitField{Mask: 1}
+
+	if mt != nil {
+		m, err := strconv.ParseUint(mt.value, 0, 64)
+		if err != nil || m == 0 {
+			return BitField{}, false, fmt.Errorf("reflectutil.Field.Bits: invalid mask %q for field %s", mt.value, f.name)
+		}
+
+		b.Mask = m
+		if bt == nil {
+			b.Offset = bits.TrailingZeros64(m)
+			b.Mask = m >> b.Offset
+		}
+	}
+
+	if bt != nil {
+		n, err := strconv.Atoi(bt.value)
+		if err != nil || n < 0 || n > 63 {
+			return BitField{}, false, fmt.Errorf("reflectutil.Field.Bits: invalid bit %q for field %s", bt.value, f.name)
+		}
+
+		if bits.Len64(b.Mask)+n > 64 {
+			return BitField{}, false, fmt.Errorf("reflectutil.Field.Bits: mask 0x%x at bit %d doesn't fit in 64 bits for field %s", b.Mask, n, f.name)
+		}
+
+		b.Offset = n
+	}
+
+	return b, true, nil
+}
+
+type packedField struct {
+	field *Field
+	bits  BitField
+}
+
+// packedFields returns the fields of d with bit or mask tags, making sure
+// none of them overlap.
+func packedFields(d *StructDescription) ([]packedField, error) {
+	var r []packedField
+
+	var errs []error
+
+	var claimed uint64
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		b, ok, err := f.Bits()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		switch derefType(f.typ).Kind() {
+		case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			errs = append(errs, &FieldError{Field: f.name, Err: fmt.Errorf("can't pack %s into bits", f.typ)})
+			continue
+		}
+
+		m := b.Mask << b.Offset
+		if claimed&m != 0 {
+			errs = append(errs, &FieldError{Field: f.name, Err: fmt.Errorf("bits 0x%x overlap another field", m)})
+			continue
+		}
+		claimed |= m
+
+		r = append(r, packedField{field: f, bits: b})
+	}
+
+	return r, errors.Join(errs...)
+}
+
+// PackBits returns the fields of v with bit or mask tags packed into a single
+// integer. Bool fields set their lowest bit when true; integer fields must be
+// non-negative and fit within their mask. Nil pointers pack as zero.
+func PackBits(v interface{}) (uint64, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.PackBits: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.PackBits: %w", err)
+	}
+
+	fields, err := packedFields(d)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.PackBits: %w", err)
+	}
+
+	var u uint64
+
+	var errs []error
+
+	for _, e := range fields {
+		fv, ok := fieldValue(rv, e.field.index)
+		if !ok {
+			continue
+		}
+
+		n, err := packedValue(reflect.Indirect(fv), e.bits)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: e.field.name, Err: err})
+			continue
+		}
+
+		u |= n << e.bits.Offset
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return 0, fmt.Errorf("reflectutil.PackBits: %w", err)
+	}
+
+	return u, nil
+}
+
+func packedValue(v reflect.Value, b BitField) (uint64, error) {
+	if !v.IsValid() {
+		return 0, nil
+	}
+
+	var n uint64
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			n = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("can't pack negative value %d", v.Int())
+		}
+		n = uint64(v.Int())
+	default:
+		n = v.Uint()
+	}
+
+	if n&^b.Mask != 0 {
+		return 0, fmt.Errorf("value %d doesn't fit in mask 0x%x", n, b.Mask)
+	}
+
+	return n, nil
+}
+
+// UnpackBits sets the fields of dest, which must be a pointer to a struct,
+// that have bit or mask tags from the packed integer u.
+func UnpackBits(u uint64, dest interface{}) error {
+	rv, err := settableStructValue(dest)
+	if err != nil {
+		return fmt.Errorf("reflectutil.UnpackBits: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.UnpackBits: %w", err)
+	}
+
+	fields, err := packedFields(d)
+	if err != nil {
+		return fmt.Errorf("reflectutil.UnpackBits: %w", err)
+	}
+
+	var errs []error
+
+	for _, e := range fields {
+		var n interface{} = (u >> e.bits.Offset) & e.bits.Mask
+		if derefType(e.field.typ).Kind() == reflect.Bool {
+			n = n != uint64(0)
+		}
+
+		fv, err := fieldByIndexAlloc(e.field, rv)
+		if err == nil {
+			err = assignValue(e.field, fv, n, nil, nil)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: e.field.name, Err: err})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.UnpackBits: %w", err)
+	}
+
+	return nil
+}
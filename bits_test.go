@@ -0,0 +1,107 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bitsTestRegister struct {
+	Enabled bool  `bit:"0"`
+	Ready   bool  `bit:"1"`
+	Mode    uint8 `mask:"0x0C"`
+	Channel int   `bit:"4" mask:"0x0F"`
+	Level   *uint `bit:"8" mask:"0x3"`
+	Name    string
+}
+
+func TestFieldBits(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(bitsTestRegister{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field string
+		bits  BitField
+		ok    bool
+	}{
+		{"Enabled", BitField{Offset: 0, Mask: 1}, true},
+		{"Ready", BitField{Offset: 1, Mask: 1}, true},
+		{"Mode", BitField{Offset: 2, Mask: 3}, true},
+		{"Channel", BitField{Offset: 4, Mask: 0xF}, true},
+		{"Name", BitField{}, false},
+	} {
+		b, ok, err := d.Field(tc.field).Bits()
+		a.NoError(err, tc.field)
+		a.Equal(tc.ok, ok, tc.field)
+		a.Equal(tc.bits, b, tc.field)
+	}
+
+	d, err = GetDescription(struct {
+		A int `bit:"64"`
+		B int `mask:"zz"`
+		C int `bit:"62" mask:"0x7"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, _, err = d.Field("A").Bits()
+	a.EqualError(err, `reflectutil.Field.Bits: invalid bit "64" for field A`)
+	_, _, err = d.Field("B").Bits()
+	a.EqualError(err, `reflectutil.Field.Bits: invalid mask "zz" for field B`)
+	_, _, err = d.Field("C").Bits()
+	a.EqualError(err, `reflectutil.Field.Bits: mask 0x7 at bit 62 doesn't fit in 64 bits for field C`)
+}
+
+func TestPackBits(t *testing.T) {
+	a := assert.New(t)
+
+	level := uint(2)
+
+	u, err := PackBits(bitsTestRegister{Enabled: true, Mode: 2, Channel: 9, Level: &level, Name: "x"})
+	a.NoError(err)
+	a.Equal(uint64(0x299), u)
+
+	u, err = PackBits(&bitsTestRegister{Ready: true})
+	a.NoError(err)
+	a.Equal(uint64(0x2), u)
+
+	_, err = PackBits(bitsTestRegister{Channel: 16})
+	a.ErrorContains(err, "Channel: value 16 doesn't fit in mask 0xf")
+
+	_, err = PackBits(bitsTestRegister{Channel: -1})
+	a.ErrorContains(err, "Channel: can't pack negative value -1")
+
+	_, err = PackBits(struct {
+		A bool   `bit:"1"`
+		B int    `mask:"0x6"`
+		C string `bit:"4"`
+	}{})
+	a.ErrorContains(err, "B: bits 0x6 overlap another field")
+	a.ErrorContains(err, "C: can't pack string into bits")
+}
+
+func TestUnpackBits(t *testing.T) {
+	a := assert.New(t)
+
+	var v bitsTestRegister
+	a.NoError(UnpackBits(0x299, &v))
+
+	level := uint(2)
+	a.Equal(bitsTestRegister{Enabled: true, Mode: 2, Channel: 9, Level: &level}, v)
+
+	u, err := PackBits(v)
+	a.NoError(err)
+	a.Equal(uint64(0x299), u)
+
+	var w struct {
+		Small uint8 `mask:"0x1FF"`
+	}
+	a.ErrorContains(UnpackBits(0x1FF, &w), "Small: 511 overflows uint8")
+
+	a.Error(UnpackBits(0, v))
+}
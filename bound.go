@@ -0,0 +1,96 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnexportedField is returned by BoundField.Get for unexported fields,
+// whose values can't be handed out through reflection.
+var ErrUnexportedField = errors.New("field is unexported")
+
+// BoundStruct pairs a description with a particular value of the described
+// type, for reading and writing its fields by name.
+type BoundStruct struct {
+	d *StructDescription
+	v reflect.Value
+}
+
+// Bind returns s bound to v, which must be a value of the described type or a
+// pointer to one. Fields can only be set through a pointer.
+func (s *StructDescription) Bind(v interface{}) (*BoundStruct, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.StructDescription.Bind: %w", err)
+	}
+
+	if rv.Type() != s.typ {
+		return nil, fmt.Errorf("reflectutil.StructDescription.Bind: input should be %s; got %s", s.typ, rv.Type())
+	}
+
+	return &BoundStruct{d: s, v: rv}, nil
+}
+
+// Description returns the description b was bound from.
+func (b *BoundStruct) Description() *StructDescription { return b.d }
+
+// Field returns the named field of the bound value. If there's no such field,
+// the returned BoundField's methods all fail with ErrUnknownKey.
+func (b *BoundStruct) Field(name string) *BoundField {
+	return &BoundField{name: name, field: b.d.Field(name), v: b.v}
+}
+
+// BoundField is a single field of a BoundStruct.
+type BoundField struct {
+	name  string
+	field *Field
+	v     reflect.Value
+}
+
+// Field returns the field's description, or nil if there's no such field.
+func (b *BoundField) Field() *Field { return b.field }
+
+// Get returns the field's value. Fields promoted through nil embedded pointers
+// read as their type's zero value.
+func (b *BoundField) Get() (interface{}, error) {
+	if b.field == nil {
+		return nil, fmt.Errorf("reflectutil.BoundField.Get: %w", &FieldError{Field: b.name, Err: ErrUnknownKey})
+	}
+
+	if !b.field.Exported() {
+		return nil, fmt.Errorf("reflectutil.BoundField.Get: %w", &FieldError{Field: b.name, Err: ErrUnexportedField})
+	}
+
+	fv, ok := fieldValue(b.v, b.field.index)
+	if !ok {
+		fv = reflect.Zero(b.field.typ)
+	}
+
+	return fv.Interface(), nil
+}
+
+// Set stores x in the field, converted to the field's type by the same rules
+// as Convert. Nil embedded pointers on the way to the field are allocated.
+func (b *BoundField) Set(x interface{}) error {
+	if b.field == nil {
+		return fmt.Errorf("reflectutil.BoundField.Set: %w", &FieldError{Field: b.name, Err: ErrUnknownKey})
+	}
+
+	if !b.v.CanSet() {
+		return fmt.Errorf("reflectutil.BoundField.Set: %w", &FieldError{Field: b.name, Err: &SetError{Reason: SetPassedByValue}})
+	}
+
+	fv, err := fieldByIndexAlloc(b.field, b.v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.BoundField.Set: %w", &FieldError{Field: b.name, Err: err})
+	}
+
+	fromMap := func(m map[string]interface{}, v reflect.Value, o *options) error { return setFields(v, m, "", o) }
+
+	if err := assignValue(b.field, fv, x, fromMap, getOptions(nil)); err != nil {
+		return fmt.Errorf("reflectutil.BoundField.Set: %w", &FieldError{Field: b.name, Err: err})
+	}
+
+	return nil
+}
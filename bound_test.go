@@ -0,0 +1,104 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type boundTestUser struct {
+	Name    string
+	Age     int
+	private string
+}
+
+func TestBind(t *testing.T) {
+	a := assert.New(t)
+
+	type Base struct {
+		ID int
+	}
+
+	type User struct {
+		*Base
+		Name    string
+		Age     int
+		private string
+	}
+
+	d, err := GetDescription(User{})
+	if !a.NoError(err) {
+		return
+	}
+
+	var u User
+
+	b, err := d.Bind(&u)
+	if !a.NoError(err) {
+		return
+	}
+
+	id, err := b.Field("ID").Get()
+	a.NoError(err)
+	a.Equal(0, id)
+
+	a.NoError(b.Field("Name").Set("Jo"))
+	a.NoError(b.Field("Age").Set("42"))
+	a.NoError(b.Field("ID").Set(int64(7)))
+	a.Equal(User{Base: &Base{ID: 7}, Name: "Jo", Age: 42}, u)
+
+	name, err := b.Field("Name").Get()
+	a.NoError(err)
+	a.Equal("Jo", name)
+
+	a.Equal("Age", b.Field("Age").Field().Name())
+	a.Nil(b.Field("Nope").Field())
+
+	_, err = b.Field("Nope").Get()
+	a.True(errors.Is(err, ErrUnknownKey))
+	a.True(errors.Is(b.Field("Nope").Set(1), ErrUnknownKey))
+
+	_, err = b.Field("private").Get()
+	a.True(errors.Is(err, ErrUnexportedField))
+
+	var se *SetError
+	if a.True(errors.As(b.Field("private").Set("x"), &se)) {
+		a.Equal(SetUnexported, se.Reason)
+	}
+
+	a.Error(b.Field("Age").Set("old"))
+}
+
+func TestBindByValue(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(boundTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	b, err := d.Bind(boundTestUser{Name: "Jo"})
+	if !a.NoError(err) {
+		return
+	}
+
+	name, err := b.Field("Name").Get()
+	a.NoError(err)
+	a.Equal("Jo", name)
+
+	var se *SetError
+	if a.True(errors.As(b.Field("Name").Set("Al"), &se)) {
+		a.Equal(SetPassedByValue, se.Reason)
+	}
+}
+
+func TestBindWrongType(t *testing.T) {
+	d, err := GetDescription(boundTestUser{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = d.Bind(&selectorTestUser{})
+	assert.EqualError(t, err, "reflectutil.StructDescription.Bind: input should be reflectutil.boundTestUser; got reflectutil.selectorTestUser")
+}
@@ -0,0 +1,283 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BSONTag is the parsed form of a bson tag, following the conventions of the
+// MongoDB Go driver.
+type BSONTag struct {
+	// Name is the document key, defaulting to the lowercased field name.
+	Name      string
+	Skip      bool
+	OmitEmpty bool
+	MinSize   bool
+	Truncate  bool
+	Inline    bool
+}
+
+func (f *Field) BSONTag() BSONTag {
+	r := BSONTag{Name: strings.ToLower(f.name)}
+
+	t := f.tags.Get("bson")
+	if t == nil {
+		return r
+	}
+
+	if t.value == "-" && len(t.parameters) == 0 {
+		r.Skip = true
+		return r
+	}
+
+	if t.value != "" {
+		r.Name = t.value
+	}
+
+	r.OmitEmpty = t.parameters.Has("omitempty")
+	r.MinSize = t.parameters.Has("minsize")
+	r.Truncate = t.parameters.Has("truncate")
+	r.Inline = t.parameters.Has("inline")
+
+	return r
+}
+
+// ToBSONMap converts v, a struct or pointer to one, into a document map using
+// its bson tags. Nested structs become nested maps, and inline structs and
+// maps are flattened into their parent. The result can be converted directly
+// to bson.M.
+func ToBSONMap(v interface{}) (map[string]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToBSONMap: %w", err)
+	}
+
+	m := make(map[string]interface{})
+	if err := toBSONMap(rv, m, ""); err != nil {
+		return nil, fmt.Errorf("reflectutil.ToBSONMap: %w", err)
+	}
+
+	return m, nil
+}
+
+func toBSONMap(rv reflect.Value, m map[string]interface{}, prefix string) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range d.fields {
+		if len(f.index) != 1 || !f.Exported() {
+			continue
+		}
+
+		tag := f.BSONTag()
+		if tag.Skip {
+			continue
+		}
+
+		fv := rv.Field(f.index[0])
+
+		if tag.Inline {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+
+			switch fv.Kind() {
+			case reflect.Struct:
+				if err := toBSONMap(fv, m, prefix+f.name+"."); err != nil {
+					return err
+				}
+			case reflect.Map:
+				iter := fv.MapRange()
+				for iter.Next() {
+					m[fmt.Sprint(iter.Key().Interface())] = toBSONValue(iter.Value())
+				}
+			case reflect.Ptr:
+				// nil inline pointer
+			default:
+				return &FieldError{Field: prefix + f.name, Err: fmt.Errorf("can't inline a %s", fv.Type())}
+			}
+
+			continue
+		}
+
+		if tag.OmitEmpty && fv.IsZero() {
+			continue
+		}
+
+		m[tag.Name] = toBSONValue(fv)
+	}
+
+	return nil
+}
+
+func toBSONValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toBSONValue(v.Elem())
+	case reflect.Struct:
+		if v.Type() == timeType || !hasExportedFields(v.Type()) {
+			return v.Interface()
+		}
+
+		m := make(map[string]interface{})
+		if err := toBSONMap(v, m, ""); err != nil {
+			return v.Interface()
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+
+		if !isStructLike(v.Type().Elem()) {
+			return v.Interface()
+		}
+
+		r := make([]interface{}, v.Len())
+		for i := range r {
+			r[i] = toBSONValue(v.Index(i))
+		}
+		return r
+	default:
+		return v.Interface()
+	}
+}
+
+// FromBSONMap fills v, which must be a pointer to a struct, from a document
+// map using its bson tags. Values are converted to the field types where
+// possible, so int32 document values can fill int fields, nested maps can
+// fill structs, and so on. Keys that don't match any field go to an inline
+// map field, if there is one, and are otherwise subject to
+// WithUnknownKeyPolicy.
+func FromBSONMap(m map[string]interface{}, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromBSONMap: %w", err)
+	}
+
+	if err := fromBSONMap(m, rv, "", getOptions(opts)); err != nil {
+		return fmt.Errorf("reflectutil.FromBSONMap: %w", err)
+	}
+
+	return nil
+}
+
+func fromBSONMap(m map[string]interface{}, rv reflect.Value, prefix string, o *options) error {
+	used := make(map[string]bool)
+	if err := fromBSONMapFields(m, rv, prefix, used, o); err != nil {
+		return err
+	}
+
+	if err := fillBSONInlineMap(m, rv, used, o); err != nil {
+		return err
+	}
+
+	return unknownKeys(o, m, used)
+}
+
+func fromBSONMapFields(m map[string]interface{}, rv reflect.Value, prefix string, used map[string]bool, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range d.fields {
+		if len(f.index) != 1 || !f.Exported() {
+			continue
+		}
+
+		tag := f.BSONTag()
+		if tag.Skip {
+			continue
+		}
+
+		fv := rv.Field(f.index[0])
+
+		if tag.Inline {
+			if fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				if err := fromBSONMapFields(m, fv, prefix+f.name+".", used, o); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		value, ok := m[tag.Name]
+		if !ok {
+			continue
+		}
+
+		used[tag.Name] = true
+
+		if err := assignValue(&f, fv, value, bsonStructFromMap, o.forKey(tag.Name, prefix+f.name)); err != nil {
+			return &FieldError{Field: prefix + f.name, Err: err}
+		}
+
+		o.record(prefix+f.name, tag.Name, "bson")
+	}
+
+	return nil
+}
+
+func fillBSONInlineMap(m map[string]interface{}, rv reflect.Value, used map[string]bool, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range d.fields {
+		if len(f.index) != 1 || !f.Exported() || !f.BSONTag().Inline {
+			continue
+		}
+
+		fv := rv.Field(f.index[0])
+
+		switch {
+		case fv.Kind() == reflect.Map && fv.Type().Key().Kind() == reflect.String:
+			for k, value := range m {
+				if used[k] {
+					continue
+				}
+
+				if fv.IsNil() {
+					fv.Set(reflect.MakeMap(fv.Type()))
+				}
+
+				ev := reflect.New(fv.Type().Elem()).Elem()
+				if err := assignValue(nil, ev, value, bsonStructFromMap, o); err != nil {
+					return &FieldError{Field: f.name + "." + k, Err: err}
+				}
+				fv.SetMapIndex(reflect.ValueOf(k).Convert(fv.Type().Key()), ev)
+				used[k] = true
+			}
+		case fv.Kind() == reflect.Struct:
+			if err := fillBSONInlineMap(m, fv, used, o); err != nil {
+				return err
+			}
+		case fv.Kind() == reflect.Ptr && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct:
+			if err := fillBSONInlineMap(m, fv.Elem(), used, o); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func bsonStructFromMap(m map[string]interface{}, v reflect.Value, o *options) error {
+	return fromBSONMap(m, v, "", o)
+}
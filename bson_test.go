@@ -0,0 +1,114 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bsonTestAudit struct {
+	CreatedBy string `bson:"created_by"`
+}
+
+type bsonTestAddress struct {
+	City string `bson:"city"`
+}
+
+type bsonTestUser struct {
+	ID         string                 `bson:"_id,omitempty"`
+	Name       string                 `bson:"name"`
+	Age        int                    `bson:",minsize"`
+	Skipped    string                 `bson:"-"`
+	Audit      bsonTestAudit          `bson:",inline"`
+	Address    *bsonTestAddress       `bson:"address,omitempty"`
+	Previous   []bsonTestAddress      `bson:"previous"`
+	Joined     time.Time              `bson:"joined"`
+	Extra      map[string]interface{} `bson:",inline"`
+	Untagged   bool
+	unexported string
+}
+
+func TestBSONTag(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(bsonTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(BSONTag{Name: "_id", OmitEmpty: true}, d.Field("ID").BSONTag())
+	a.Equal(BSONTag{Name: "age", MinSize: true}, d.Field("Age").BSONTag())
+	a.Equal(BSONTag{Name: "skipped", Skip: true}, d.Field("Skipped").BSONTag())
+	a.Equal(BSONTag{Name: "audit", Inline: true}, d.Field("Audit").BSONTag())
+	a.Equal(BSONTag{Name: "untagged"}, d.Field("Untagged").BSONTag())
+}
+
+func TestBSONMap(t *testing.T) {
+	a := assert.New(t)
+
+	joined := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	u := bsonTestUser{
+		Name:       "Jo",
+		Age:        30,
+		Skipped:    "x",
+		Audit:      bsonTestAudit{CreatedBy: "admin"},
+		Previous:   []bsonTestAddress{{City: "Perth"}},
+		Joined:     joined,
+		Extra:      map[string]interface{}{"legacy": true},
+		Untagged:   true,
+		unexported: "x",
+	}
+
+	m, err := ToBSONMap(&u)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{
+		"name":       "Jo",
+		"age":        30,
+		"created_by": "admin",
+		"previous":   []interface{}{map[string]interface{}{"city": "Perth"}},
+		"joined":     joined,
+		"legacy":     true,
+		"untagged":   true,
+	}, m)
+
+	var decoded bsonTestUser
+	err = FromBSONMap(map[string]interface{}{
+		"_id":        "abc",
+		"name":       "Jo",
+		"age":        int32(30),
+		"skipped":    "nope",
+		"created_by": "admin",
+		"address":    map[string]interface{}{"city": "Sydney"},
+		"previous":   []interface{}{map[string]interface{}{"city": "Perth"}},
+		"joined":     joined,
+		"legacy":     true,
+		"untagged":   true,
+	}, &decoded)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(bsonTestUser{
+		ID:       "abc",
+		Name:     "Jo",
+		Age:      30,
+		Audit:    bsonTestAudit{CreatedBy: "admin"},
+		Address:  &bsonTestAddress{City: "Sydney"},
+		Previous: []bsonTestAddress{{City: "Perth"}},
+		Joined:   joined,
+		Extra:    map[string]interface{}{"legacy": true, "skipped": "nope"},
+		Untagged: true,
+	}, decoded)
+
+	err = FromBSONMap(map[string]interface{}{"age": "old"}, &decoded)
+	a.ErrorContains(err, "Age: ")
+
+	a.Error(FromBSONMap(nil, decoded))
+	_, err = ToBSONMap(1)
+	a.Error(err)
+}
@@ -0,0 +1,169 @@
+package reflectutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// descriptionCacheShards is the number of independently locked shards in the
+// description cache, so that filling it from many goroutines at once doesn't
+// serialise on a single lock.
+const descriptionCacheShards = 64
+
+type descriptionCacheShard struct {
+	sync.RWMutex
+	descriptions map[reflect.Type]*StructDescription
+}
+
+// descriptionCache holds the descriptions built for calls without options,
+// keyed by struct type. Shards' maps are made on first use, so the cache works
+// during package initialisation.
+var descriptionCache [descriptionCacheShards]descriptionCacheShard
+
+func descriptionCacheShardFor(typ reflect.Type) *descriptionCacheShard {
+	p := reflect.ValueOf(typ).Pointer()
+
+	return &descriptionCache[(p>>4)%descriptionCacheShards]
+}
+
+// WithoutCache makes GetDescription build a fresh description rather than
+// returning the shared, cached one. Descriptions built with any other
+// options are never cached either, since the options change the result.
+func WithoutCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+// ClearDescriptionCache forgets every cached description, including the
+// shared nested descriptions (see Field.Description).
+func ClearDescriptionCache() {
+	clearSharedDescriptions()
+
+	for i := range descriptionCache {
+		s := &descriptionCache[i]
+		s.Lock()
+		s.descriptions = nil
+		s.Unlock()
+	}
+}
+
+// getCachedDescription returns the cached description of typ, building and
+// caching it on a miss. Descriptions that fail to build aren't cached.
+func getCachedDescription(typ reflect.Type, o *options) (*StructDescription, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	s := descriptionCacheShardFor(typ)
+
+	s.RLock()
+	d, ok := s.descriptions[typ]
+	s.RUnlock()
+
+	if ok {
+		stats.cacheHits.Add(1)
+		return d, nil
+	}
+
+	stats.cacheMisses.Add(1)
+
+	d, err := getDescriptionFromReflectType(typ, o)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if existing, ok := s.descriptions[typ]; ok {
+		return existing, nil
+	}
+
+	if s.descriptions == nil {
+		s.descriptions = make(map[reflect.Type]*StructDescription)
+	}
+
+	s.descriptions[typ] = d
+
+	return d, nil
+}
+
+// WarmUpOptions controls WarmUp.
+type WarmUpOptions struct {
+	// Concurrency is the number of types described at once. It defaults to
+	// GOMAXPROCS.
+	Concurrency int
+	// Progress, if set, is called after each type is described with the
+	// number done so far and the total. Calls are never concurrent.
+	Progress func(done, total int)
+}
+
+// WarmUp fills the description cache for each of inputs, as GetDescription
+// would, so the first requests a service handles don't pay for describing
+// its types. It stops starting new work once ctx is done, and returns every
+// error it ran into, making it easy to run from an errgroup.
+func WarmUp(ctx context.Context, inputs []interface{}, opts WarmUpOptions) error {
+	n := opts.Concurrency
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		mu   sync.Mutex
+		done int
+		errs []error
+		wg   sync.WaitGroup
+	)
+
+	work := make(chan int)
+
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range work {
+				_, err := GetDescription(inputs[i])
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("input %d (%T): %w", i, inputs[i], err))
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, len(inputs))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range inputs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case work <- i:
+		case <-ctx.Done():
+		}
+	}
+
+	close(work)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.WarmUp: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package reflectutil
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheTestUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDescriptionCache(t *testing.T) {
+	a := assert.New(t)
+
+	ClearDescriptionCache()
+	ResetStats()
+
+	d1, err := GetDescription(cacheTestUser{})
+	a.NoError(err)
+	d2, err := GetDescription(&cacheTestUser{})
+	a.NoError(err)
+	d3, err := GetDescriptionFromReflectType(reflect.TypeOf(cacheTestUser{}))
+	a.NoError(err)
+
+	a.True(d1 == d2)
+	a.True(d1 == d3)
+
+	d4, err := GetDescription(cacheTestUser{}, WithoutCache())
+	a.NoError(err)
+	a.False(d1 == d4)
+	a.Equal(d1.Fields().Names(), d4.Fields().Names())
+
+	d5, err := GetDescription(cacheTestUser{}, WithTagSpans())
+	a.NoError(err)
+	a.False(d1 == d5)
+
+	a.Equal(Stats{DescriptionsBuilt: 3, TagsParsed: 6, CacheHits: 2, CacheMisses: 1}, GetStats())
+
+	ClearDescriptionCache()
+
+	d6, err := GetDescription(cacheTestUser{})
+	a.NoError(err)
+	a.False(d1 == d6)
+
+	_, err = GetDescription(1)
+	a.Error(err)
+	_, err = GetDescription(1)
+	a.Error(err)
+}
+
+func TestDescriptionCacheConcurrent(t *testing.T) {
+	a := assert.New(t)
+
+	ClearDescriptionCache()
+
+	var wg sync.WaitGroup
+	results := make([]*StructDescription, 16)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = GetDescription(cacheTestUser{})
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, d := range results {
+		a.True(d == results[0])
+	}
+}
+
+type cacheTestA struct{ A string }
+type cacheTestB struct{ B string }
+type cacheTestC struct{ C string }
+
+func TestWarmUp(t *testing.T) {
+	a := assert.New(t)
+
+	ClearDescriptionCache()
+	ResetStats()
+
+	var calls [][2]int
+	err := WarmUp(context.Background(), []interface{}{cacheTestA{}, &cacheTestB{}, cacheTestC{}}, WarmUpOptions{
+		Concurrency: 2,
+		Progress:    func(done, total int) { calls = append(calls, [2]int{done, total}) },
+	})
+	a.NoError(err)
+	a.Equal([][2]int{{1, 3}, {2, 3}, {3, 3}}, calls)
+
+	_, err = GetDescription(cacheTestB{})
+	a.NoError(err)
+	a.Equal(uint64(3), GetStats().DescriptionsBuilt)
+
+	err = WarmUp(context.Background(), []interface{}{cacheTestA{}, 1}, WarmUpOptions{})
+	a.ErrorContains(err, "reflectutil.WarmUp: input 1 (int)")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = WarmUp(ctx, []interface{}{cacheTestA{}}, WarmUpOptions{})
+	a.True(errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,103 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CacheKey builds a deterministic cache key from the fields of v that have a
+// tag called tag, as name=value pairs separated by colons:
+//
+//	UserID int    `cache:"user"`
+//	Email  string `cache:"email,lower"`
+//	Query  string `cache:"q,hash:sha256"`
+//
+// gives "user=42:email=jo@example.com:q=5e8f...". Fields come in declaration
+// order unless they have order parameters (see FieldList.SortByOrderParam).
+// Values are formatted as usual, then lower cased with a lower parameter and
+// replaced by their digest with a hash parameter naming a registered
+// derivation. Separators within names and values are escaped, and nil
+// pointers are left out, so different inputs always give different keys.
+func CacheKey(v interface{}, tag string) (string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return "", fmt.Errorf("reflectutil.CacheKey: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return "", fmt.Errorf("reflectutil.CacheKey: %w", err)
+	}
+
+	var parts []string
+
+	var errs []error
+
+	for _, f := range d.fields.SortByOrderParam(tag) {
+		t := f.tags.Get(tag)
+		if t == nil || t.value == "-" || !f.Exported() {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			continue
+		}
+
+		s, err := cacheKeyValue(&f, fv, t)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		name := t.value
+		if name == "" {
+			name = f.name
+		}
+
+		parts = append(parts, escapeCacheKey(name)+"="+escapeCacheKey(s))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return "", fmt.Errorf("reflectutil.CacheKey: %w", err)
+	}
+
+	return strings.Join(parts, ":"), nil
+}
+
+func cacheKeyValue(f *Field, v reflect.Value, t *Tag) (string, error) {
+	s, err := formatString(f, v)
+	if err != nil {
+		return "", err
+	}
+
+	if t.parameters.Has("lower") {
+		s = strings.ToLower(s)
+	}
+
+	if p := t.parameters.Get("hash"); p != nil {
+		fn, ok := getDerivation(p.Value())
+		if !ok {
+			return "", fmt.Errorf("unknown hash %q", p.Value())
+		}
+
+		e, err := fn(f, []interface{}{s})
+		if err != nil {
+			return "", fmt.Errorf("hash %s: %w", p.Value(), err)
+		}
+
+		if s, err = formatString(f, reflect.ValueOf(e)); err != nil {
+			return "", fmt.Errorf("hash %s: %w", p.Value(), err)
+		}
+	}
+
+	return s, nil
+}
+
+var cacheKeyEscaper = strings.NewReplacer("%", "%25", ":", "%3A", "=", "%3D")
+
+func escapeCacheKey(s string) string {
+	return cacheKeyEscaper.Replace(s)
+}
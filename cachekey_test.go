@@ -0,0 +1,56 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheKeyTestQuery struct {
+	UserID int       `cache:"user"`
+	Email  string    `cache:"email,lower"`
+	Query  string    `cache:"q,hash:sha256"`
+	Since  time.Time `cache:"since,format:DateOnly"`
+	Page   *int      `cache:"page"`
+	Region string    `cache:",order:-1"`
+	Trace  string    `cache:"-"`
+	Other  string
+}
+
+func TestCacheKey(t *testing.T) {
+	a := assert.New(t)
+
+	v := cacheKeyTestQuery{
+		UserID: 42,
+		Email:  "Jo@Example.com",
+		Query:  "world",
+		Since:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Region: "eu:west",
+		Trace:  "x",
+		Other:  "x",
+	}
+
+	k, err := CacheKey(&v, "cache")
+	a.NoError(err)
+	a.Equal("Region=eu%3Awest:user=42:email=jo@example.com:q=486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7:since=2024-01-02", k)
+
+	v.Trace, v.Other = "y", "y"
+	k2, err := CacheKey(v, "cache")
+	a.NoError(err)
+	a.Equal(k, k2)
+
+	page := 2
+	v.Page = &page
+	k3, err := CacheKey(v, "cache")
+	a.NoError(err)
+	a.Equal(k+":page=2", k3)
+
+	_, err = CacheKey(struct {
+		A string `cache:"a,hash:whirlpool"`
+	}{}, "cache")
+	a.EqualError(err, `reflectutil.CacheKey: A: unknown hash "whirlpool"`)
+
+	_, err = CacheKey(1, "cache")
+	a.Error(err)
+}
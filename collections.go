@@ -0,0 +1,73 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ElemType returns the element type of a slice, array, map, chan or pointer
+// field, or nil for other kinds.
+func (f *Field) ElemType() reflect.Type {
+	switch f.typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan, reflect.Ptr:
+		return f.typ.Elem()
+	}
+
+	return nil
+}
+
+// KeyType returns the key type of a map field, or nil for other kinds.
+func (f *Field) KeyType() reflect.Type {
+	if f.typ.Kind() == reflect.Map {
+		return f.typ.Key()
+	}
+
+	return nil
+}
+
+// ArrayLen returns the length of an array field, or -1 for other kinds.
+func (f *Field) ArrayLen() int {
+	if f.typ.Kind() == reflect.Array {
+		return f.typ.Len()
+	}
+
+	return -1
+}
+
+// ElemDescription describes the struct type held by a slice, array, map or
+// chan field, looking through pointers on the element type. It returns nil if
+// the field isn't a collection of structs. Descriptions are built on demand,
+// using the same options as GetDescriptionFromReflectType.
+func (f *Field) ElemDescription(opts ...Option) (*StructDescription, error) {
+	if f.typ.Kind() == reflect.Ptr {
+		return nil, nil
+	}
+
+	elem := f.ElemType()
+	if elem == nil || derefType(elem).Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	d, err := GetDescriptionFromReflectType(derefType(elem), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Field.ElemDescription(%s): %w", f.name, err)
+	}
+
+	return d, nil
+}
+
+// KeyDescription is like ElemDescription, but for the key type of a map
+// field keyed by structs.
+func (f *Field) KeyDescription(opts ...Option) (*StructDescription, error) {
+	key := f.KeyType()
+	if key == nil || derefType(key).Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	d, err := GetDescriptionFromReflectType(derefType(key), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Field.KeyDescription(%s): %w", f.name, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,78 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type collectionsTestItem struct {
+	SKU string
+}
+
+type collectionsTestKey struct {
+	Region string
+}
+
+type collectionsTestOrder struct {
+	Items   []collectionsTestItem
+	Ptrs    []*collectionsTestItem
+	Fixed   [3]int
+	ByKey   map[collectionsTestKey]*collectionsTestItem
+	Names   map[string]string
+	Updates chan collectionsTestItem
+	Single  *collectionsTestItem
+	Plain   string
+}
+
+func TestCollectionTypes(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(collectionsTestOrder{})
+	if !a.NoError(err) {
+		return
+	}
+
+	itemType := reflect.TypeOf(collectionsTestItem{})
+
+	for _, tc := range []struct {
+		field    string
+		elem     reflect.Type
+		key      reflect.Type
+		arrayLen int
+		elemDesc reflect.Type
+		keyDesc  reflect.Type
+	}{
+		{"Items", itemType, nil, -1, itemType, nil},
+		{"Ptrs", reflect.PtrTo(itemType), nil, -1, itemType, nil},
+		{"Fixed", reflect.TypeOf(0), nil, 3, nil, nil},
+		{"ByKey", reflect.PtrTo(itemType), reflect.TypeOf(collectionsTestKey{}), -1, itemType, reflect.TypeOf(collectionsTestKey{})},
+		{"Names", reflect.TypeOf(""), reflect.TypeOf(""), -1, nil, nil},
+		{"Updates", itemType, nil, -1, itemType, nil},
+		{"Single", itemType, nil, -1, nil, nil},
+		{"Plain", nil, nil, -1, nil, nil},
+	} {
+		f := d.Field(tc.field)
+
+		a.Equal(tc.elem, f.ElemType(), tc.field)
+		a.Equal(tc.key, f.KeyType(), tc.field)
+		a.Equal(tc.arrayLen, f.ArrayLen(), tc.field)
+
+		ed, err := f.ElemDescription()
+		a.NoError(err, tc.field)
+		if tc.elemDesc == nil {
+			a.Nil(ed, tc.field)
+		} else if a.NotNil(ed, tc.field) {
+			a.Equal(tc.elemDesc, ed.Type(), tc.field)
+		}
+
+		kd, err := f.KeyDescription()
+		a.NoError(err, tc.field)
+		if tc.keyDesc == nil {
+			a.Nil(kd, tc.field)
+		} else if a.NotNil(kd, tc.field) {
+			a.Equal(tc.keyDesc, kd.Type(), tc.field)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CompatibilityChangeKind classifies a difference found by CheckCompatibility.
+type CompatibilityChangeKind int
+
+const (
+	// FieldAdded is a field that only exists in the new description.
+	FieldAdded CompatibilityChangeKind = iota
+	// FieldRemoved is a field that only exists in the old description.
+	FieldRemoved
+	// WireNameChanged is a field whose Go name is kept but whose wire name
+	// isn't. It's compatible if the new field lists the old name in its
+	// renamed_from parameter.
+	WireNameChanged
+	// TypeWidened is a type change that can hold every old value, such as
+	// int32 to int64, T to *T, or anything to interface{}.
+	TypeWidened
+	// TypeNarrowed is the reverse of TypeWidened.
+	TypeNarrowed
+	// TypeChanged is any other type change.
+	TypeChanged
+)
+
+func (k CompatibilityChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "FieldAdded"
+	case FieldRemoved:
+		return "FieldRemoved"
+	case WireNameChanged:
+		return "WireNameChanged"
+	case TypeWidened:
+		return "TypeWidened"
+	case TypeNarrowed:
+		return "TypeNarrowed"
+	case TypeChanged:
+		return "TypeChanged"
+	default:
+		return fmt.Sprintf("[UNKNOWN CHANGE %d]", int(k))
+	}
+}
+
+// CompatibilityRules control how CheckCompatibility matches and judges
+// fields.
+type CompatibilityRules struct {
+	// Tag gives each field's wire name (see Field.EffectiveName), which is how
+	// fields are matched up. With no tag, field names are used.
+	Tag string
+	// AllowRemoved treats removed fields as compatible, for readers that
+	// ignore unknown keys.
+	AllowRemoved bool
+}
+
+// CompatibilityChange is a single difference between two descriptions.
+type CompatibilityChange struct {
+	// Field is the field's wire path, e.g. "address.city".
+	Field    string
+	Kind     CompatibilityChangeKind
+	Breaking bool
+	Detail   string
+}
+
+// CompatibilityReport lists the differences between two descriptions.
+type CompatibilityReport struct {
+	Changes []CompatibilityChange
+}
+
+// Breaking returns the changes that break compatibility.
+func (r *CompatibilityReport) Breaking() []CompatibilityChange {
+	var l []CompatibilityChange
+	for _, c := range r.Changes {
+		if c.Breaking {
+			l = append(l, c)
+		}
+	}
+
+	return l
+}
+
+// Compatible reports whether none of the changes break compatibility.
+func (r *CompatibilityReport) Compatible() bool { return len(r.Breaking()) == 0 }
+
+// CheckCompatibility compares an old and new description of a serialized
+// type, such as an API model, and classifies every difference as
+// backward-compatible or breaking. Fields are matched by wire name, and
+// nested structs are compared field by field. Changes are listed in the new
+// description's field order, followed by removed fields.
+func CheckCompatibility(old, new *StructDescription, rules CompatibilityRules) (*CompatibilityReport, error) {
+	c := compatibilityCheck{rules: rules, seen: make(map[[2]reflect.Type]bool)}
+
+	if err := c.compare(old, new, ""); err != nil {
+		return nil, fmt.Errorf("reflectutil.CheckCompatibility: %w", err)
+	}
+
+	return &CompatibilityReport{Changes: c.changes}, nil
+}
+
+type compatibilityCheck struct {
+	rules   CompatibilityRules
+	seen    map[[2]reflect.Type]bool
+	changes []CompatibilityChange
+}
+
+func (c *compatibilityCheck) add(field string, kind CompatibilityChangeKind, breaking bool, format string, args ...interface{}) {
+	c.changes = append(c.changes, CompatibilityChange{Field: field, Kind: kind, Breaking: breaking, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (c *compatibilityCheck) wireFields(d *StructDescription) ([]*Field, map[string]*Field) {
+	var l []*Field
+	m := make(map[string]*Field)
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		name, ok := f.EffectiveName(c.rules.Tag)
+		if !ok {
+			continue
+		}
+
+		if _, ok := m[name]; !ok {
+			l = append(l, f)
+			m[name] = f
+		}
+	}
+
+	return l, m
+}
+
+func (c *compatibilityCheck) compare(old, new *StructDescription, prefix string) error {
+	oldList, oldFields := c.wireFields(old)
+	newList, newFields := c.wireFields(new)
+
+	matched := make(map[string]bool)
+
+	for _, nf := range newList {
+		name, _ := nf.EffectiveName(c.rules.Tag)
+		path := prefix + name
+
+		if of, ok := oldFields[name]; ok {
+			matched[name] = true
+			if err := c.compareTypes(of.typ, nf.typ, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if of := old.fields.Get(nf.name); of != nil && of.Exported() {
+			if oldName, ok := of.EffectiveName(c.rules.Tag); ok && newFields[oldName] == nil {
+				matched[oldName] = true
+				c.add(path, WireNameChanged, !containsString(nf.RenamedFrom(c.rules.Tag), oldName), "%s renamed from %s", name, oldName)
+				if err := c.compareTypes(of.typ, nf.typ, path); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		c.add(path, FieldAdded, false, "%s added", name)
+	}
+
+	for _, of := range oldList {
+		name, _ := of.EffectiveName(c.rules.Tag)
+		if !matched[name] {
+			c.add(prefix+name, FieldRemoved, !c.rules.AllowRemoved, "%s removed", name)
+		}
+	}
+
+	return nil
+}
+
+func (c *compatibilityCheck) compareTypes(old, new reflect.Type, path string) error {
+	if old == new {
+		return nil
+	}
+
+	switch {
+	case new.Kind() == reflect.Interface && new.NumMethod() == 0:
+		c.add(path, TypeWidened, false, "%s widened from %s to %s", path, old, new)
+		return nil
+	case new.Kind() == reflect.Ptr && old.Kind() != reflect.Ptr && new.Elem() == old:
+		c.add(path, TypeWidened, false, "%s widened from %s to %s", path, old, new)
+		return nil
+	case old.Kind() == reflect.Ptr && new.Kind() != reflect.Ptr && old.Elem() == new:
+		c.add(path, TypeNarrowed, true, "%s narrowed from %s to %s", path, old, new)
+		return nil
+	}
+
+	if w, ok := numberWidens(old, new); ok {
+		if w {
+			c.add(path, TypeWidened, false, "%s widened from %s to %s", path, old, new)
+		} else {
+			c.add(path, TypeNarrowed, true, "%s narrowed from %s to %s", path, old, new)
+		}
+		return nil
+	}
+
+	if old.Kind() == new.Kind() {
+		switch old.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			if old.Kind() == reflect.Array && old.Len() != new.Len() {
+				break
+			}
+			return c.compareTypes(old.Elem(), new.Elem(), path+"[]")
+		case reflect.Map:
+			if old.Key() == new.Key() {
+				return c.compareTypes(old.Elem(), new.Elem(), path+"{}")
+			}
+		case reflect.Struct:
+			if !hasExportedFields(old) || !hasExportedFields(new) {
+				break
+			}
+
+			key := [2]reflect.Type{old, new}
+			if c.seen[key] {
+				return nil
+			}
+			c.seen[key] = true
+
+			od, err := GetDescription(old)
+			if err != nil {
+				return err
+			}
+			nd, err := GetDescription(new)
+			if err != nil {
+				return err
+			}
+
+			return c.compare(od, nd, path+".")
+		default:
+			if old.Kind() != reflect.Interface && old.Kind() != reflect.Func && old.Kind() != reflect.Chan {
+				// Same underlying kind, different named type, e.g. a string
+				// enum type replacing string.
+				return nil
+			}
+		}
+	}
+
+	c.add(path, TypeChanged, true, "%s changed from %s to %s", path, old, new)
+
+	return nil
+}
+
+// numberWidens reports whether changing a number type from old to new widens
+// it (true) or narrows it (false). ok is false if either isn't a number.
+func numberWidens(old, new reflect.Type) (widens, ok bool) {
+	if !isNumberKind(old.Kind()) || !isNumberKind(new.Kind()) || old.Kind() == new.Kind() {
+		return false, false
+	}
+
+	return numberFits(old, new), true
+}
+
+// numberFits reports whether every value of the number type from can be held
+// by to.
+func numberFits(from, to reflect.Type) bool {
+	fk, tk := numberClass(from.Kind()), numberClass(to.Kind())
+
+	switch {
+	case fk == tk:
+		return from.Bits() <= to.Bits()
+	case fk == 'u' && tk == 'i':
+		return from.Bits() < to.Bits()
+	case fk != 'f' && tk == 'f':
+		return (from.Bits() <= 16 && to.Bits() >= 32) || (from.Bits() <= 32 && to.Bits() == 64)
+	}
+
+	return false
+}
+
+func numberClass(k reflect.Kind) byte {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return 'i'
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return 'u'
+	default:
+		return 'f'
+	}
+}
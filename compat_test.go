@@ -0,0 +1,99 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compatTestAddressV1 struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type compatTestAddressV2 struct {
+	City string `json:"city"`
+}
+
+type compatTestUserV1 struct {
+	ID      int32               `json:"id"`
+	Score   float64             `json:"score"`
+	Name    string              `json:"name"`
+	Email   string              `json:"email"`
+	Nick    *string             `json:"nick"`
+	Tags    []int8              `json:"tags"`
+	Address compatTestAddressV1 `json:"address"`
+	Legacy  bool                `json:"legacy"`
+	Meta    string              `json:"meta"`
+	Kind    string              `json:"kind"`
+}
+
+type compatTestUserV2 struct {
+	ID      int64               `json:"id"`
+	Score   float32             `json:"score"`
+	Name    string              `json:"full_name,renamed_from:name"`
+	Email   string              `json:"email_address"`
+	Nick    string              `json:"nick"`
+	Tags    []int16             `json:"tags"`
+	Address compatTestAddressV2 `json:"address"`
+	Created string              `json:"created"`
+	Meta    interface{}         `json:"meta"`
+	Kind    int                 `json:"kind"`
+}
+
+func TestCompatibilityChangeKindString(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("FieldAdded", FieldAdded.String())
+	a.Equal("TypeChanged", TypeChanged.String())
+	a.Equal("[UNKNOWN CHANGE 99]", CompatibilityChangeKind(99).String())
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	a := assert.New(t)
+
+	v1, err := GetDescription(compatTestUserV1{})
+	if !a.NoError(err) {
+		return
+	}
+	v2, err := GetDescription(compatTestUserV2{})
+	if !a.NoError(err) {
+		return
+	}
+
+	r, err := CheckCompatibility(v1, v2, CompatibilityRules{Tag: "json"})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]CompatibilityChange{
+		{Field: "id", Kind: TypeWidened, Detail: "id widened from int32 to int64"},
+		{Field: "score", Kind: TypeNarrowed, Breaking: true, Detail: "score narrowed from float64 to float32"},
+		{Field: "full_name", Kind: WireNameChanged, Detail: "full_name renamed from name"},
+		{Field: "email_address", Kind: WireNameChanged, Breaking: true, Detail: "email_address renamed from email"},
+		{Field: "nick", Kind: TypeNarrowed, Breaking: true, Detail: "nick narrowed from *string to string"},
+		{Field: "tags[]", Kind: TypeWidened, Detail: "tags[] widened from int8 to int16"},
+		{Field: "address.zip", Kind: FieldRemoved, Breaking: true, Detail: "zip removed"},
+		{Field: "created", Kind: FieldAdded, Detail: "created added"},
+		{Field: "meta", Kind: TypeWidened, Detail: "meta widened from string to interface {}"},
+		{Field: "kind", Kind: TypeChanged, Breaking: true, Detail: "kind changed from string to int"},
+		{Field: "legacy", Kind: FieldRemoved, Breaking: true, Detail: "legacy removed"},
+	}, r.Changes)
+	a.False(r.Compatible())
+	a.Len(r.Breaking(), 6)
+
+	r, err = CheckCompatibility(v1, v1, CompatibilityRules{Tag: "json"})
+	a.NoError(err)
+	a.True(r.Compatible())
+	a.Empty(r.Changes)
+
+	r, err = CheckCompatibility(v2, v2, CompatibilityRules{})
+	a.NoError(err)
+	a.Empty(r.Changes)
+
+	a.True(numberFits(reflect.TypeOf(uint8(0)), reflect.TypeOf(int16(0))))
+	a.False(numberFits(reflect.TypeOf(uint16(0)), reflect.TypeOf(int16(0))))
+	a.True(numberFits(reflect.TypeOf(int32(0)), reflect.TypeOf(float64(0))))
+	a.False(numberFits(reflect.TypeOf(int64(0)), reflect.TypeOf(float64(0))))
+}
@@ -0,0 +1,274 @@
+package reflectutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ConfigSource names a layer of configuration, in increasing order of
+// precedence: defaults, then a file, then the environment, then flags, and
+// finally secrets fetched through registered SecretResolvers.
+type ConfigSource string
+
+const (
+	ConfigDefault ConfigSource = "default"
+	ConfigFile    ConfigSource = "file"
+	ConfigEnv     ConfigSource = "env"
+	ConfigFlag    ConfigSource = "flag"
+	ConfigSecret  ConfigSource = "secret"
+)
+
+// ConfigProvenance is the Provenance returned by ConfigLoader.Load.
+//
+// Deprecated: use Provenance.
+type ConfigProvenance = Provenance
+
+// ConfigLoader fills a struct from layered sources, each overriding the last:
+//
+//	Addr string `default:":8080" config:"addr" env:"ADDR" flag:"addr"`
+//
+// Fields without a tag for a particular layer are left alone by it. Nested
+// structs are filled field by field, taking their file values from nested
+// maps; nil pointers to structs are treated as single values, so they can only
+// be set from the file.
+type ConfigLoader struct {
+	// File holds the decoded contents of a configuration file, keyed by the
+	// config tag (or field name).
+	File map[string]interface{}
+	// LookupEnv looks up env tags. It defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+	// Flags holds the values of flags that were actually set, keyed by the
+	// flag tag.
+	Flags map[string]string
+	// Options control how file values are decoded, e.g. WithStrictTypes or
+	// WithUnknownKeyPolicy.
+	Options []Option
+}
+
+// Load fills v, which must be a pointer to a struct, and reports where each
+// value came from, with Source set to one of the ConfigSources. If Options
+// include WithProvenance, that Provenance is filled in and returned. All
+// errors are collected, each identifying the field and source involved.
+func (l *ConfigLoader) Load(v interface{}) (Provenance, error) {
+	return l.LoadContext(context.Background(), v)
+}
+
+// LoadContext is like Load, passing ctx along to any SecretResolvers.
+func (l *ConfigLoader) LoadContext(ctx context.Context, v interface{}) (Provenance, error) {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ConfigLoader.Load: %w", err)
+	}
+
+	lookupEnv := l.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	o := getOptions(l.Options)
+	if o.provenance == nil {
+		o.provenance = Provenance{}
+	}
+
+	c := configLoad{loader: l, lookupEnv: lookupEnv}
+
+	if err := c.loadStruct(rv, "", l.File, o); err != nil {
+		return o.provenance, fmt.Errorf("reflectutil.ConfigLoader.Load: %w", err)
+	}
+
+	err = resolveSecrets(ctx, rv, "", func(path string, ref SecretRef) {
+		o.provenance[path] = ValueSource{Source: string(ConfigSecret), Key: ref.String()}
+	})
+	if err != nil {
+		return o.provenance, fmt.Errorf("reflectutil.ConfigLoader.Load: %w", err)
+	}
+
+	return o.provenance, nil
+}
+
+// ConfigKey returns the key used to find the field's value in a configuration
+// file.
+func (f *Field) ConfigKey() (string, bool) {
+	t := f.tags.Get("config")
+	if t == nil || t.value == "" {
+		return f.name, true
+	}
+
+	if t.value == "-" {
+		return "", false
+	}
+
+	return t.value, true
+}
+
+type configLoad struct {
+	loader    *ConfigLoader
+	lookupEnv func(key string) (string, bool)
+}
+
+func (c *configLoad) loadStruct(rv reflect.Value, prefix string, file map[string]interface{}, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		key, hasKey := f.ConfigKey()
+		if hasKey {
+			used[key] = true
+		}
+
+		if fv, ok := fieldValue(rv, f.index); ok && isConfigSection(fv) {
+			sub, _ := file[key].(map[string]interface{})
+			if !hasKey {
+				sub = nil
+			}
+
+			if err := c.loadStruct(reflect.Indirect(fv), prefix+f.name+".", sub, o.forKey(key, f.name)); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		if err := c.loadField(f, rv, prefix+f.name, file, key, hasKey, o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if file != nil {
+		if err := unknownKeys(o, file, used); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadField applies each layer in turn to the field f of rv. The field is
+// only looked up for writing (allocating any embedded pointers on the way)
+// once some layer has a value for it.
+func (c *configLoad) loadField(f *Field, rv reflect.Value, path string, file map[string]interface{}, key string, hasKey bool, o *options) error {
+	var v reflect.Value
+
+	set := func(source ValueSource, fn func(v reflect.Value) error) error {
+		if !v.IsValid() {
+			fv, err := fieldByIndexAlloc(f, rv)
+			if err != nil {
+				return &FieldError{Field: path, Err: fmt.Errorf("%s: %w", source.Source, err)}
+			}
+
+			v = fv
+		}
+
+		if err := fn(v); err != nil {
+			return &FieldError{Field: path, Err: fmt.Errorf("%s: %w", source.Source, err)}
+		}
+
+		o.recordSource(f.name, source)
+
+		return nil
+	}
+
+	if s, ok := f.DefaultValue(); ok {
+		if fv, ok := fieldValue(rv, f.index); !ok || fv.IsZero() {
+			if err := set(ValueSource{Source: string(ConfigDefault), Default: true}, func(v reflect.Value) error { return setDefault(f, v, s) }); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e, ok := file[key]; ok && hasKey {
+		if err := set(ValueSource{Source: string(ConfigFile), Key: o.keyPrefix + key}, func(v reflect.Value) error { return assignValue(f, v, e, configStructFromMap, o.forKey(key, f.name)) }); err != nil {
+			return err
+		}
+	}
+
+	if t := f.tags.Get("env"); t != nil && t.value != "" && t.value != "-" {
+		if s, ok := c.lookupEnv(t.value); ok {
+			if err := set(ValueSource{Source: string(ConfigEnv), Key: t.value}, func(v reflect.Value) error { return setFromString(f, v, s) }); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t := f.tags.Get("flag"); t != nil && t.value != "" && t.value != "-" {
+		if s, ok := c.loader.Flags[t.value]; ok {
+			if err := set(ValueSource{Source: string(ConfigFlag), Key: t.value}, func(v reflect.Value) error { return setFromString(f, v, s) }); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isConfigSection reports whether v should be loaded field by field rather
+// than as a single value.
+func isConfigSection(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return false
+	}
+
+	return hasExportedFields(v.Type())
+}
+
+func configStructFromMap(m map[string]interface{}, rv reflect.Value, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		key, ok := f.ConfigKey()
+		if !ok {
+			continue
+		}
+
+		e, ok := m[key]
+		if !ok {
+			continue
+		}
+
+		used[key] = true
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = assignValue(f, fv, e, configStructFromMap, o.forKey(key, f.name))
+		}
+		if err != nil {
+			return &FieldError{Field: f.name, Err: err}
+		}
+
+		o.record(f.name, key, string(ConfigFile))
+	}
+
+	return unknownKeys(o, m, used)
+}
@@ -0,0 +1,86 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type configTestDatabase struct {
+	Host string `config:"host" default:"localhost" env:"DB_HOST"`
+	Port int    `config:"port" default:"5432"`
+}
+
+type configTestTLS struct {
+	Cert string `config:"cert"`
+}
+
+type configTest struct {
+	Addr     string             `config:"addr" default:":8080" env:"ADDR" flag:"addr"`
+	Timeout  time.Duration      `config:"timeout" default:"5s" env:"TIMEOUT"`
+	Debug    bool               `env:"DEBUG" flag:"debug"`
+	Database configTestDatabase `config:"database"`
+	TLS      *configTestTLS     `config:"tls"`
+	Ignored  string             `config:"-"`
+}
+
+func TestConfigLoader(t *testing.T) {
+	a := assert.New(t)
+
+	env := map[string]string{"ADDR": ":9090", "DB_HOST": "db.internal", "DEBUG": "true"}
+
+	l := ConfigLoader{
+		File: map[string]interface{}{
+			"addr":     ":7070",
+			"timeout":  "10s",
+			"database": map[string]interface{}{"port": 6543},
+			"tls":      map[string]interface{}{"cert": "/etc/cert.pem"},
+			"Ignored":  "x",
+		},
+		LookupEnv: func(k string) (string, bool) { s, ok := env[k]; return s, ok },
+		Flags:     map[string]string{"addr": ":6060"},
+	}
+
+	var c configTest
+	p, err := l.Load(&c)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(configTest{
+		Addr:     ":6060",
+		Timeout:  10 * time.Second,
+		Debug:    true,
+		Database: configTestDatabase{Host: "db.internal", Port: 6543},
+		TLS:      &configTestTLS{Cert: "/etc/cert.pem"},
+	}, c)
+
+	a.Equal(Provenance{
+		"Addr":          {Source: "flag", Key: "addr"},
+		"Timeout":       {Source: "file", Key: "timeout"},
+		"Debug":         {Source: "env", Key: "DEBUG"},
+		"Database.Host": {Source: "env", Key: "DB_HOST"},
+		"Database.Port": {Source: "file", Key: "database.port"},
+		"TLS":           {Source: "file", Key: "tls"},
+		"TLS.Cert":      {Source: "file", Key: "tls.cert"},
+	}, p)
+}
+
+func TestConfigLoaderErrors(t *testing.T) {
+	a := assert.New(t)
+
+	l := ConfigLoader{
+		File:      map[string]interface{}{"timeout": "soon", "database": map[string]interface{}{"port": "x"}},
+		LookupEnv: func(string) (string, bool) { return "", false },
+	}
+
+	var c configTest
+	p, err := l.Load(&c)
+	a.ErrorContains(err, "Timeout: file:")
+	a.ErrorContains(err, "Database.Port: file:")
+	a.Equal(ValueSource{Source: string(ConfigDefault), Default: true}, p["Addr"])
+
+	_, err = l.Load(c)
+	a.Error(err)
+}
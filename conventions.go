@@ -0,0 +1,165 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ConventionViolationKind classifies a problem found by CheckConventions.
+type ConventionViolationKind int
+
+const (
+	// ConventionMissingTag is an exported field without one of the tags in
+	// ConventionRules.RequireTags.
+	ConventionMissingTag ConventionViolationKind = iota
+	// ConventionNotSnakeCase is a value of one of the tags in
+	// ConventionRules.SnakeCaseTags that isn't snake_case.
+	ConventionNotSnakeCase
+	// ConventionDuplicateTable is a table name claimed by more than one type.
+	ConventionDuplicateTable
+)
+
+func (k ConventionViolationKind) String() string {
+	switch k {
+	case ConventionMissingTag:
+		return "MissingTag"
+	case ConventionNotSnakeCase:
+		return "NotSnakeCase"
+	case ConventionDuplicateTable:
+		return "DuplicateTable"
+	default:
+		return fmt.Sprintf("[UNKNOWN VIOLATION %d]", int(k))
+	}
+}
+
+// ConventionRules configure CheckConventions. Zero values disable each rule.
+type ConventionRules struct {
+	// RequireTags lists tags every exported field must have, e.g. "json". A
+	// "-" value counts, since it's an explicit decision.
+	RequireTags []string
+	// SnakeCaseTags lists tags whose values must be snake_case, e.g. "db".
+	SnakeCaseTags []string
+	// UniqueTables makes sure that no two types claim the same table name.
+	UniqueTables bool
+	// TableName returns the table a type is stored in, or "" if none. It
+	// defaults to calling the type's TableName() string method, if it has one.
+	TableName func(d *StructDescription) string
+}
+
+// ConventionViolation is a single rule broken by a registered type.
+type ConventionViolation struct {
+	// Type is the type's FullName.
+	Type string
+	// Field is the field's Go path, e.g. "Address.Street", or empty for
+	// violations concerning the whole type.
+	Field  string
+	Kind   ConventionViolationKind
+	Detail string
+}
+
+func (v ConventionViolation) String() string {
+	if v.Field == "" {
+		return fmt.Sprintf("%s: %s: %s", v.Type, v.Kind, v.Detail)
+	}
+
+	return fmt.Sprintf("%s.%s: %s: %s", v.Type, v.Field, v.Kind, v.Detail)
+}
+
+// ConventionReport lists the violations found by CheckConventions.
+type ConventionReport struct {
+	Violations []ConventionViolation
+}
+
+// OK reports whether there were no violations.
+func (r *ConventionReport) OK() bool { return len(r.Violations) == 0 }
+
+// CheckConventions checks every type in the registry (see RegisterType)
+// against rules. Violations are listed type by type, in the order of
+// RegisteredTypes, with duplicate table names last.
+func CheckConventions(rules ConventionRules) *ConventionReport {
+	return checkConventions(RegisteredTypes(), rules)
+}
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+type tableNamer interface{ TableName() string }
+
+func defaultTableName(d *StructDescription) string {
+	if d.typ == nil {
+		return ""
+	}
+
+	// a zero value for value receivers, and a pointer to one (rather than a
+	// nil pointer) for pointer receivers
+	if t, ok := reflect.Zero(d.typ).Interface().(tableNamer); ok {
+		return t.TableName()
+	}
+
+	if t, ok := reflect.New(d.typ).Interface().(tableNamer); ok {
+		return t.TableName()
+	}
+
+	return ""
+}
+
+func checkConventions(descriptions []*StructDescription, rules ConventionRules) *ConventionReport {
+	r := &ConventionReport{}
+
+	add := func(d *StructDescription, field string, kind ConventionViolationKind, format string, args ...interface{}) {
+		r.Violations = append(r.Violations, ConventionViolation{Type: d.FullName(), Field: field, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	for _, d := range descriptions {
+		for i := range d.fields {
+			f := &d.fields[i]
+			if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+				continue
+			}
+
+			path := strings.Join(append(append([]string(nil), f.path...), f.name), ".")
+
+			for _, name := range rules.RequireTags {
+				if !f.tags.Has(name) {
+					add(d, path, ConventionMissingTag, "no %s tag", name)
+				}
+			}
+
+			for _, name := range rules.SnakeCaseTags {
+				t := f.tags.Get(name)
+				if t == nil || t.value == "" || t.value == "-" {
+					continue
+				}
+
+				if !snakeCasePattern.MatchString(t.value) {
+					add(d, path, ConventionNotSnakeCase, "%s tag %q is not snake_case", name, t.value)
+				}
+			}
+		}
+	}
+
+	if rules.UniqueTables {
+		tableName := rules.TableName
+		if tableName == nil {
+			tableName = defaultTableName
+		}
+
+		claimed := make(map[string]*StructDescription)
+		for _, d := range descriptions {
+			name := tableName(d)
+			if name == "" {
+				continue
+			}
+
+			if first, ok := claimed[name]; ok {
+				add(d, "", ConventionDuplicateTable, "table %q is already claimed by %s", name, first.FullName())
+				continue
+			}
+
+			claimed[name] = d
+		}
+	}
+
+	return r
+}
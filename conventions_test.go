@@ -0,0 +1,73 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type conventionsTestAudit struct {
+	CreatedBy string `json:"created_by" db:"createdBy"`
+}
+
+type conventionsTestUser struct {
+	conventionsTestAudit
+	ID       int    `json:"id" db:"id"`
+	Name     string `json:"name" db:"full_name"`
+	Password string `json:"-" db:"password_hash"`
+	Email    string `db:"Email"`
+	internal string
+}
+
+func (conventionsTestUser) TableName() string { return "users" }
+
+type conventionsTestAdmin struct {
+	ID     int `json:"id" db:"id"`
+	prefix string
+}
+
+// TableName has a pointer receiver and reads a field, so it must not be
+// called on a nil pointer.
+func (a *conventionsTestAdmin) TableName() string { return a.prefix + "users" }
+
+type conventionsTestNote struct {
+	Text string `json:"text"`
+}
+
+func TestCheckConventions(t *testing.T) {
+	a := assert.New(t)
+
+	for _, v := range []interface{}{conventionsTestUser{}, conventionsTestAdmin{}, conventionsTestNote{}} {
+		if !a.NoError(RegisterType(v)) {
+			return
+		}
+		defer UnregisterType(reflect.TypeOf(v))
+	}
+
+	r := CheckConventions(ConventionRules{
+		RequireTags:   []string{"json"},
+		SnakeCaseTags: []string{"db"},
+		UniqueTables:  true,
+	})
+
+	const pkg = "fknsrs.biz/p/reflectutil."
+
+	a.Equal([]ConventionViolation{
+		{Type: pkg + "conventionsTestUser", Field: "conventionsTestAudit.CreatedBy", Kind: ConventionNotSnakeCase, Detail: `db tag "createdBy" is not snake_case`},
+		{Type: pkg + "conventionsTestUser", Field: "Email", Kind: ConventionMissingTag, Detail: "no json tag"},
+		{Type: pkg + "conventionsTestUser", Field: "Email", Kind: ConventionNotSnakeCase, Detail: `db tag "Email" is not snake_case`},
+		{Type: pkg + "conventionsTestUser", Kind: ConventionDuplicateTable, Detail: `table "users" is already claimed by ` + pkg + "conventionsTestAdmin"},
+	}, r.Violations)
+	a.False(r.OK())
+
+	a.Equal("fknsrs.biz/p/reflectutil.conventionsTestUser.Email: MissingTag: no json tag", r.Violations[1].String())
+
+	r = CheckConventions(ConventionRules{
+		UniqueTables: true,
+		TableName:    func(d *StructDescription) string { return d.Name() },
+	})
+	a.True(r.OK())
+
+	a.True(CheckConventions(ConventionRules{}).OK())
+}
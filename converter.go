@@ -0,0 +1,87 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+var converters = struct {
+	sync.RWMutex
+	pairs   map[[2]reflect.Type]reflect.Value
+	targets map[reflect.Type]reflect.Value
+}{
+	pairs:   map[[2]reflect.Type]reflect.Value{},
+	targets: map[reflect.Type]reflect.Value{},
+}
+
+// RegisterConverter adds a conversion to the rules used by Convert and every
+// decoder and setter in this package. fn must be a function shaped like
+// func(From) (To, error). It's used whenever a From needs to become a To, so
+// RegisterConverter(uuid.Parse) makes UUID fields work with string input
+// everywhere. If From is interface{}, fn is instead consulted for any source
+// value that has no more specific converter. Registering a second converter
+// for the same types replaces the first; a function of any other shape
+// panics, as does any call in builds with the tinygo tag.
+func RegisterConverter(fn interface{}) {
+	if !callSupported {
+		panic("reflectutil.RegisterConverter: converters aren't supported with the tinygo tag")
+	}
+
+	v := reflect.ValueOf(fn)
+
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 2 || t.Out(1) != errorType {
+		panic(fmt.Sprintf("reflectutil.RegisterConverter: expected func(From) (To, error); got %s", t))
+	}
+
+	converters.Lock()
+	defer converters.Unlock()
+
+	if t.In(0) == interfaceType {
+		converters.targets[t.Out(0)] = v
+	} else {
+		converters.pairs[[2]reflect.Type{t.In(0), t.Out(0)}] = v
+	}
+}
+
+func getConverter(from, to reflect.Type) (reflect.Value, bool) {
+	converters.RLock()
+	defer converters.RUnlock()
+
+	if fn, ok := converters.pairs[[2]reflect.Type{from, to}]; ok {
+		return fn, true
+	}
+
+	fn, ok := converters.targets[to]
+	return fn, ok
+}
+
+// convertWithRegistered stores sv in v using a registered converter, if
+// there is one for their types.
+func convertWithRegistered(v, sv reflect.Value) (bool, error) {
+	fn, ok := getConverter(sv.Type(), v.Type())
+	if !ok {
+		return false, nil
+	}
+
+	in := sv
+	if fn.Type().In(0) == interfaceType {
+		in = reflect.New(interfaceType).Elem()
+		in.Set(sv)
+	}
+
+	out := callFunc(fn, []reflect.Value{in})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return true, err
+	}
+
+	v.Set(out[0])
+
+	return true, nil
+}
@@ -0,0 +1,80 @@
+//go:build !tinygo
+
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type converterTestID struct {
+	prefix string
+	n      int
+}
+
+type converterTestCents int64
+
+func init() {
+	RegisterConverter(func(s string) (converterTestID, error) {
+		prefix, n, ok := strings.Cut(s, "-")
+		if !ok {
+			return converterTestID{}, errors.New("expected prefix-number")
+		}
+
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			return converterTestID{}, err
+		}
+
+		return converterTestID{prefix: prefix, n: i}, nil
+	})
+
+	RegisterConverter(func(v interface{}) (converterTestCents, error) {
+		switch v := v.(type) {
+		case float64:
+			return converterTestCents(v * 100), nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimPrefix(v, "$"), 64)
+			return converterTestCents(f * 100), err
+		}
+
+		return 0, fmt.Errorf("can't make cents from %T", v)
+	})
+}
+
+func TestRegisterConverter(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := Convert("usr-12", reflect.TypeOf(converterTestID{}))
+	a.NoError(err)
+	a.Equal(converterTestID{prefix: "usr", n: 12}, r)
+
+	r, err = Convert(1.5, reflect.TypeOf(converterTestCents(0)))
+	a.NoError(err)
+	a.Equal(converterTestCents(150), r)
+
+	_, err = Convert(true, reflect.TypeOf(converterTestCents(0)))
+	a.ErrorContains(err, "can't make cents from bool")
+
+	var v struct {
+		ID    converterTestID     `redis:"id"`
+		Price *converterTestCents `redis:"price"`
+	}
+	a.NoError(FromRedisHash(map[string]string{"id": "ord-7", "price": "$2.5"}, &v))
+	a.Equal(converterTestID{prefix: "ord", n: 7}, v.ID)
+	if a.NotNil(v.Price) {
+		a.Equal(converterTestCents(250), *v.Price)
+	}
+
+	a.ErrorContains(SetFields(&v, map[string]interface{}{"ID": "nope"}, ""), "ID: expected prefix-number")
+
+	a.PanicsWithValue("reflectutil.RegisterConverter: expected func(From) (To, error); got func(string) int", func() {
+		RegisterConverter(func(string) int { return 0 })
+	})
+}
@@ -0,0 +1,233 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// CopyStep is a single field copied by a CopyPlan.
+type CopyStep struct {
+	// Key is the name the two fields share in the plan's tag.
+	Key string
+	Src *Field
+	Dst *Field
+	// Convert is true if the value has to be converted between types on the
+	// way, which can still fail for particular values (e.g. parsing a string,
+	// or a number that doesn't fit).
+	Convert bool
+}
+
+// MismatchReason explains why a field isn't part of a CopyPlan.
+type MismatchReason string
+
+const (
+	MismatchNoDestination MismatchReason = "no matching destination field"
+	MismatchNoSource      MismatchReason = "no matching source field"
+	MismatchIncompatible  MismatchReason = "types are incompatible"
+)
+
+// Mismatch reports a field that PlanCopy couldn't map. Src or Dst is nil if
+// there's no field on that side.
+type Mismatch struct {
+	Key    string
+	Src    *Field
+	Dst    *Field
+	Reason MismatchReason
+}
+
+func (m Mismatch) String() string {
+	if m.Reason == MismatchIncompatible {
+		return fmt.Sprintf("%s: %s (%s to %s)", m.Key, m.Reason, m.Src.typ, m.Dst.typ)
+	}
+
+	return fmt.Sprintf("%s: %s", m.Key, m.Reason)
+}
+
+// CopyPlan copies fields between values of two struct types, worked out in
+// advance by PlanCopy.
+type CopyPlan struct {
+	Steps []CopyStep
+
+	src, dst reflect.Type
+}
+
+// PlanCopy matches the exported fields of src to those of dst by their names
+// in the given tag (see Field.EffectiveName, and SetFields for how keys are
+// matched on the destination side), and checks that each pair's types can be
+// converted by the package's coercion rules. Embedded structs aren't matched
+// themselves, since their promoted fields are. Fields on either side without
+// a counterpart, and pairs whose types are incompatible, are returned as
+// mismatches: sources first, in src's field order, then unmatched
+// destinations in dst's. This is done once, up front, so that mappers can
+// refuse to start rather than finding out when the first value is copied.
+func PlanCopy(src, dst *StructDescription, tag string) (*CopyPlan, []Mismatch) {
+	p := &CopyPlan{Steps: []CopyStep{}, src: src.typ, dst: dst.typ}
+
+	var mismatches []Mismatch
+
+	used := make(map[string]bool)
+
+	for i := range src.fields {
+		sf := &src.fields[i]
+
+		key, ok := copyKey(sf, tag)
+		if !ok {
+			continue
+		}
+
+		df := dst.fields.getByKey(tag, key)
+		if df != nil {
+			if _, ok := copyKey(df, tag); !ok {
+				df = nil
+			}
+		}
+
+		if df == nil {
+			mismatches = append(mismatches, Mismatch{Key: key, Src: sf, Reason: MismatchNoDestination})
+			continue
+		}
+
+		used[df.name] = true
+
+		convert, ok := copyConversion(sf.typ, df.typ)
+		if !ok {
+			mismatches = append(mismatches, Mismatch{Key: key, Src: sf, Dst: df, Reason: MismatchIncompatible})
+			continue
+		}
+
+		p.Steps = append(p.Steps, CopyStep{Key: key, Src: sf, Dst: df, Convert: convert})
+	}
+
+	for i := range dst.fields {
+		df := &dst.fields[i]
+
+		key, ok := copyKey(df, tag)
+		if !ok || used[df.name] {
+			continue
+		}
+
+		mismatches = append(mismatches, Mismatch{Key: key, Dst: df, Reason: MismatchNoSource})
+	}
+
+	return p, mismatches
+}
+
+func copyKey(f *Field, tag string) (string, bool) {
+	if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+		return "", false
+	}
+
+	if tag == "" {
+		return f.name, true
+	}
+
+	return f.EffectiveName(tag)
+}
+
+// copyConversion reports whether assignValue can store a src in a dst, and if
+// so, whether it needs to convert it rather than assigning it directly. It
+// mirrors assignValue's rules without looking at any values, so conversions
+// out of interfaces are assumed to work.
+func copyConversion(src, dst reflect.Type) (convert, ok bool) {
+	if src.AssignableTo(dst) {
+		return false, true
+	}
+
+	if _, ok := getConverter(src, dst); ok {
+		return true, true
+	}
+
+	if w, ok := LookupWrapper(dst); ok && w.Wrap != nil && w.Underlying != nil {
+		_, ok := copyConversion(src, w.Underlying)
+		return true, ok
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		_, ok := copyConversion(src, dst.Elem())
+		return true, ok
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		_, ok := copyConversion(src.Elem(), dst)
+		return true, ok
+	case reflect.Interface:
+		return true, true
+	}
+
+	switch {
+	case src.Kind() == reflect.String && dst.Kind() != reflect.String:
+		return true, dst.Kind() != reflect.Struct || reflect.PtrTo(dst).Implements(textUnmarshalerType)
+	case src.Kind() == reflect.Slice && src.Elem().Kind() == reflect.Uint8 && dst.Kind() == reflect.String:
+		return true, true
+	case isNumberKind(src.Kind()) && isNumberKind(dst.Kind()):
+		return true, true
+	case src.Kind() == reflect.Bool && dst.Kind() == reflect.Bool:
+		return true, true
+	case (src.Kind() == reflect.Slice || src.Kind() == reflect.Array) && (dst.Kind() == reflect.Slice || dst.Kind() == reflect.Array):
+		_, ok := copyConversion(src.Elem(), dst.Elem())
+		return true, ok
+	case src.Kind() == reflect.Map && dst.Kind() == reflect.Map:
+		_, keyOK := copyConversion(src.Key(), dst.Key())
+		_, elemOK := copyConversion(src.Elem(), dst.Elem())
+		return true, keyOK && elemOK
+	case src.ConvertibleTo(dst) && src.Kind() == dst.Kind():
+		return true, true
+	}
+
+	return false, false
+}
+
+// Copy copies each of the plan's fields from src to dst. src must be a value
+// of (or pointer to) the source type, and dst a pointer to the destination
+// type. Fields promoted through nil embedded pointers in src are skipped, and
+// those in dst are allocated. Every field that can't be copied is reported as
+// a *FieldError.
+func (p *CopyPlan) Copy(dst, src interface{}) error {
+	sv, err := structValue(src)
+	if err != nil {
+		return fmt.Errorf("reflectutil.CopyPlan.Copy: %w", err)
+	}
+
+	if sv.Type() != p.src {
+		return fmt.Errorf("reflectutil.CopyPlan.Copy: source should be %s; got %s", p.src, sv.Type())
+	}
+
+	dv, err := settableStructValue(dst)
+	if err != nil {
+		return fmt.Errorf("reflectutil.CopyPlan.Copy: %w", err)
+	}
+
+	if dv.Type() != p.dst {
+		return fmt.Errorf("reflectutil.CopyPlan.Copy: destination should be %s; got %s", p.dst, dv.Type())
+	}
+
+	fromMap := func(m map[string]interface{}, v reflect.Value, o *options) error { return setFields(v, m, "", o) }
+	o := getOptions(nil)
+
+	var errs []error
+
+	for _, s := range p.Steps {
+		fv, ok := fieldValue(sv, s.Src.index)
+		if !ok {
+			continue
+		}
+
+		v, err := fieldByIndexAlloc(s.Dst, dv)
+		if err == nil && !s.Convert {
+			v.Set(fv)
+		} else if err == nil {
+			err = assignValue(s.Dst, v, fv.Interface(), fromMap, o)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: s.Key, Err: err})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.CopyPlan.Copy: %w", err)
+	}
+
+	return nil
+}
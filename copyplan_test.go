@@ -0,0 +1,181 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type copyPlanTestRow struct {
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Age      string   `json:"age"`
+	Tags     []string `json:"tags"`
+	Address  struct{ Street string }
+	Internal string `json:"-"`
+	Legacy   string `json:"legacy"`
+}
+
+type copyPlanTestBase struct {
+	ID int `json:"id"`
+}
+
+type copyPlanTestModel struct {
+	*copyPlanTestBase
+	FullName string            `json:"name"`
+	Age      *uint8            `json:"age"`
+	Tags     []string          `json:"tags"`
+	Address  map[string]string `json:"Address"`
+	Created  string            `json:"created"`
+}
+
+func TestPlanCopy(t *testing.T) {
+	a := assert.New(t)
+
+	src, err := GetDescription(copyPlanTestRow{})
+	if !a.NoError(err) {
+		return
+	}
+
+	dst, err := GetDescription(copyPlanTestModel{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, mismatches := PlanCopy(src, dst, "json")
+
+	var steps []string
+	for _, s := range p.Steps {
+		steps = append(steps, s.Src.Name()+">"+s.Dst.Name())
+	}
+
+	a.Equal([]string{"ID>ID", "Name>FullName", "Age>Age", "Tags>Tags"}, steps)
+	a.Equal([]bool{true, false, true, false}, []bool{p.Steps[0].Convert, p.Steps[1].Convert, p.Steps[2].Convert, p.Steps[3].Convert})
+
+	var reasons []string
+	for _, m := range mismatches {
+		reasons = append(reasons, m.String())
+	}
+
+	a.Equal([]string{
+		"Address: types are incompatible (struct { Street string } to map[string]string)",
+		"legacy: no matching destination field",
+		"created: no matching source field",
+	}, reasons)
+}
+
+func TestCopyPlanCopy(t *testing.T) {
+	a := assert.New(t)
+
+	src, err := GetDescription(copyPlanTestRow{})
+	if !a.NoError(err) {
+		return
+	}
+
+	dst, err := GetDescription(copyPlanTestModel{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, _ := PlanCopy(src, dst, "json")
+
+	var m copyPlanTestModel
+	err = p.Copy(&m, copyPlanTestRow{ID: 3, Name: "Jo", Age: "42", Tags: []string{"a"}})
+	a.EqualError(err, "reflectutil.CopyPlan.Copy: id: can't set: field is promoted through a nil pointer that can't be allocated")
+
+	m = copyPlanTestModel{copyPlanTestBase: &copyPlanTestBase{}}
+	if a.NoError(p.Copy(&m, &copyPlanTestRow{ID: 3, Name: "Jo", Age: "42", Tags: []string{"a"}})) {
+		a.Equal(3, m.ID)
+		a.Equal("Jo", m.FullName)
+		if a.NotNil(m.Age) {
+			a.Equal(uint8(42), *m.Age)
+		}
+		a.Equal([]string{"a"}, m.Tags)
+	}
+
+	a.Error(p.Copy(&m, copyPlanTestRow{Age: "old"}))
+	a.EqualError(p.Copy(&m, copyPlanTestModel{}), "reflectutil.CopyPlan.Copy: source should be reflectutil.copyPlanTestRow; got reflectutil.copyPlanTestModel")
+	a.EqualError(p.Copy(&copyPlanTestRow{}, copyPlanTestRow{}), "reflectutil.CopyPlan.Copy: destination should be reflectutil.copyPlanTestModel; got reflectutil.copyPlanTestRow")
+}
+
+func TestPlanCopyUntagged(t *testing.T) {
+	a := assert.New(t)
+
+	type A struct {
+		X int
+		Y []int32
+		Z map[string]int
+	}
+
+	type B struct {
+		X float64
+		Y [4]int64
+		Z map[string]bool
+	}
+
+	src, err := GetDescription(A{})
+	if !a.NoError(err) {
+		return
+	}
+
+	dst, err := GetDescription(B{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, mismatches := PlanCopy(src, dst, "")
+	if a.Len(p.Steps, 2) && a.Len(mismatches, 1) {
+		a.Equal("Z", mismatches[0].Key)
+		a.Equal(MismatchIncompatible, mismatches[0].Reason)
+	}
+
+	var b B
+	if a.NoError(p.Copy(&b, A{X: 1, Y: []int32{1, 2}})) {
+		a.Equal(B{X: 1, Y: [4]int64{1, 2}}, b)
+	}
+}
+
+func TestPlanCopyEmptyTagValue(t *testing.T) {
+	a := assert.New(t)
+
+	type T struct {
+		Name string `json:",omitempty"`
+	}
+
+	d, err := GetDescription(T{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, mismatches := PlanCopy(d, d, "json")
+	a.Empty(mismatches)
+	if a.Len(p.Steps, 1) {
+		a.Equal("Name", p.Steps[0].Key)
+		a.False(p.Steps[0].Convert)
+	}
+}
+
+func TestPlanCopyWrapper(t *testing.T) {
+	a := assert.New(t)
+
+	type A struct{ Amount string }
+	type B struct{ Amount wrapperTestDecimal }
+
+	src, err := GetDescription(A{})
+	if !a.NoError(err) {
+		return
+	}
+
+	dst, err := GetDescription(B{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, mismatches := PlanCopy(src, dst, "")
+	a.Empty(mismatches)
+	if a.Len(p.Steps, 1) && a.True(p.Steps[0].Convert) {
+		var b B
+		a.NoError(p.Copy(&b, A{Amount: "1.50"}))
+		a.Equal(B{wrapperTestDecimal{"1.50"}}, b)
+	}
+}
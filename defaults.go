@@ -0,0 +1,107 @@
+package reflectutil
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultProvider computes a default value at the time defaults are applied.
+// It's called for default tags in the form `default:"name()"` or
+// `default:"name(arg)"`, and its result is converted to the field's type.
+type DefaultProvider func(f *Field, arg string) (interface{}, error)
+
+var defaultProviders = struct {
+	sync.RWMutex
+	fns map[string]DefaultProvider
+}{fns: map[string]DefaultProvider{
+	"now":      func(*Field, string) (interface{}, error) { return time.Now(), nil },
+	"uuid":     func(*Field, string) (interface{}, error) { return newUUID() },
+	"hostname": func(*Field, string) (interface{}, error) { return os.Hostname() },
+	"env":      func(_ *Field, arg string) (interface{}, error) { return os.Getenv(arg), nil },
+}}
+
+// RegisterDefaultProvider makes fn available to default tags under name,
+// replacing any existing provider with that name. The built in providers are
+// now, uuid (a random version 4 UUID), hostname and env(NAME).
+func RegisterDefaultProvider(name string, fn DefaultProvider) {
+	defaultProviders.Lock()
+	defer defaultProviders.Unlock()
+
+	defaultProviders.fns[name] = fn
+}
+
+func getDefaultProvider(name string) (DefaultProvider, bool) {
+	defaultProviders.RLock()
+	defer defaultProviders.RUnlock()
+
+	fn, ok := defaultProviders.fns[name]
+	return fn, ok
+}
+
+// DefaultProviderCall returns the provider name and argument if the field's
+// default tag is a provider call rather than a literal value.
+func (f *Field) DefaultProviderCall() (string, string, bool) {
+	s, ok := f.DefaultValue()
+	if !ok {
+		return "", "", false
+	}
+
+	return parseDefaultCall(s)
+}
+
+func parseDefaultCall(s string) (string, string, bool) {
+	if !strings.HasSuffix(s, ")") {
+		return "", "", false
+	}
+
+	name, arg, ok := strings.Cut(strings.TrimSuffix(s, ")"), "(")
+	if !ok || name == "" {
+		return "", "", false
+	}
+
+	for _, c := range name {
+		if !validTagNameCharacter(c) {
+			return "", "", false
+		}
+	}
+
+	return name, arg, true
+}
+
+// setDefault sets v to the field's default, calling the default provider if
+// there is one.
+func setDefault(f *Field, v reflect.Value, s string) error {
+	name, arg, ok := parseDefaultCall(s)
+	if !ok {
+		return setFromString(f, v, s)
+	}
+
+	fn, ok := getDefaultProvider(name)
+	if !ok {
+		return fmt.Errorf("unknown default provider %q", name)
+	}
+
+	e, err := fn(f, arg)
+	if err != nil {
+		return fmt.Errorf("default provider %s: %w", name, err)
+	}
+
+	return assignValue(f, v, e, nil, nil)
+}
+
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
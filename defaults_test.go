@@ -0,0 +1,76 @@
+package reflectutil
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDefaultCall(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		name  string
+		arg   string
+		ok    bool
+	}{
+		{"now()", "now", "", true},
+		{"env(HOME)", "env", "HOME", true},
+		{"5s", "", "", false},
+		{"(x)", "", "", false},
+		{"a b()", "", "", false},
+		{"value)", "", "", false},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			a := assert.New(t)
+
+			name, arg, ok := parseDefaultCall(tc.input)
+			a.Equal(tc.name, name)
+			a.Equal(tc.arg, arg)
+			a.Equal(tc.ok, ok)
+		})
+	}
+}
+
+func TestApplyDefaultsProviders(t *testing.T) {
+	a := assert.New(t)
+
+	RegisterDefaultProvider("defaultstest", func(f *Field, arg string) (interface{}, error) {
+		if arg == "fail" {
+			return nil, errors.New("failed")
+		}
+		return 42, nil
+	})
+
+	var v struct {
+		Created  time.Time `default:"now()"`
+		ID       string    `default:"uuid()"`
+		Host     string    `default:"hostname()"`
+		Answer   int64     `default:"defaultstest()"`
+		Existing string    `default:"uuid()"`
+	}
+	v.Existing = "kept"
+
+	before := time.Now()
+	if !a.NoError(ApplyDefaults(&v)) {
+		return
+	}
+
+	a.False(v.Created.Before(before))
+	a.Regexp(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), v.ID)
+	if host, err := os.Hostname(); err == nil {
+		a.Equal(host, v.Host)
+	}
+	a.Equal(int64(42), v.Answer)
+	a.Equal("kept", v.Existing)
+
+	err := ApplyDefaults(&struct {
+		A string `default:"nosuchprovider()"`
+		B int    `default:"defaultstest(fail)"`
+	}{})
+	a.ErrorContains(err, `unknown default provider "nosuchprovider"`)
+	a.ErrorContains(err, "default provider defaultstest: failed")
+}
@@ -0,0 +1,144 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldDependencyGraph records which fields of a struct depend on which
+// others. Its edges come from conditional requirement rules, depends_on
+// parameters (`json:"total,depends_on:Price|Quantity"`), computed defaults
+// whose argument names another field (`default:"slug(Title)"`), and derived
+// fields (`derived:"sha256(Body)"`).
+type FieldDependencyGraph struct {
+	edges map[string][]string
+	order []string
+}
+
+// DependsOn returns the names of the fields that name directly depends on.
+func (g *FieldDependencyGraph) DependsOn(name string) []string { return g.edges[name] }
+
+// Order returns every field name in an order where each field comes after
+// the fields it depends on. Independent fields keep their declaration order.
+func (g *FieldDependencyGraph) Order() []string { return g.order }
+
+// DependencyCycleError is returned when fields depend on each other in a
+// loop. Cycle lists the fields involved, starting and ending with the same
+// one.
+type DependencyCycleError struct {
+	Cycle []string
+}
+
+func (e *DependencyCycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// DependsOn returns the field names listed in depends_on parameters on any of
+// the field's tags.
+func (f *Field) DependsOn() []string {
+	var r []string
+
+	for _, t := range f.tags {
+		for _, p := range t.parameters {
+			if p.name == "depends_on" {
+				r = append(r, strings.Fields(strings.ReplaceAll(p.Value(), "|", " "))...)
+			}
+		}
+	}
+
+	return r
+}
+
+// FieldDependencies builds the dependency graph of the struct's fields,
+// returning a *DependencyCycleError if it isn't acyclic.
+func (s *StructDescription) FieldDependencies() (*FieldDependencyGraph, error) {
+	edges, err := s.ConditionalRuleDependencies()
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.StructDescription.FieldDependencies: %w", err)
+	}
+
+	add := func(from, to string) error {
+		if !s.fields.Has(to) {
+			return fmt.Errorf("reflectutil.StructDescription.FieldDependencies: field %s depends on unknown field %s", from, to)
+		}
+
+		if !containsString(edges[from], to) {
+			edges[from] = append(edges[from], to)
+		}
+
+		return nil
+	}
+
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		for _, name := range f.DependsOn() {
+			if err := add(f.name, name); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, arg, ok := f.DefaultProviderCall(); ok && arg != f.name && s.fields.Has(arg) {
+			if err := add(f.name, arg); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, args, ok := f.Derivation(); ok {
+			for _, name := range args {
+				if err := add(f.name, name); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	g := FieldDependencyGraph{edges: edges}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range stack {
+				if stack[i] == name {
+					cycle := append(append([]string{}, stack[i:]...), name)
+					return fmt.Errorf("reflectutil.StructDescription.FieldDependencies: %w", &DependencyCycleError{Cycle: cycle})
+				}
+			}
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dep := range edges[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		g.order = append(g.order, name)
+
+		return nil
+	}
+
+	for _, f := range s.fields {
+		if err := visit(f.name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &g, nil
+}
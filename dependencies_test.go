@@ -0,0 +1,70 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dependenciesTestOrder struct {
+	Total    int    `json:"total,depends_on:Price|Quantity"`
+	Price    int    `json:"price"`
+	Quantity int    `json:"quantity"`
+	Title    string `json:"title"`
+	Slug     string `default:"slug(Title)"`
+	Notes    string `validate:"required_if=Slug draft"`
+	Created  string `default:"now()"`
+}
+
+func TestFieldDependencies(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(dependenciesTestOrder{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Price", "Quantity"}, d.Field("Total").DependsOn())
+
+	g, err := d.FieldDependencies()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Price", "Quantity"}, g.DependsOn("Total"))
+	a.Equal([]string{"Title"}, g.DependsOn("Slug"))
+	a.Equal([]string{"Slug"}, g.DependsOn("Notes"))
+	a.Nil(g.DependsOn("Created"))
+	a.Equal([]string{"Price", "Quantity", "Total", "Title", "Slug", "Notes", "Created"}, g.Order())
+}
+
+func TestFieldDependenciesErrors(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(struct {
+		A string `x:",depends_on:B"`
+		B string `x:",depends_on:C"`
+		C string `validate:"required_with=A"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, err = d.FieldDependencies()
+	var cycleError *DependencyCycleError
+	if a.True(errors.As(err, &cycleError)) {
+		a.Equal([]string{"A", "B", "C", "A"}, cycleError.Cycle)
+	}
+	a.ErrorContains(err, "dependency cycle: A -> B -> C -> A")
+
+	d, err = GetDescription(struct {
+		A string `x:",depends_on:Missing"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, err = d.FieldDependencies()
+	a.ErrorContains(err, "field A depends on unknown field Missing")
+}
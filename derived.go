@@ -0,0 +1,196 @@
+package reflectutil
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Derivation computes a derived field's value from the values of the fields
+// named in its derived tag, in the order they're listed.
+type Derivation func(f *Field, args []interface{}) (interface{}, error)
+
+var derivations = struct {
+	sync.RWMutex
+	fns map[string]Derivation
+}{fns: map[string]Derivation{
+	"sha256": hashDerivation(sha256.New),
+	"sha1":   hashDerivation(sha1.New),
+	"md5":    hashDerivation(md5.New),
+	"crc32": func(_ *Field, args []interface{}) (interface{}, error) {
+		h := crc32.NewIEEE()
+		if err := writeDerivationArgs(h, args); err != nil {
+			return nil, err
+		}
+		return h.Sum32(), nil
+	},
+	"concat": func(_ *Field, args []interface{}) (interface{}, error) {
+		var b strings.Builder
+		if err := writeDerivationArgs(&b, args); err != nil {
+			return nil, err
+		}
+		return b.String(), nil
+	},
+}}
+
+// RegisterDerivation makes fn available to derived tags under name,
+// replacing any existing derivation with that name. The built in derivations
+// are sha256, sha1 and md5 (hex encoded digests), crc32 (a uint32), and
+// concat, each working on the arguments' text concatenated together.
+func RegisterDerivation(name string, fn Derivation) {
+	derivations.Lock()
+	defer derivations.Unlock()
+
+	derivations.fns[name] = fn
+}
+
+func getDerivation(name string) (Derivation, bool) {
+	derivations.RLock()
+	defer derivations.RUnlock()
+
+	fn, ok := derivations.fns[name]
+	return fn, ok
+}
+
+// Derivation returns the derivation name and the names of the fields it takes
+// as arguments from the field's derived tag, e.g. "sha256" and ["Body"] for
+// `derived:"sha256(Body)"`, or "concat" and ["First", "Last"] for
+// `derived:"concat(First,Last)"`.
+func (f *Field) Derivation() (string, []string, bool) {
+	t := f.tags.Get("derived")
+	if t == nil {
+		return "", nil, false
+	}
+
+	name, arg, ok := parseDefaultCall(t.rawValue())
+	if !ok {
+		return "", nil, false
+	}
+
+	var args []string
+	for _, s := range strings.Split(arg, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			args = append(args, s)
+		}
+	}
+
+	return name, args, true
+}
+
+// ComputeDerived sets every field of v that has a derived tag to the result
+// of its derivation, computing fields in dependency order (see
+// FieldDependencies) so derived fields can build on each other. v must be a
+// pointer to a struct. Failures are reported as *FieldError values, and
+// fields that depend on a failed field are left alone.
+func ComputeDerived(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ComputeDerived: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.ComputeDerived: %w", err)
+	}
+
+	g, err := d.FieldDependencies()
+	if err != nil {
+		return fmt.Errorf("reflectutil.ComputeDerived: %w", err)
+	}
+
+	failed := make(map[string]bool)
+
+	var errs []error
+
+	for _, name := range g.Order() {
+		f := d.fields.Get(name)
+
+		derivation, argNames, ok := f.Derivation()
+		if !ok {
+			continue
+		}
+
+		if err := computeDerived(f, rv, derivation, argNames, d, failed); err != nil {
+			failed[name] = true
+			errs = append(errs, &FieldError{Field: name, Err: err})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.ComputeDerived: %w", err)
+	}
+
+	return nil
+}
+
+func computeDerived(f *Field, rv reflect.Value, name string, argNames []string, d *StructDescription, failed map[string]bool) error {
+	fn, ok := getDerivation(name)
+	if !ok {
+		return fmt.Errorf("unknown derivation %q", name)
+	}
+
+	args := make([]interface{}, len(argNames))
+	for i, argName := range argNames {
+		if failed[argName] {
+			return fmt.Errorf("input %s could not be computed", argName)
+		}
+
+		if fv, ok := fieldValue(rv, d.fields.Get(argName).index); ok {
+			args[i] = fv.Interface()
+		}
+	}
+
+	e, err := fn(f, args)
+	if err != nil {
+		return fmt.Errorf("derivation %s: %w", name, err)
+	}
+
+	fv, err := fieldByIndexAlloc(f, rv)
+	if err != nil {
+		return err
+	}
+
+	return assignValue(f, fv, e, nil, nil)
+}
+
+func hashDerivation(newHash func() hash.Hash) Derivation {
+	return func(_ *Field, args []interface{}) (interface{}, error) {
+		h := newHash()
+		if err := writeDerivationArgs(h, args); err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
+
+// writeDerivationArgs writes the text of each argument to w: byte slices as
+// they are, and anything else as it would be formatted for a tag.
+func writeDerivationArgs(w interface{ Write([]byte) (int, error) }, args []interface{}) error {
+	for i, arg := range args {
+		if b, ok := arg.([]byte); ok {
+			w.Write(b)
+			continue
+		}
+
+		if arg == nil {
+			continue
+		}
+
+		s, err := formatString(nil, reflect.ValueOf(arg))
+		if err != nil {
+			return fmt.Errorf("argument %d: %w", i, err)
+		}
+
+		w.Write([]byte(s))
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package reflectutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type derivedTestDocument struct {
+	Title   string
+	Body    []byte
+	Hash    string `derived:"sha256(Body)"`
+	Key     string `derived:"concat(Title, Hash)"`
+	Check   uint32 `derived:"crc32(Title)"`
+	Version int
+}
+
+func TestFieldDerivation(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(derivedTestDocument{})
+	if !a.NoError(err) {
+		return
+	}
+
+	name, args, ok := d.Field("Key").Derivation()
+	a.True(ok)
+	a.Equal("concat", name)
+	a.Equal([]string{"Title", "Hash"}, args)
+
+	_, _, ok = d.Field("Title").Derivation()
+	a.False(ok)
+
+	g, err := d.FieldDependencies()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Body"}, g.DependsOn("Hash"))
+	a.Equal([]string{"Title", "Hash"}, g.DependsOn("Key"))
+}
+
+func TestComputeDerived(t *testing.T) {
+	a := assert.New(t)
+
+	v := derivedTestDocument{Title: "hello", Body: []byte("world")}
+	if !a.NoError(ComputeDerived(&v)) {
+		return
+	}
+
+	a.Equal("486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7", v.Hash)
+	a.Equal("hello"+v.Hash, v.Key)
+	a.Equal(uint32(0x3610a686), v.Check)
+
+	a.ErrorContains(ComputeDerived(v), "reflectutil.ComputeDerived")
+}
+
+func TestComputeDerivedRegistered(t *testing.T) {
+	a := assert.New(t)
+
+	RegisterDerivation("derivedTestUpper", func(f *Field, args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("expected a string")
+		}
+		return strings.ToUpper(s), nil
+	})
+
+	var v struct {
+		Name  string
+		Num   int
+		Upper string `derived:"derivedTestUpper(Name)"`
+		Count string `derived:"derivedTestUpper(Num)"`
+		Other string `derived:"derivedTestUpper(Count)"`
+		Bad   string `derived:"derivedTestMissing(Name)"`
+	}
+	v.Name = "alice"
+
+	err := ComputeDerived(&v)
+	a.Equal("ALICE", v.Upper)
+	a.Equal("", v.Other)
+
+	var fieldError *FieldError
+	if a.True(errors.As(err, &fieldError)) {
+		a.Equal("Count", fieldError.Field)
+	}
+	a.ErrorContains(err, `Bad: unknown derivation "derivedTestMissing"`)
+	a.ErrorContains(err, "Count: derivation derivedTestUpper: expected a string")
+	a.ErrorContains(err, "Other: input Count could not be computed")
+}
+
+func TestComputeDerivedUnknownField(t *testing.T) {
+	a := assert.New(t)
+
+	var v struct {
+		Hash string `derived:"sha256(Missing)"`
+	}
+
+	a.ErrorContains(ComputeDerived(&v), "reflectutil.ComputeDerived")
+
+	var w struct {
+		Hash string `derived:"sha256(Hash)"`
+	}
+
+	a.ErrorContains(ComputeDerived(&w), "dependency cycle: Hash -> Hash")
+}
@@ -0,0 +1,22 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Describe is like GetDescription, but takes the type to describe as a type
+// parameter, e.g. Describe[User](). T may be a struct or a pointer to one. It
+// can be used in package level variable declarations:
+//
+//	var userDescription, _ = reflectutil.Describe[User]()
+func Describe[T any](opts ...Option) (*StructDescription, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	d, err := getDescription(typ, opts, getOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Describe[%s]: could not get description: %w", typ, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,43 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describeTestUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var describeTestDescription, describeTestErr = Describe[describeTestUser]()
+
+func TestDescribe(t *testing.T) {
+	a := assert.New(t)
+
+	if !a.NoError(describeTestErr) {
+		return
+	}
+
+	a.Equal("describeTestUser", describeTestDescription.Name())
+	a.Equal([]string{"ID", "Name"}, describeTestDescription.Fields().Names())
+
+	d1, err := Describe[describeTestUser]()
+	a.NoError(err)
+	d2, err := Describe[*describeTestUser]()
+	a.NoError(err)
+	a.True(d1 == d2)
+
+	d3, err := GetDescriptionFromReflectType(reflect.TypeOf(describeTestUser{}))
+	a.NoError(err)
+	a.True(d1 == d3)
+
+	d4, err := Describe[describeTestUser](WithTagSpans())
+	a.NoError(err)
+	a.False(d1 == d4)
+
+	_, err = Describe[int]()
+	a.ErrorContains(err, "reflectutil.Describe[int]: could not get description")
+}
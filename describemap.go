@@ -0,0 +1,65 @@
+package reflectutil
+
+import (
+	"reflect"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DescribeMap infers a description from a sample map, so dynamic data can be
+// put through the same filtering and naming code as real structs. There's a
+// field for each key, in sorted order, typed after its value (interface{} for
+// nil values). Field names are the keys made into exported identifiers, e.g.
+// "name" becomes "Name", and each field has a json tag holding the original
+// key. Nested map[string]interface{} values get nested descriptions. The
+// description has no name or type.
+func DescribeMap(m map[string]interface{}) *StructDescription {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	d := &StructDescription{fields: make(FieldList, len(keys))}
+
+	for i, k := range keys {
+		f := Field{
+			name:  exportedName(k),
+			index: []int{i},
+			typ:   interfaceType,
+			tags:  TagList{{name: "json", value: k, parameters: ParameterList{}}},
+		}
+
+		if v := m[k]; v != nil {
+			f.typ = reflect.TypeOf(v)
+
+			if sub, ok := v.(map[string]interface{}); ok {
+				f.description = DescribeMap(sub)
+			}
+		}
+
+		d.fields[i] = f
+	}
+
+	stats.descriptionsBuilt.Add(1)
+
+	return d
+}
+
+// exportedName turns s into something usable as an exported field name by
+// upper casing its first letter, or prefixing it with "X" if it doesn't start
+// with one.
+func exportedName(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	switch {
+	case s == "":
+		return "X"
+	case unicode.IsUpper(r):
+		return s
+	case unicode.IsLetter(r):
+		return string(unicode.ToUpper(r)) + s[n:]
+	default:
+		return "X" + s
+	}
+}
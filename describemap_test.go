@@ -0,0 +1,55 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeMap(t *testing.T) {
+	a := assert.New(t)
+
+	d := DescribeMap(map[string]interface{}{
+		"name":    "Jo",
+		"age":     30,
+		"Tags":    []string{"a"},
+		"2fa":     true,
+		"missing": nil,
+		"address": map[string]interface{}{"city": "Perth"},
+	})
+
+	a.Equal([]string{"X2fa", "Tags", "Address", "Age", "Missing", "Name"}, d.Fields().Names())
+
+	for _, tc := range []struct {
+		field, key string
+		typ        reflect.Type
+	}{
+		{"X2fa", "2fa", reflect.TypeOf(true)},
+		{"Tags", "Tags", reflect.TypeOf([]string{})},
+		{"Age", "age", reflect.TypeOf(0)},
+		{"Missing", "missing", reflect.TypeOf((*interface{})(nil)).Elem()},
+		{"Name", "name", reflect.TypeOf("")},
+	} {
+		f := d.Field(tc.field)
+		if a.NotNil(f, tc.field) {
+			a.Equal(tc.typ, f.Type(), tc.field)
+			a.True(f.Exported(), tc.field)
+
+			name, ok := f.EffectiveName("json")
+			a.True(ok, tc.field)
+			a.Equal(tc.key, name, tc.field)
+		}
+	}
+
+	a.Equal([]int{5}, d.Field("Name").Index())
+	a.Nil(d.Field("Name").Description())
+
+	if nested := d.Field("Address").Description(); a.NotNil(nested) {
+		a.Equal([]string{"City"}, nested.Fields().Names())
+	}
+
+	a.Equal(d.Field("Age"), d.FieldByTagValue("json", "age"))
+
+	a.Empty(DescribeMap(nil).Fields())
+}
@@ -0,0 +1,124 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DescriptionProvider builds the descriptions returned by GetDescription and
+// everything built on it, so that frameworks can swap in another backend -
+// generated code, a source parser, or a test double - without changing the
+// code that consumes descriptions.
+type DescriptionProvider interface {
+	Describe(typ reflect.Type, opts ...Option) (*StructDescription, error)
+}
+
+// DescriptionProviderFunc adapts a function to the DescriptionProvider
+// interface.
+type DescriptionProviderFunc func(typ reflect.Type, opts ...Option) (*StructDescription, error)
+
+func (fn DescriptionProviderFunc) Describe(typ reflect.Type, opts ...Option) (*StructDescription, error) {
+	return fn(typ, opts...)
+}
+
+// ReflectDescriptionProvider is the built in, reflection based
+// DescriptionProvider. Other providers can fall back to it for types they
+// don't handle.
+type ReflectDescriptionProvider struct{}
+
+func (ReflectDescriptionProvider) Describe(typ reflect.Type, opts ...Option) (*StructDescription, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("reflectutil.ReflectDescriptionProvider.Describe: input should be struct or pointer to struct")
+	}
+
+	return getDescriptionFromReflectType(typ, getOptions(opts))
+}
+
+var descriptionProvider struct {
+	sync.RWMutex
+	p DescriptionProvider
+}
+
+// SetDescriptionProvider replaces the provider used by GetDescription,
+// returning the previous one. Passing nil restores the built in provider.
+func SetDescriptionProvider(p DescriptionProvider) DescriptionProvider {
+	descriptionProvider.Lock()
+	defer descriptionProvider.Unlock()
+
+	prev := descriptionProvider.p
+	if prev == nil {
+		prev = ReflectDescriptionProvider{}
+	}
+
+	if _, ok := p.(ReflectDescriptionProvider); ok {
+		p = nil
+	}
+
+	descriptionProvider.p = p
+
+	return prev
+}
+
+// getDescriptionProvider returns the custom provider, or nil if the built in
+// one is in use.
+func getDescriptionProvider() DescriptionProvider {
+	descriptionProvider.RLock()
+	defer descriptionProvider.RUnlock()
+
+	return descriptionProvider.p
+}
+
+// FieldSpec is the information NewStructDescription needs about each field.
+type FieldSpec struct {
+	Name string
+	// Index is the field's index sequence, as in reflect.StructField.
+	Index    []int
+	Type     reflect.Type
+	Tag      reflect.StructTag
+	Embedded bool
+}
+
+// NewStructDescription builds a description from field specs rather than by
+// reflecting over typ, for use by other DescriptionProviders. Tags are parsed
+// with opts as usual. typ may be nil for types that only exist as source; if
+// it is a struct, each field's Owner and Path are worked out from it.
+func NewStructDescription(name string, typ reflect.Type, fields []FieldSpec, opts ...Option) (*StructDescription, error) {
+	o := getOptions(opts)
+
+	d := &StructDescription{name: name, typ: typ, fields: make(FieldList, 0, len(fields)), anonymous: typ != nil && typ.Name() == ""}
+
+	var errs []error
+
+	for _, spec := range fields {
+		tags, err := parseTagList(string(spec.Tag), o)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not get tags for field %s: %w", spec.Name, err))
+			continue
+		}
+
+		f := Field{
+			name:     spec.Name,
+			index:    spec.Index,
+			typ:      spec.Type,
+			tags:     tags,
+			owner:    typ,
+			embedded: spec.Embedded,
+		}
+
+		if typ != nil && typ.Kind() == reflect.Struct && len(spec.Index) > 0 {
+			f.owner, f.path = getOwnerAndPath(typ, spec.Index)
+		}
+
+		d.fields = append(d.fields, f)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.NewStructDescription: %w", err)
+	}
+
+	stats.descriptionsBuilt.Add(1)
+
+	return d, nil
+}
@@ -0,0 +1,87 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describerTestGenerated struct {
+	ID   string
+	Name string
+}
+
+type describerTestOther struct {
+	A string `json:"a"`
+}
+
+func TestDescriptionProvider(t *testing.T) {
+	a := assert.New(t)
+
+	generated := reflect.TypeOf(describerTestGenerated{})
+
+	var calls int
+	prev := SetDescriptionProvider(DescriptionProviderFunc(func(typ reflect.Type, opts ...Option) (*StructDescription, error) {
+		calls++
+
+		if typ != generated {
+			return ReflectDescriptionProvider{}.Describe(typ, opts...)
+		}
+
+		return NewStructDescription("describerTestGenerated", generated, []FieldSpec{
+			{Name: "ID", Index: []int{0}, Type: reflect.TypeOf(""), Tag: `json:"id"`},
+			{Name: "Name", Index: []int{1}, Type: reflect.TypeOf(""), Tag: `json:"name,omitempty"`},
+		})
+	}))
+	defer SetDescriptionProvider(prev)
+
+	a.Equal(ReflectDescriptionProvider{}, prev)
+
+	d, err := GetDescription(describerTestGenerated{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("id", d.Field("ID").Tag("json").Value())
+	a.True(d.Field("Name").Tag("json").Parameters().Has("omitempty"))
+
+	m, err := GetFields(describerTestGenerated{ID: "1", Name: "Jo"}, "json")
+	a.NoError(err)
+	a.Equal(map[string]interface{}{"id": "1", "name": "Jo"}, m)
+
+	d, err = GetDescriptionFromReflectType(reflect.TypeOf(describerTestOther{}))
+	a.NoError(err)
+	a.Equal("a", d.Field("A").Tag("json").Value())
+
+	a.Equal(3, calls)
+
+	SetDescriptionProvider(nil)
+	d, err = GetDescription(describerTestGenerated{})
+	a.NoError(err)
+	a.Nil(d.Field("ID").Tag("json"))
+	a.Equal(3, calls)
+}
+
+func TestNewStructDescription(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := NewStructDescription("Source", nil, []FieldSpec{
+		{Name: "A", Type: reflect.TypeOf(0), Tag: `db:"a"`},
+	})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("Source", d.Name())
+	a.Nil(d.Type())
+	a.Equal("a", d.Field("A").Tag("db").Value())
+	a.Nil(d.Field("A").Owner())
+
+	_, err = NewStructDescription("Bad", nil, []FieldSpec{{Name: "A", Tag: `db:"a`}, {Name: "B", Tag: `db:b`}})
+	a.ErrorContains(err, "could not get tags for field A")
+	a.ErrorContains(err, "could not get tags for field B")
+
+	_, err = ReflectDescriptionProvider{}.Describe(nil)
+	a.Error(err)
+}
@@ -0,0 +1,186 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactionMask replaces the values of sensitive fields in output meant for
+// people, such as change logs.
+var RedactionMask = "***"
+
+// Change records a single field that differs between two values.
+type Change struct {
+	// Path is the field's path in Go terms, e.g. "Address.Street".
+	Path string `json:"path"`
+	// Name is the field's path using json names, e.g. "address.street". It is
+	// empty if any part of the path is excluded from json.
+	Name string `json:"name"`
+	// Label is the field's display label, from its label tag.
+	Label string `json:"label,omitempty"`
+	// Sensitive reports whether Old and New have been replaced with
+	// RedactionMask.
+	Sensitive bool        `json:"sensitive,omitempty"`
+	Old       interface{} `json:"old"`
+	New       interface{} `json:"new"`
+}
+
+// Label returns the value of the field's label tag, or an empty string.
+func (f *Field) Label() string {
+	if t := f.tags.Get("label"); t != nil {
+		return t.rawValue()
+	}
+
+	return ""
+}
+
+// Diff compares two values of the same struct type and returns a Change for
+// every exported field that differs, in field order. Nested structs are
+// compared field by field; other values (including slices, maps and structs
+// without exported fields, like time.Time) are compared as a whole.
+func Diff(oldValue, newValue interface{}) ([]Change, error) {
+	ov, err := structValue(oldValue)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Diff: old value: %w", err)
+	}
+
+	nv, err := structValue(newValue)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Diff: new value: %w", err)
+	}
+
+	if ov.Type() != nv.Type() {
+		return nil, fmt.Errorf("reflectutil.Diff: old type %s does not match new type %s", ov.Type(), nv.Type())
+	}
+
+	changes, err := diffValues(ov, nv)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Diff: %w", err)
+	}
+
+	r := make([]Change, len(changes))
+	for i := range changes {
+		r[i] = changes[i].Change
+	}
+
+	return r, nil
+}
+
+// diffChange is a Change along with what the patch formats need to know
+// about it: the json names leading to the field (nil if it has none), and
+// whether it's present in each encoded value, which it isn't if it's
+// promoted through a nil pointer or is zero with omitempty.
+type diffChange struct {
+	Change
+	names                  []string
+	oldPresent, newPresent bool
+}
+
+func diffValues(ov, nv reflect.Value) ([]diffChange, error) {
+	changes := []diffChange{}
+	if err := diffStruct(&changes, ov, nv, "", nil, false); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func diffStruct(changes *[]diffChange, ov, nv reflect.Value, path string, names []string, unnamed bool) error {
+	d, err := GetDescription(ov.Type())
+	if err != nil {
+		return err
+	}
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		ofv, ook := fieldValue(ov, f.index)
+		nfv, nok := fieldValue(nv, f.index)
+
+		fieldName, ok := f.EffectiveName("json")
+
+		fieldPath := joinPath(path, f.name)
+		fieldUnnamed := unnamed || !ok
+
+		var fieldNames []string
+		if !fieldUnnamed {
+			fieldNames = append(append(make([]string, 0, len(names)+1), names...), fieldName)
+		}
+
+		if ook && nok && canDiffNested(ofv, nfv) {
+			if ofv.Kind() == reflect.Ptr {
+				ofv, nfv = ofv.Elem(), nfv.Elem()
+			}
+
+			if err := diffStruct(changes, ofv, nfv, fieldPath, fieldNames, fieldUnnamed); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var o, n interface{}
+		if ook {
+			o = ofv.Interface()
+		}
+		if nok {
+			n = nfv.Interface()
+		}
+
+		if ook == nok && reflect.DeepEqual(o, n) {
+			continue
+		}
+
+		change := Change{
+			Path:      fieldPath,
+			Label:     f.Label(),
+			Sensitive: f.Sensitive(),
+			Old:       o,
+			New:       n,
+		}
+		if !fieldUnnamed {
+			change.Name = strings.Join(fieldNames, ".")
+		}
+		if change.Sensitive {
+			change.Old, change.New = RedactionMask, RedactionMask
+		}
+
+		omitEmpty := false
+		if t := f.tags.Get("json"); t != nil {
+			omitEmpty = t.parameters.Has("omitempty")
+		}
+
+		*changes = append(*changes, diffChange{
+			Change:     change,
+			names:      fieldNames,
+			oldPresent: ook && !(omitEmpty && ofv.IsZero()),
+			newPresent: nok && !(omitEmpty && nfv.IsZero()),
+		})
+	}
+
+	return nil
+}
+
+func canDiffNested(ov, nv reflect.Value) bool {
+	switch ov.Kind() {
+	case reflect.Struct:
+		return hasExportedFields(ov.Type())
+	case reflect.Ptr:
+		return ov.Type().Elem().Kind() == reflect.Struct && hasExportedFields(ov.Type().Elem()) && !ov.IsNil() && !nv.IsNil()
+	default:
+		return false
+	}
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
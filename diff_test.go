@@ -0,0 +1,92 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffTestAudit struct {
+	UpdatedBy string `json:"updatedBy"`
+}
+
+type diffTestAddress struct {
+	Street string `json:"street" label:"Street address"`
+	City   string `json:"city"`
+}
+
+type diffTestUser struct {
+	diffTestAudit
+	Email    string `json:"email" label:"Email address, primary"`
+	Password string `json:"password,redact"`
+	Tags     []string
+	Internal string `json:"-"`
+	Joined   time.Time
+	Address  diffTestAddress  `json:"address"`
+	Billing  *diffTestAddress `json:"billing"`
+	hidden   string
+}
+
+func TestDiff(t *testing.T) {
+	a := assert.New(t)
+
+	before := diffTestUser{
+		diffTestAudit: diffTestAudit{UpdatedBy: "alice"},
+		Email:         "a@example.com",
+		Password:      "old",
+		Tags:          []string{"x"},
+		Internal:      "i1",
+		Address:       diffTestAddress{Street: "1 Main St", City: "Perth"},
+		Billing:       &diffTestAddress{City: "Perth"},
+		hidden:        "h1",
+	}
+
+	after := before
+	after.UpdatedBy = "bob"
+	after.Email = "b@example.com"
+	after.Password = "new"
+	after.Tags = []string{"x", "y"}
+	after.Internal = "i2"
+	after.Joined = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	after.Address.Street = "2 Main St"
+	after.Billing = &diffTestAddress{City: "Sydney"}
+	after.hidden = "h2"
+
+	changes, err := Diff(before, &after)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]Change{
+		{Path: "UpdatedBy", Name: "updatedBy", Old: "alice", New: "bob"},
+		{Path: "Email", Name: "email", Label: "Email address, primary", Old: "a@example.com", New: "b@example.com"},
+		{Path: "Password", Name: "password", Sensitive: true, Old: "***", New: "***"},
+		{Path: "Tags", Name: "Tags", Old: []string{"x"}, New: []string{"x", "y"}},
+		{Path: "Internal", Name: "", Old: "i1", New: "i2"},
+		{Path: "Joined", Name: "Joined", Old: time.Time{}, New: after.Joined},
+		{Path: "Address.Street", Name: "address.street", Label: "Street address", Old: "1 Main St", New: "2 Main St"},
+		{Path: "Billing.City", Name: "billing.city", Old: "Perth", New: "Sydney"},
+	}, changes)
+
+	b, err := json.Marshal(changes[1])
+	if a.NoError(err) {
+		a.JSONEq(`{"path":"Email","name":"email","label":"Email address, primary","old":"a@example.com","new":"b@example.com"}`, string(b))
+	}
+
+	after.Billing = nil
+	changes, err = Diff(before, after)
+	if a.NoError(err) {
+		a.Equal(Change{Path: "Billing", Name: "billing", Old: before.Billing, New: (*diffTestAddress)(nil)}, changes[len(changes)-1])
+	}
+
+	changes, err = Diff(before, before)
+	a.NoError(err)
+	a.Equal([]Change{}, changes)
+
+	_, err = Diff(before, diffTestAddress{})
+	a.ErrorContains(err, "does not match")
+	_, err = Diff(1, before)
+	a.ErrorContains(err, "old value")
+}
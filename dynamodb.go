@@ -0,0 +1,369 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DynamoDB items are represented in the same shape as the DynamoDB JSON wire
+// format: each attribute value is a single-entry map keyed by its type, such
+// as {"S": "hello"}, {"N": "12"} or {"SS": []string{"a", "b"}}. Fields are
+// named and configured by dynamodbav tags, following the AWS SDK:
+//
+//	Name  string   `dynamodbav:"name"`
+//	Tags  []string `dynamodbav:"tags,stringset,omitempty"`
+//	Attrs []string `dynamodbav:",omitemptyelem"`
+
+// DynamoDBTag is the parsed form of a dynamodbav tag.
+type DynamoDBTag struct {
+	// Name is the attribute name, defaulting to the field name.
+	Name          string
+	Skip          bool
+	OmitEmpty     bool
+	OmitEmptyElem bool
+	StringSet     bool
+	NumberSet     bool
+	BinarySet     bool
+	UnixTime      bool
+}
+
+func (f *Field) DynamoDBTag() DynamoDBTag {
+	r := DynamoDBTag{Name: f.name}
+
+	t := f.tags.Get("dynamodbav")
+	if t == nil {
+		return r
+	}
+
+	if t.value == "-" {
+		r.Skip = true
+		return r
+	}
+
+	if t.value != "" {
+		r.Name = t.value
+	}
+
+	r.OmitEmpty = t.parameters.Has("omitempty")
+	r.OmitEmptyElem = t.parameters.Has("omitemptyelem")
+	r.StringSet = t.parameters.Has("stringset")
+	r.NumberSet = t.parameters.Has("numberset")
+	r.BinarySet = t.parameters.Has("binaryset")
+	r.UnixTime = t.parameters.Has("unixtime")
+
+	return r
+}
+
+// ToDynamoDBItem converts v, a struct or pointer to one, into a DynamoDB item.
+func ToDynamoDBItem(v interface{}) (map[string]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToDynamoDBItem: %w", err)
+	}
+
+	m, err := toDynamoDBMap(rv)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToDynamoDBItem: %w", err)
+	}
+
+	return m, nil
+}
+
+func toDynamoDBMap(rv reflect.Value) (map[string]interface{}, error) {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+
+	for _, f := range d.fields {
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		tag := f.DynamoDBTag()
+		if tag.Skip {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (tag.OmitEmpty && fv.IsZero()) {
+			continue
+		}
+
+		av, err := toDynamoDBValue(fv, tag)
+		if err != nil {
+			return nil, &FieldError{Field: f.name, Err: err}
+		}
+
+		m[tag.Name] = av
+	}
+
+	return m, nil
+}
+
+func toDynamoDBValue(v reflect.Value, tag DynamoDBTag) (map[string]interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return map[string]interface{}{"NULL": true}, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		if tag.UnixTime {
+			return map[string]interface{}{"N": strconv.FormatInt(v.Interface().(time.Time).Unix(), 10)}, nil
+		}
+
+		return map[string]interface{}{"S": v.Interface().(time.Time).Format(time.RFC3339Nano)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"S": v.String()}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"BOOL": v.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"N": fmt.Sprint(v.Interface())}, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.IsNil() {
+				return map[string]interface{}{"NULL": true}, nil
+			}
+			return map[string]interface{}{"B": v.Bytes()}, nil
+		}
+
+		if tag.StringSet || tag.NumberSet || tag.BinarySet {
+			return toDynamoDBSet(v, tag)
+		}
+
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return map[string]interface{}{"NULL": true}, nil
+		}
+
+		l := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			if tag.OmitEmptyElem && v.Index(i).IsZero() {
+				continue
+			}
+
+			av, err := toDynamoDBValue(v.Index(i), DynamoDBTag{})
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			l = append(l, av)
+		}
+		return map[string]interface{}{"L": l}, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return map[string]interface{}{"NULL": true}, nil
+		}
+
+		m := make(map[string]interface{})
+		iter := v.MapRange()
+		for iter.Next() {
+			if tag.OmitEmptyElem && iter.Value().IsZero() {
+				continue
+			}
+
+			k := fmt.Sprint(iter.Key().Interface())
+			av, err := toDynamoDBValue(iter.Value(), DynamoDBTag{})
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", k, err)
+			}
+			m[k] = av
+		}
+		return map[string]interface{}{"M": m}, nil
+	case reflect.Struct:
+		m, err := toDynamoDBMap(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"M": m}, nil
+	}
+
+	return nil, fmt.Errorf("can't convert a %s to a DynamoDB attribute value", v.Type())
+}
+
+func toDynamoDBSet(v reflect.Value, tag DynamoDBTag) (map[string]interface{}, error) {
+	if v.Len() == 0 {
+		// DynamoDB doesn't allow empty sets
+		return map[string]interface{}{"NULL": true}, nil
+	}
+
+	switch {
+	case tag.BinarySet:
+		r := make([][]byte, v.Len())
+		for i := range r {
+			if v.Index(i).Kind() != reflect.Slice || v.Index(i).Type().Elem().Kind() != reflect.Uint8 {
+				return nil, fmt.Errorf("binary sets need byte slice items; got %s", v.Index(i).Type())
+			}
+			r[i] = v.Index(i).Bytes()
+		}
+		return map[string]interface{}{"BS": r}, nil
+	case tag.NumberSet:
+		r := make([]string, v.Len())
+		for i := range r {
+			if !isNumberKind(v.Index(i).Kind()) {
+				return nil, fmt.Errorf("number sets need numeric items; got %s", v.Index(i).Type())
+			}
+			r[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return map[string]interface{}{"NS": r}, nil
+	default:
+		r := make([]string, v.Len())
+		for i := range r {
+			if v.Index(i).Kind() != reflect.String {
+				return nil, fmt.Errorf("string sets need string items; got %s", v.Index(i).Type())
+			}
+			r[i] = v.Index(i).String()
+		}
+		sort.Strings(r)
+		return map[string]interface{}{"SS": r}, nil
+	}
+}
+
+// FromDynamoDBItem fills v, which must be a pointer to a struct, from a
+// DynamoDB item. Attributes that don't match any field are subject to
+// WithUnknownKeyPolicy.
+func FromDynamoDBItem(item map[string]interface{}, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromDynamoDBItem: %w", err)
+	}
+
+	plain := make(map[string]interface{}, len(item))
+	for k, av := range item {
+		p, err := fromDynamoDBValue(av)
+		if err != nil {
+			return fmt.Errorf("reflectutil.FromDynamoDBItem: attribute %s: %w", k, err)
+		}
+		plain[k] = p
+	}
+
+	// numbers arrive as strings, so they always need parsing
+	o := getOptions(opts)
+	o.strict = false
+
+	if err := dynamoDBStructFromMap(plain, rv, o); err != nil {
+		return fmt.Errorf("reflectutil.FromDynamoDBItem: %w", err)
+	}
+
+	return nil
+}
+
+// fromDynamoDBValue turns an attribute value into plain Go values. Numbers
+// are left as strings, which assignValue parses into the target type.
+func fromDynamoDBValue(av interface{}) (interface{}, error) {
+	m, ok := av.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return nil, fmt.Errorf("expected a single-entry attribute value map; got %T", av)
+	}
+
+	for k, e := range m {
+		switch k {
+		case "S", "N", "B", "BOOL", "SS", "NS", "BS":
+			return e, nil
+		case "NULL":
+			return nil, nil
+		case "L":
+			l, ok := e.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a list for L; got %T", e)
+			}
+
+			r := make([]interface{}, len(l))
+			for i := range l {
+				p, err := fromDynamoDBValue(l[i])
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", i, err)
+				}
+				r[i] = p
+			}
+			return r, nil
+		case "M":
+			mm, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a map for M; got %T", e)
+			}
+
+			r := make(map[string]interface{}, len(mm))
+			for k, e := range mm {
+				p, err := fromDynamoDBValue(e)
+				if err != nil {
+					return nil, fmt.Errorf("key %s: %w", k, err)
+				}
+				r[k] = p
+			}
+			return r, nil
+		default:
+			return nil, fmt.Errorf("unknown attribute value type %q", k)
+		}
+	}
+
+	return nil, nil
+}
+
+func dynamoDBStructFromMap(m map[string]interface{}, rv reflect.Value, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+
+	for _, f := range d.fields {
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		tag := f.DynamoDBTag()
+		if tag.Skip {
+			continue
+		}
+
+		e, ok := m[tag.Name]
+		if !ok {
+			continue
+		}
+
+		used[tag.Name] = true
+
+		fv, err := fieldByIndexAlloc(&f, rv)
+		if err != nil {
+			return &FieldError{Field: f.name, Err: err}
+		}
+
+		if tag.UnixTime && derefType(fv.Type()) == timeType {
+			if s, ok := e.(string); ok {
+				n, err := strconv.ParseInt(s, 10, 64)
+				if err != nil {
+					return &FieldError{Field: f.name, Err: err}
+				}
+				e = time.Unix(n, 0).UTC()
+			}
+		} else if derefType(fv.Type()) == timeType {
+			if s, ok := e.(string); ok {
+				t, err := time.Parse(time.RFC3339Nano, s)
+				if err != nil {
+					return &FieldError{Field: f.name, Err: err}
+				}
+				e = t
+			}
+		}
+
+		if err := assignValue(&f, fv, e, dynamoDBStructFromMap, o.forKey(tag.Name, f.name)); err != nil {
+			return &FieldError{Field: f.name, Err: err}
+		}
+
+		o.record(f.name, tag.Name, "dynamodb")
+	}
+
+	return unknownKeys(o, m, used)
+}
@@ -0,0 +1,111 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dynamoDBTestBase struct {
+	PK string `dynamodbav:"pk"`
+}
+
+type dynamoDBTestAddress struct {
+	City string `dynamodbav:"city"`
+}
+
+type dynamoDBTestItem struct {
+	dynamoDBTestBase
+	Name     string               `dynamodbav:"name"`
+	Age      int                  `dynamodbav:"age,omitempty"`
+	Tags     []string             `dynamodbav:"tags,stringset"`
+	Scores   []int                `dynamodbav:"scores,numberset"`
+	Notes    []string             `dynamodbav:"notes,omitemptyelem"`
+	Address  *dynamoDBTestAddress `dynamodbav:"address"`
+	Expires  time.Time            `dynamodbav:"expires,unixtime"`
+	Skipped  string               `dynamodbav:"-"`
+	Untagged bool
+}
+
+func TestDynamoDBTag(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(dynamoDBTestItem{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(DynamoDBTag{Name: "age", OmitEmpty: true}, d.Field("Age").DynamoDBTag())
+	a.Equal(DynamoDBTag{Name: "tags", StringSet: true}, d.Field("Tags").DynamoDBTag())
+	a.Equal(DynamoDBTag{Name: "notes", OmitEmptyElem: true}, d.Field("Notes").DynamoDBTag())
+	a.Equal(DynamoDBTag{Name: "Skipped", Skip: true}, d.Field("Skipped").DynamoDBTag())
+	a.Equal(DynamoDBTag{Name: "Untagged"}, d.Field("Untagged").DynamoDBTag())
+}
+
+func TestDynamoDBItem(t *testing.T) {
+	a := assert.New(t)
+
+	expires := time.Unix(1700000000, 0).UTC()
+
+	v := dynamoDBTestItem{
+		dynamoDBTestBase: dynamoDBTestBase{PK: "user#1"},
+		Name:             "Jo",
+		Tags:             []string{"b", "a"},
+		Scores:           []int{3, 1},
+		Notes:            []string{"x", "", "y"},
+		Expires:          expires,
+		Skipped:          "x",
+	}
+
+	m, err := ToDynamoDBItem(v)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{
+		"pk":     map[string]interface{}{"S": "user#1"},
+		"name":   map[string]interface{}{"S": "Jo"},
+		"tags":   map[string]interface{}{"SS": []string{"a", "b"}},
+		"scores": map[string]interface{}{"NS": []string{"3", "1"}},
+		"notes": map[string]interface{}{"L": []interface{}{
+			map[string]interface{}{"S": "x"},
+			map[string]interface{}{"S": "y"},
+		}},
+		"address":  map[string]interface{}{"NULL": true},
+		"expires":  map[string]interface{}{"N": "1700000000"},
+		"Untagged": map[string]interface{}{"BOOL": false},
+	}, m)
+
+	var decoded dynamoDBTestItem
+	err = FromDynamoDBItem(map[string]interface{}{
+		"pk":      map[string]interface{}{"S": "user#2"},
+		"name":    map[string]interface{}{"S": "Sam"},
+		"age":     map[string]interface{}{"N": "41"},
+		"tags":    map[string]interface{}{"SS": []string{"a"}},
+		"scores":  map[string]interface{}{"NS": []string{"7", "8"}},
+		"address": map[string]interface{}{"M": map[string]interface{}{"city": map[string]interface{}{"S": "Perth"}}},
+		"expires": map[string]interface{}{"N": "1700000000"},
+	}, &decoded)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(dynamoDBTestItem{
+		dynamoDBTestBase: dynamoDBTestBase{PK: "user#2"},
+		Name:             "Sam",
+		Age:              41,
+		Tags:             []string{"a"},
+		Scores:           []int{7, 8},
+		Address:          &dynamoDBTestAddress{City: "Perth"},
+		Expires:          expires,
+	}, decoded)
+
+	err = FromDynamoDBItem(map[string]interface{}{"age": map[string]interface{}{"X": "1"}}, &decoded)
+	a.Error(err)
+
+	_, err = ToDynamoDBItem(struct {
+		Bad []int `dynamodbav:",stringset"`
+	}{[]int{1}})
+	a.Error(err)
+}
@@ -0,0 +1,145 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EmbeddedTagPolicy controls what happens when an embedded field and a field
+// promoted from it both carry a tag with the same name, e.g.
+//
+//	type Audit struct {
+//		CreatedAt time.Time `json:"created_at"`
+//	}
+//
+//	type User struct {
+//		Audit `json:",omitempty"`
+//	}
+type EmbeddedTagPolicy int
+
+const (
+	// EmbeddedTagsIgnore leaves promoted fields' tags alone, the same as
+	// reflect does, and records nothing.
+	EmbeddedTagsIgnore EmbeddedTagPolicy = iota
+	// EmbeddedTagsInner keeps the promoted field's tag, but records the
+	// conflict.
+	EmbeddedTagsInner
+	// EmbeddedTagsOuter replaces the promoted field's tag with the embedded
+	// field's.
+	EmbeddedTagsOuter
+	// EmbeddedTagsMerge keeps the promoted field's tag, taking its value from
+	// the embedded field's if it has none, and adds any of the embedded
+	// field's parameters it doesn't already have.
+	EmbeddedTagsMerge
+)
+
+func (p EmbeddedTagPolicy) String() string {
+	switch p {
+	case EmbeddedTagsIgnore:
+		return "Ignore"
+	case EmbeddedTagsInner:
+		return "Inner"
+	case EmbeddedTagsOuter:
+		return "Outer"
+	case EmbeddedTagsMerge:
+		return "Merge"
+	default:
+		return fmt.Sprintf("[UNKNOWN POLICY %d]", int(p))
+	}
+}
+
+// WithEmbeddedTagPolicy controls how tags on embedded fields combine with the
+// same tags on the fields promoted from them. With any policy other than
+// EmbeddedTagsIgnore, each conflict is recorded and available from
+// Field.TagConflicts.
+func WithEmbeddedTagPolicy(policy EmbeddedTagPolicy) Option {
+	return func(o *options) {
+		o.embeddedTagPolicy = policy
+	}
+}
+
+// TagConflict records how a promoted field's tag was resolved against the tag
+// of the same name on an embedded field it was promoted through.
+type TagConflict struct {
+	Policy EmbeddedTagPolicy
+	// Embedded is the path of the embedded field, e.g. "Base.Audit".
+	Embedded string
+	// Inner and Outer are the promoted and embedded fields' tags as they were
+	// before resolution, and Result is the tag the field ended up with.
+	Inner  Tag
+	Outer  Tag
+	Result Tag
+}
+
+func (c TagConflict) String() string {
+	return fmt.Sprintf("%s tag %s: %s", c.Policy, c.Inner.name, c.Embedded)
+}
+
+// TagConflicts returns the conflicts resolved for the field's tags, from the
+// innermost embedded field outwards.
+func (f *Field) TagConflicts() []TagConflict { return f.tagConflicts }
+
+// resolveEmbeddedTags applies policy to every promoted field in fields, which
+// must be in reflect.VisibleFields order. Enclosing embedded fields are
+// visited from the innermost out, so with EmbeddedTagsOuter the outermost tag
+// wins.
+func resolveEmbeddedTags(fields FieldList, policy EmbeddedTagPolicy) {
+	if policy == EmbeddedTagsIgnore {
+		return
+	}
+
+	for i := range fields {
+		f := &fields[i]
+
+		for n := len(f.index) - 1; n > 0; n-- {
+			outer := fields.GetByIndex(f.index[:n])
+			if outer == nil {
+				continue
+			}
+
+			for j := range f.tags {
+				inner := &f.tags[j]
+
+				o := outer.tags.Get(inner.name)
+				if o == nil {
+					continue
+				}
+
+				c := TagConflict{
+					Policy:   policy,
+					Embedded: strings.Join(append(append([]string(nil), outer.path...), outer.name), "."),
+					Inner:    *inner,
+					Outer:    *o,
+				}
+
+				switch policy {
+				case EmbeddedTagsOuter:
+					*inner = *o
+				case EmbeddedTagsMerge:
+					*inner = mergeTags(*inner, *o)
+				}
+
+				c.Result = *inner
+
+				f.tagConflicts = append(f.tagConflicts, c)
+			}
+		}
+	}
+}
+
+func mergeTags(inner, outer Tag) Tag {
+	r := inner
+
+	if r.value == "" {
+		r.value = outer.value
+	}
+
+	r.parameters = append(ParameterList(nil), inner.parameters...)
+	for _, p := range outer.parameters {
+		if !inner.parameters.Has(p.name) {
+			r.parameters = append(r.parameters, p)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,96 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type embeddedTestAudit struct {
+	CreatedBy string `json:"created_by,omitempty" db:"created_by"`
+	Note      string `db:""`
+}
+
+type embeddedTestBase struct {
+	embeddedTestAudit `json:",string" db:"audit,readonly"`
+	ID                int `json:"id"`
+}
+
+type embeddedTestUser struct {
+	embeddedTestBase `db:"base,prefix:b_"`
+	Name             string `json:"name"`
+}
+
+func TestEmbeddedTagPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy    EmbeddedTagPolicy
+		createdBy string
+		note      string
+		conflicts int
+	}{
+		{EmbeddedTagsIgnore, `json:"created_by,omitempty" db:"created_by"`, `db:""`, 0},
+		{EmbeddedTagsInner, `json:"created_by,omitempty" db:"created_by"`, `db:""`, 3},
+		{EmbeddedTagsOuter, `json:",string" db:"base,prefix:b_"`, `db:"base,prefix:b_"`, 3},
+		{EmbeddedTagsMerge, `json:"created_by,omitempty,string" db:"created_by,readonly,prefix:b_"`, `db:"audit,readonly,prefix:b_"`, 3},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			a := assert.New(t)
+
+			d, err := GetDescription(embeddedTestUser{}, WithEmbeddedTagPolicy(tc.policy))
+			if !a.NoError(err) {
+				return
+			}
+
+			a.Equal(tc.createdBy, string(d.Field("CreatedBy").Tags().StructTag()))
+			a.Equal(tc.note, string(d.Field("Note").Tags().StructTag()))
+			a.Len(d.Field("CreatedBy").TagConflicts(), tc.conflicts)
+			a.Empty(d.Field("ID").TagConflicts())
+			a.Empty(d.Field("Name").TagConflicts())
+
+			if tc.conflicts > 0 {
+				c := d.Field("CreatedBy").TagConflicts()[0]
+				a.Equal(tc.policy, c.Policy)
+				a.Equal("embeddedTestBase.embeddedTestAudit", c.Embedded)
+				a.Equal("json", c.Inner.Name())
+				a.Equal("created_by", c.Inner.Value())
+				a.Equal(",string", c.Outer.rawValue())
+			}
+		})
+	}
+}
+
+func TestEmbeddedTagPolicyString(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("Merge", EmbeddedTagsMerge.String())
+	a.Equal("[UNKNOWN POLICY 9]", EmbeddedTagPolicy(9).String())
+}
+
+func TestEmbeddedTagPolicyIterateFields(t *testing.T) {
+	for _, policy := range []EmbeddedTagPolicy{EmbeddedTagsIgnore, EmbeddedTagsInner, EmbeddedTagsOuter, EmbeddedTagsMerge} {
+		t.Run(policy.String(), func(t *testing.T) {
+			a := assert.New(t)
+
+			d, err := GetDescription(embeddedTestUser{}, WithEmbeddedTagPolicy(policy))
+			if !a.NoError(err) {
+				return
+			}
+
+			it, err := IterateFields(embeddedTestUser{}, WithEmbeddedTagPolicy(policy))
+			if !a.NoError(err) {
+				return
+			}
+
+			for it.Next() {
+				f, err := it.Field()
+				if !a.NoError(err) {
+					return
+				}
+
+				want := d.Field(it.Name())
+				a.Equal(want.Tags().StructTag(), f.Tags().StructTag(), it.Name())
+				a.Equal(want.TagConflicts(), f.TagConflicts(), it.Name())
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package reflectutil
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Encryptor encrypts and decrypts field values for EncryptFields and
+// DecryptFields.
+type Encryptor interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+var encryptors = struct {
+	sync.RWMutex
+	encryptors map[string]Encryptor
+}{encryptors: map[string]Encryptor{}}
+
+// RegisterEncryptor makes e responsible for fields tagged with the given
+// name, e.g. `encrypt:"aes"`, replacing any existing encryptor for it.
+func RegisterEncryptor(name string, e Encryptor) {
+	encryptors.Lock()
+	defer encryptors.Unlock()
+
+	encryptors.encryptors[name] = e
+}
+
+func getEncryptor(name string) (Encryptor, bool) {
+	encryptors.RLock()
+	defer encryptors.RUnlock()
+
+	e, ok := encryptors.encryptors[name]
+	return e, ok
+}
+
+// Encryption returns the name of the encryptor given by the field's encrypt
+// tag, if it has one.
+func (f *Field) Encryption() (string, bool) {
+	t := f.tags.Get("encrypt")
+	if t == nil || t.value == "" || t.value == "-" {
+		return "", false
+	}
+
+	return t.value, true
+}
+
+// EncryptFields replaces the value of every string or []byte field of v that
+// has an encrypt tag with its encryption by the registered Encryptor. Strings
+// hold the base64 encoded ciphertext. Empty values are left alone, so they
+// stay empty. v must be a pointer to a struct; nested structs are handled
+// too. Failures are reported as *FieldError values.
+func EncryptFields(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.EncryptFields: %w", err)
+	}
+
+	if err := transformEncrypted(rv, true); err != nil {
+		return fmt.Errorf("reflectutil.EncryptFields: %w", err)
+	}
+
+	return nil
+}
+
+// DecryptFields reverses EncryptFields.
+func DecryptFields(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.DecryptFields: %w", err)
+	}
+
+	if err := transformEncrypted(rv, false); err != nil {
+		return fmt.Errorf("reflectutil.DecryptFields: %w", err)
+	}
+
+	return nil
+}
+
+func transformEncrypted(rv reflect.Value, encrypt bool) error {
+	return walkValue(rv, "", func(f *Field, v reflect.Value, path string) (bool, error) {
+		name, ok := f.Encryption()
+		if !ok {
+			return true, nil
+		}
+
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if !v.CanSet() || v.IsZero() {
+			return false, nil
+		}
+
+		isBytes := v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+		if v.Kind() != reflect.String && !isBytes {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("can't encrypt %s; only strings and byte slices", v.Type())}
+		}
+
+		e, ok := getEncryptor(name)
+		if !ok {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("no encryptor registered for %q", name)}
+		}
+
+		if encrypt {
+			in := []byte(v.String())
+			if isBytes {
+				in = v.Bytes()
+			}
+
+			out, err := e.Encrypt(in)
+			if err != nil {
+				return false, &FieldError{Field: path, Err: fmt.Errorf("encrypting: %w", err)}
+			}
+
+			if isBytes {
+				v.SetBytes(out)
+			} else {
+				v.SetString(base64.StdEncoding.EncodeToString(out))
+			}
+
+			return false, nil
+		}
+
+		var ciphertext []byte
+		if isBytes {
+			ciphertext = v.Bytes()
+		} else {
+			b, err := base64.StdEncoding.DecodeString(v.String())
+			if err != nil {
+				return false, &FieldError{Field: path, Err: fmt.Errorf("decoding ciphertext: %w", err)}
+			}
+			ciphertext = b
+		}
+
+		out, err := e.Decrypt(ciphertext)
+		if err != nil {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("decrypting: %w", err)}
+		}
+
+		if isBytes {
+			v.SetBytes(out)
+		} else {
+			v.SetString(string(out))
+		}
+
+		return false, nil
+	})
+}
+
+// AESGCM is an Encryptor using AES in GCM mode. Each ciphertext starts with
+// the random nonce it was sealed with.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM using key, which must be 16, 24 or 32 bytes
+// long to select AES-128, AES-192 or AES-256.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.NewAESGCM: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.NewAESGCM: %w", err)
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+func (e *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plaintext)+e.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("reflectutil.AESGCM.Encrypt: %w", err)
+	}
+
+	return e.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (e *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < e.aead.NonceSize() {
+		return nil, errors.New("reflectutil.AESGCM.Decrypt: ciphertext is too short")
+	}
+
+	n := e.aead.NonceSize()
+
+	r, err := e.aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.AESGCM.Decrypt: %w", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,109 @@
+package reflectutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type encryptTestReverse struct{}
+
+func (encryptTestReverse) Encrypt(p []byte) ([]byte, error) { return encryptTestReversed(p), nil }
+func (encryptTestReverse) Decrypt(c []byte) ([]byte, error) { return encryptTestReversed(c), nil }
+
+func encryptTestReversed(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+type encryptTestProfile struct {
+	SSN string `encrypt:"encryptTestAES"`
+}
+
+type encryptTestUser struct {
+	Name    string
+	Email   string  `encrypt:"encryptTestReverse"`
+	Token   []byte  `encrypt:"encryptTestReverse"`
+	Phone   *string `encrypt:"encryptTestReverse"`
+	Empty   string  `encrypt:"encryptTestReverse"`
+	Profile encryptTestProfile
+}
+
+func TestEncryptFields(t *testing.T) {
+	a := assert.New(t)
+
+	key := bytes.Repeat([]byte{1}, 32)
+	aes, err := NewAESGCM(key)
+	if !a.NoError(err) {
+		return
+	}
+
+	RegisterEncryptor("encryptTestAES", aes)
+	RegisterEncryptor("encryptTestReverse", encryptTestReverse{})
+
+	phone := "0400"
+	u := encryptTestUser{
+		Name:    "Jo",
+		Email:   "jo@example.com",
+		Token:   []byte("abc"),
+		Phone:   &phone,
+		Profile: encryptTestProfile{SSN: "123-45-6789"},
+	}
+
+	if !a.NoError(EncryptFields(&u)) {
+		return
+	}
+
+	a.Equal("Jo", u.Name)
+	a.Equal("bW9jLmVscG1heGVAb2o=", u.Email)
+	a.Equal([]byte("cba"), u.Token)
+	a.Equal("MDA0MA==", phone)
+	a.Equal("", u.Empty)
+	a.NotEqual("123-45-6789", u.Profile.SSN)
+
+	if !a.NoError(DecryptFields(&u)) {
+		return
+	}
+
+	a.Equal("jo@example.com", u.Email)
+	a.Equal([]byte("abc"), u.Token)
+	a.Equal("0400", phone)
+	a.Equal("123-45-6789", u.Profile.SSN)
+
+	u.Profile.SSN = "bm90IHNlYWxlZA=="
+	a.ErrorContains(DecryptFields(&u), "Profile.SSN: decrypting: reflectutil.AESGCM.Decrypt")
+
+	u.Profile.SSN = "!"
+	a.ErrorContains(DecryptFields(&u), "Profile.SSN: decoding ciphertext")
+
+	a.Error(EncryptFields(u))
+
+	_, err = NewAESGCM([]byte("short"))
+	a.Error(err)
+}
+
+func TestEncryptFieldsErrors(t *testing.T) {
+	a := assert.New(t)
+
+	RegisterEncryptor("encryptTestFailing", encryptTestFailing{})
+
+	err := EncryptFields(&struct {
+		A int    `encrypt:"encryptTestReverse"`
+		B string `encrypt:"encryptTestMissing"`
+		C string `encrypt:"encryptTestFailing"`
+	}{A: 1, B: "b", C: "c"})
+
+	a.ErrorContains(err, "A: can't encrypt int; only strings and byte slices")
+	a.ErrorContains(err, `B: no encryptor registered for "encryptTestMissing"`)
+	a.ErrorContains(err, "C: encrypting: nope")
+}
+
+type encryptTestFailing struct{}
+
+func (encryptTestFailing) Encrypt([]byte) ([]byte, error) { return nil, errors.New("nope") }
+func (encryptTestFailing) Decrypt([]byte) ([]byte, error) { return nil, errors.New("nope") }
@@ -0,0 +1,79 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Enum returns the values permitted for the field, declared either with an
+// enum tag (`enum:"a|b|c"`) or with a oneof parameter on any other tag
+// (`validate:"required,oneof:a|b|c"`). It returns nil if neither is present.
+func (f *Field) Enum() []string {
+	if t := f.tags.Get("enum"); t != nil {
+		return t.ValueList("|")
+	}
+
+	for _, t := range f.tags {
+		if p := t.parameters.Get("oneof"); p != nil {
+			if strings.Contains(p.Value(), "|") {
+				return p.ValueList("|")
+			}
+
+			return p.ValueList(" ")
+		}
+	}
+
+	return nil
+}
+
+// ValidateEnums checks that every field of v that declares an enum holds one
+// of the permitted values. Zero values (and nil pointers) are not checked, as
+// requiring a value is a separate concern. Problems are reported as a joined
+// list of *FieldError.
+func ValidateEnums(v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ValidateEnums: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.ValidateEnums: %w", err)
+	}
+
+	var errs []error
+
+	for _, f := range d.fields {
+		allowed := f.Enum()
+		if allowed == nil {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || fv.IsZero() {
+			continue
+		}
+
+		for fv.Kind() == reflect.Ptr {
+			fv = fv.Elem()
+		}
+
+		s := fmt.Sprint(fv)
+
+		found := false
+		for _, e := range allowed {
+			if e == s {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			errs = append(errs, &FieldError{Field: f.name, Err: fmt.Errorf("value %q is not one of %s", s, strings.Join(allowed, ", "))})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type enumTestStruct struct {
+	Color    string  `enum:"red|green|blue"`
+	Size     *string `validate:"required,oneof:s|m|l"`
+	Level    int     `validate:",oneof:1 2 3"`
+	Free     string
+	NotOneOf string `validate:",oneof"`
+}
+
+func TestFieldEnum(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(enumTestStruct{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"red", "green", "blue"}, d.Field("Color").Enum())
+	a.Equal([]string{"s", "m", "l"}, d.Field("Size").Enum())
+	a.Equal([]string{"1", "2", "3"}, d.Field("Level").Enum())
+	a.Nil(d.Field("Free").Enum())
+	a.Equal([]string{}, d.Field("NotOneOf").Enum())
+}
+
+func TestValidateEnums(t *testing.T) {
+	large, huge := "l", "xl"
+
+	for _, tc := range []struct {
+		name   string
+		input  interface{}
+		fields []string
+	}{
+		{"zero values", enumTestStruct{}, nil},
+		{"valid values", &enumTestStruct{Color: "red", Size: &large, Level: 2}, nil},
+		{"invalid values", enumTestStruct{Color: "pink", Size: &huge, Level: 4, Free: "x"}, []string{"Color", "Size", "Level"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			err := ValidateEnums(tc.input)
+
+			if tc.fields == nil {
+				a.NoError(err)
+				return
+			}
+
+			var fields []string
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var fieldError *FieldError
+				if a.True(errors.As(e, &fieldError)) {
+					fields = append(fields, fieldError.Field)
+				}
+			}
+
+			a.Equal(tc.fields, fields)
+			a.ErrorContains(err, `Color: value "pink" is not one of red, green, blue`)
+		})
+	}
+
+	assert.Error(t, ValidateEnums("x"))
+}
@@ -0,0 +1,11 @@
+package reflectutil
+
+// FieldError wraps an error relating to a particular field, identified by its
+// path from the root struct (e.g. "Address.Street").
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Err.Error() }
+func (e *FieldError) Unwrap() error { return e.Err }
@@ -0,0 +1,266 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCriterion compiles a selection expression into a Criterion, so that
+// field selections can come from configuration rather than code:
+//
+//	has(json) && !value(sql,"-") && param(sql,index)
+//
+// Expressions are made of calls combined with !, && and ||, in decreasing
+// order of precedence, and grouped with parentheses. The calls are:
+//
+//	has(tag)           HasTag
+//	value(tag, value)  HasTagValue
+//	param(tag, param)  HasParameter
+//	prefix(prefix)     HasTagPrefix
+//	exported()         IsExported
+//
+// Arguments are either bare words (letters, digits, and any of "_-.") or
+// double-quoted Go strings.
+func ParseCriterion(expr string) (Criterion, error) {
+	p := exprParser{input: expr}
+
+	c, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ParseCriterion: %w", err)
+	}
+
+	return c, nil
+}
+
+// ParseSelector is like ParseCriterion, but returns a FieldSelector.
+func ParseSelector(expr string) (*FieldSelector, error) {
+	p := exprParser{input: expr}
+
+	c, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ParseSelector: %w", err)
+	}
+
+	return CompileSelector(c), nil
+}
+
+type exprCall struct {
+	args int
+	fn   func(args []string) Criterion
+}
+
+var exprCalls = map[string]exprCall{
+	"has":      {1, func(args []string) Criterion { return HasTag(args[0]) }},
+	"value":    {2, func(args []string) Criterion { return HasTagValue(args[0], args[1]) }},
+	"param":    {2, func(args []string) Criterion { return HasParameter(args[0], args[1]) }},
+	"prefix":   {1, func(args []string) Criterion { return HasTagPrefix(args[0]) }},
+	"exported": {0, func(args []string) Criterion { return IsExported() }},
+}
+
+type exprParser struct {
+	input string
+	pos   int
+}
+
+func (p *exprParser) parse() (Criterion, error) {
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:p.pos+1])
+	}
+
+	return c, nil
+}
+
+func (p *exprParser) parseOr() (Criterion, error) {
+	c, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		c = AnyOf(c, r)
+	}
+
+	return c, nil
+}
+
+func (p *exprParser) parseAnd() (Criterion, error) {
+	c, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("&&") {
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		l := c
+		c = func(f *Field) bool { return l(f) && r(f) }
+	}
+
+	return c, nil
+}
+
+func (p *exprParser) parseNot() (Criterion, error) {
+	if p.consume("!") {
+		c, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return Not(c), nil
+	}
+
+	if p.consume("(") {
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+
+		return c, nil
+	}
+
+	return p.parseCall()
+}
+
+func (p *exprParser) parseCall() (Criterion, error) {
+	p.skipSpace()
+
+	start := p.pos
+
+	name := p.word()
+	if name == "" {
+		if p.pos == len(p.input) {
+			return nil, p.errorf("unexpected end of expression")
+		}
+
+		return nil, p.errorf("unexpected %q", p.input[p.pos:p.pos+1])
+	}
+
+	call, ok := exprCalls[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown function %q", name)
+	}
+
+	if !p.consume("(") {
+		return nil, p.errorf("expected ( after %s", name)
+	}
+
+	var args []string
+
+	if !p.consume(")") {
+		for {
+			arg, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, arg)
+
+			if p.consume(")") {
+				break
+			}
+
+			if !p.consume(",") {
+				return nil, p.errorf("expected , or )")
+			}
+		}
+	}
+
+	if len(args) != call.args {
+		p.pos = start
+		return nil, p.errorf("%s takes %d arguments; got %d", name, call.args, len(args))
+	}
+
+	return call.fn(args), nil
+}
+
+func (p *exprParser) parseArg() (string, error) {
+	p.skipSpace()
+
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		start := p.pos
+
+		for p.pos++; p.pos < len(p.input) && p.input[p.pos] != '"'; p.pos++ {
+			if p.input[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+
+		if p.pos >= len(p.input) {
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		}
+
+		p.pos++
+
+		s, err := strconv.Unquote(p.input[start:p.pos])
+		if err != nil {
+			p.pos = start
+			return "", p.errorf("invalid string: %w", err)
+		}
+
+		return s, nil
+	}
+
+	s := p.word()
+	if s == "" {
+		return "", p.errorf("expected argument")
+	}
+
+	return s, nil
+}
+
+func (p *exprParser) word() string {
+	start := p.pos
+
+	for p.pos < len(p.input) && isExprWordByte(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func isExprWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+// consume skips any whitespace, then the token s if it's next.
+func (p *exprParser) consume(s string) bool {
+	p.skipSpace()
+
+	if !strings.HasPrefix(p.input[p.pos:], s) {
+		return false
+	}
+
+	p.pos += len(s)
+
+	return true
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: "+format, append([]interface{}{p.pos}, args...)...)
+}
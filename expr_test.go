@@ -0,0 +1,65 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(selectorTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		expr   string
+		result []string
+	}{
+		{`has(db)`, []string{"ID", "Name", "Password", "private"}},
+		{`has(db) && exported()`, []string{"ID", "Name", "Password"}},
+		{`value(api, "public")`, []string{"ID", "Name"}},
+		{`has(db) && !value(api,internal)`, []string{"ID", "Name", "private"}},
+		{`prefix(swagger_)`, []string{"Age"}},
+		{`param(json,omitempty)`, []string{"Name"}},
+		{`value(api,internal) || has(swagger_min)`, []string{"Password", "Age"}},
+		{`has(json) && (param(json,omitempty) || has(swagger_min))`, []string{"Name", "Age"}},
+		{`has(json) && param(json,omitempty) || has(swagger_min)`, []string{"Name", "Age"}},
+		{`!!has(json)`, []string{"Name", "Age"}},
+		{`!(has(db) || has(json))`, []string{"selectorTestBase"}},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			a := assert.New(t)
+
+			s, err := ParseSelector(tc.expr)
+			if a.NoError(err) {
+				a.Equal(tc.result, s.Apply(d).Names())
+			}
+		})
+	}
+}
+
+func TestParseCriterionErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		err  string
+	}{
+		{``, "offset 0: unexpected end of expression"},
+		{`has(db) &&`, "offset 10: unexpected end of expression"},
+		{`has(db`, "offset 6: expected , or )"},
+		{`has db`, "offset 4: expected ( after has"},
+		{`nope(db)`, `offset 0: unknown function "nope"`},
+		{`has(db, json)`, "offset 0: has takes 1 arguments; got 2"},
+		{`value(api, "public)`, "offset 11: unterminated string"},
+		{`(has(db)`, "offset 8: expected )"},
+		{`has(db) json`, `offset 8: unexpected "j"`},
+		{`has(,)`, "offset 4: expected argument"},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := ParseCriterion(tc.expr)
+			assert.EqualError(t, err, "reflectutil.ParseCriterion: "+tc.err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package reflectutil
+
+import (
+	"strings"
+)
+
+// DefaultExtensionPrefix is the tag name prefix that marks passthrough tags
+// when Extensions is given an empty prefix.
+var DefaultExtensionPrefix = "x_"
+
+// Extensions returns the raw values of every tag whose name starts with
+// prefix (or DefaultExtensionPrefix if prefix is empty), keyed by the rest of
+// the name, e.g. {"owner": "billing"} for `x_owner:"billing"`. These tags
+// need no registration; they're carried along for tools that understand
+// them. If a tag is repeated, the first one wins.
+func (f *Field) Extensions(prefix string) map[string]string {
+	if prefix == "" {
+		prefix = DefaultExtensionPrefix
+	}
+
+	r := make(map[string]string)
+
+	for _, t := range f.tags.WithPrefix(prefix) {
+		name := strings.TrimPrefix(t.name, prefix)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := r[name]; !ok {
+			r[name] = t.rawValue()
+		}
+	}
+
+	return r
+}
+
+// VendorExtensions returns the field's Extensions in the form JSON Schema and
+// OpenAPI documents use for vendor extensions: keyed by "x-" and the name
+// with underscores turned into dashes, e.g. {"x-owner-team": "billing"} for
+// `x_owner_team:"billing"`. The result can be merged into a generated schema
+// for the field as is.
+func (f *Field) VendorExtensions(prefix string) map[string]interface{} {
+	r := make(map[string]interface{})
+
+	for name, value := range f.Extensions(prefix) {
+		r["x-"+strings.ReplaceAll(name, "_", "-")] = value
+	}
+
+	return r
+}
@@ -0,0 +1,27 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type extensionsTestModel struct {
+	Name string `json:"name" x_owner_team:"billing" x_deprecated:"true" x_note:"a, b" x_:"ignored" vendor_logo:"logo.png"`
+	Bare string `json:"bare"`
+}
+
+func TestExtensions(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(extensionsTestModel{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]string{"owner_team": "billing", "deprecated": "true", "note": "a, b"}, d.Field("Name").Extensions(""))
+	a.Equal(map[string]string{"logo": "logo.png"}, d.Field("Name").Extensions("vendor_"))
+	a.Empty(d.Field("Bare").Extensions(""))
+
+	a.Equal(map[string]interface{}{"x-owner-team": "billing", "x-deprecated": "true", "x-note": "a, b"}, d.Field("Name").VendorExtensions(""))
+}
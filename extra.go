@@ -0,0 +1,159 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// IsExtra reports whether the field is the catch-all for keys that don't match
+// any other field, either through an inline-extra parameter on the named tag
+// (`json:",inline-extra"`) or a dedicated `extra:"true"` tag. Only fields
+// whose type is a map with string keys can hold extra keys.
+func (f *Field) IsExtra(tag string) bool {
+	if f.typ.Kind() != reflect.Map || f.typ.Key().Kind() != reflect.String {
+		return false
+	}
+
+	if t := f.tags.Get(tag); tag != "" && t != nil && t.parameters.Has("inline-extra") {
+		return true
+	}
+
+	if t := f.tags.Get("extra"); t != nil {
+		b, err := strconv.ParseBool(t.value)
+		return err == nil && b
+	}
+
+	return false
+}
+
+// ExtraField returns the first exported field that IsExtra for the named tag,
+// or nil if there isn't one.
+func (l FieldList) ExtraField(tag string) *Field {
+	for i := range l {
+		if l[i].Exported() && l[i].IsExtra(tag) {
+			return &l[i]
+		}
+	}
+
+	return nil
+}
+
+// setExtra stores the value of key in the extra field of rv, allocating the
+// map if needed.
+func setExtra(f *Field, rv reflect.Value, key string, value interface{}, fromMap structFromMapFunc, o *options) error {
+	fv, err := fieldByIndexAlloc(f, rv)
+	if err != nil {
+		return err
+	}
+
+	e := reflect.New(fv.Type().Elem()).Elem()
+	if err := assignValue(nil, e, value, fromMap, o); err != nil {
+		return err
+	}
+
+	if fv.IsNil() {
+		fv.Set(reflect.MakeMap(fv.Type()))
+	}
+
+	fv.SetMapIndex(reflect.ValueOf(key).Convert(fv.Type().Key()), e)
+
+	return nil
+}
+
+// GetFields is the inverse of SetFields: it returns the exported fields of v,
+// a struct or pointer to one, keyed by the named tag (or field name). Fields
+// tagged "-", and empty fields with an omitempty parameter, are left out.
+// Nested structs become nested maps, except for those with a text form such
+// as time.Time. The entries of an extra field (see Field.IsExtra) are spread
+// out into the result, without replacing any field of the same name.
+func GetFields(v interface{}, tag string) (map[string]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.GetFields: %w", err)
+	}
+
+	m, err := getFields(rv, tag)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.GetFields: %w", err)
+	}
+
+	return m, nil
+}
+
+func getFields(rv reflect.Value, tag string) (map[string]interface{}, error) {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+
+	var extra reflect.Value
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok {
+			continue
+		}
+
+		if f.IsExtra(tag) {
+			if !extra.IsValid() {
+				extra = fv
+			}
+			continue
+		}
+
+		key := f.name
+		if t := f.tags.Get(tag); tag != "" && t != nil {
+			if t.value == "-" {
+				continue
+			}
+			if t.value != "" {
+				key = t.value
+			}
+			if t.parameters.Has("omitempty") && fv.IsZero() {
+				continue
+			}
+		}
+
+		e, err := getFieldValue(fv, tag)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: key, Err: err})
+			continue
+		}
+
+		m[key] = e
+	}
+
+	if extra.IsValid() {
+		iter := extra.MapRange()
+		for iter.Next() {
+			if k := iter.Key().String(); !hasKey(m, k) {
+				m[k] = iter.Value().Interface()
+			}
+		}
+	}
+
+	return m, errors.Join(errs...)
+}
+
+func hasKey(m map[string]interface{}, k string) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func getFieldValue(v reflect.Value, tag string) (interface{}, error) {
+	if isConfigSection(v) {
+		return getFields(reflect.Indirect(v), tag)
+	}
+
+	return v.Interface(), nil
+}
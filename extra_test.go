@@ -0,0 +1,103 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type extraTestAddress struct {
+	City string `json:"city"`
+}
+
+type extraTestUser struct {
+	Name    string                 `json:"name"`
+	Email   string                 `json:"email,omitempty"`
+	Secret  string                 `json:"-"`
+	Joined  time.Time              `json:"joined"`
+	Address extraTestAddress       `json:"address"`
+	Extra   map[string]interface{} `json:",inline-extra"`
+}
+
+type extraTestTagged struct {
+	Name  string            `json:"name"`
+	Other map[string]string `extra:"true"`
+}
+
+func TestIsExtra(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(extraTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.True(d.Field("Extra").IsExtra("json"))
+	a.False(d.Field("Extra").IsExtra("yaml"))
+	a.False(d.Field("Name").IsExtra("json"))
+	a.Equal("Extra", d.Fields().ExtraField("json").Name())
+	a.Nil(d.Fields().ExtraField("yaml"))
+
+	d, err = GetDescription(extraTestTagged{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.True(d.Field("Other").IsExtra("json"))
+	a.True(d.Field("Other").IsExtra(""))
+}
+
+func TestSetFieldsExtra(t *testing.T) {
+	a := assert.New(t)
+
+	var u extraTestUser
+	a.NoError(SetFields(&u, map[string]interface{}{
+		"name":    "Jo",
+		"age":     30,
+		"address": map[string]interface{}{"city": "Perth"},
+	}, "json", WithUnknownKeyPolicy(UnknownKeysError)))
+	a.Equal(extraTestUser{
+		Name:    "Jo",
+		Address: extraTestAddress{City: "Perth"},
+		Extra:   map[string]interface{}{"age": 30},
+	}, u)
+
+	var tagged extraTestTagged
+	a.NoError(SetFields(&tagged, map[string]interface{}{"name": "Jo", "age": "30"}, "json"))
+	a.Equal(extraTestTagged{Name: "Jo", Other: map[string]string{"age": "30"}}, tagged)
+
+	err := SetFields(&tagged, map[string]interface{}{"age": 30}, "json")
+	a.ErrorContains(err, "age: ")
+}
+
+func TestGetFields(t *testing.T) {
+	a := assert.New(t)
+
+	joined := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	m, err := GetFields(&extraTestUser{
+		Name:    "Jo",
+		Secret:  "x",
+		Joined:  joined,
+		Address: extraTestAddress{City: "Perth"},
+		Extra:   map[string]interface{}{"age": 30, "name": "ignored"},
+	}, "json")
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{
+		"name":    "Jo",
+		"joined":  joined,
+		"address": map[string]interface{}{"city": "Perth"},
+		"age":     30,
+	}, m)
+
+	var u extraTestUser
+	a.NoError(SetFields(&u, m, "json"))
+	a.Equal(map[string]interface{}{"age": 30}, u.Extra)
+
+	_, err = GetFields(1, "json")
+	a.Error(err)
+}
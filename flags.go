@@ -0,0 +1,250 @@
+package reflectutil
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FlagDefinition describes a command line flag bound to a struct field by its
+// flag, usage and shorthand tags:
+//
+//	Port int `flag:"port" shorthand:"p" usage:"port to listen on"`
+//
+// The fields match the arguments of pflag's FlagSet.VarPF, so definitions can
+// be registered on a pflag (or cobra) flag set with:
+//
+//	fs.VarPF(d.Value, d.Name, d.Shorthand, d.Usage).NoOptDefVal = d.NoOptDefVal
+type FlagDefinition struct {
+	Name      string
+	Shorthand string
+	Usage     string
+	// NoOptDefVal is the value used when the flag is given without one; it's
+	// "true" for boolean flags and empty otherwise.
+	NoOptDefVal string
+	Value       *FlagValue
+}
+
+// FlagValue writes parsed flag values straight into a struct field. It
+// implements flag.Value, and pflag.Value through its Type method.
+type FlagValue struct {
+	rv      reflect.Value
+	path    []*Field
+	changed bool
+}
+
+func (v *FlagValue) field() *Field { return v.path[len(v.path)-1] }
+
+// String returns the field's current value, formatted the same way it would be
+// parsed.
+func (v *FlagValue) String() string {
+	if v == nil || !v.rv.IsValid() {
+		return ""
+	}
+
+	fv := v.rv
+	for _, f := range v.path {
+		var ok bool
+		if fv, ok = fieldValue(reflect.Indirect(fv), f.index); !ok {
+			return ""
+		}
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return ""
+		}
+	}
+
+	s, _ := formatString(v.field(), fv)
+
+	return s
+}
+
+// Set parses s into the field, allocating any nil pointers on the way. Slice
+// fields replace their existing value on the first Set and append after that,
+// so the flag can be repeated.
+func (v *FlagValue) Set(s string) error {
+	fv := v.rv
+	for _, f := range v.path {
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+
+		var err error
+		if fv, err = fieldByIndexAlloc(f, fv); err != nil {
+			return err
+		}
+	}
+
+	if v.changed && fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		r := reflect.New(fv.Type()).Elem()
+		if err := setFromString(v.field(), r, s); err != nil {
+			return err
+		}
+		fv.Set(reflect.AppendSlice(fv, r))
+	} else if err := setFromString(v.field(), fv, s); err != nil {
+		return err
+	}
+
+	v.changed = true
+
+	return nil
+}
+
+// Type names the kind of value the flag takes, for pflag's usage output.
+func (v *FlagValue) Type() string {
+	typ := derefType(v.field().typ)
+
+	switch {
+	case typ == durationType:
+		return "duration"
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8:
+		return derefType(typ.Elem()).Kind().String() + "Slice"
+	case typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map:
+		return typ.String()
+	default:
+		return typ.Kind().String()
+	}
+}
+
+// IsBoolFlag lets the flag package accept boolean flags without a value.
+func (v *FlagValue) IsBoolFlag() bool { return derefType(v.field().typ).Kind() == reflect.Bool }
+
+// Changed reports whether Set has been called.
+func (v *FlagValue) Changed() bool { return v.changed }
+
+// Usage returns the value of the field's usage tag, or an empty string.
+func (f *Field) Usage() string {
+	if t := f.tags.Get("usage"); t != nil {
+		return t.rawValue()
+	}
+
+	return ""
+}
+
+// Shorthand returns the value of the field's shorthand tag, or an empty
+// string.
+func (f *Field) Shorthand() string {
+	if t := f.tags.Get("shorthand"); t != nil {
+		return t.value
+	}
+
+	return ""
+}
+
+// FlagDefinitions returns a FlagDefinition for every field of v, which must be
+// a pointer to a struct, that has a flag tag. Nested structs are searched too,
+// with their flags' names prefixed by the nested field's flag tag (or its
+// lower cased name) and a dash, e.g. "db-host"; a flag tag of "-" on a nested
+// struct leaves it out. Setting a flag stores the parsed value in v.
+func FlagDefinitions(v interface{}) ([]FlagDefinition, error) {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.FlagDefinitions: %w", err)
+	}
+
+	var defs []FlagDefinition
+	if err := flagDefinitions(rv, rv.Type(), "", nil, &defs); err != nil {
+		return nil, fmt.Errorf("reflectutil.FlagDefinitions: %w", err)
+	}
+
+	seen := make(map[string]string)
+	for _, d := range defs {
+		for _, name := range []string{d.Name, d.Shorthand} {
+			if name == "" {
+				continue
+			}
+			if other, ok := seen[name]; ok {
+				return nil, fmt.Errorf("reflectutil.FlagDefinitions: flag %q is used by both %s and %s", name, other, d.Name)
+			}
+			seen[name] = d.Name
+		}
+	}
+
+	return defs, nil
+}
+
+func flagDefinitions(rv reflect.Value, typ reflect.Type, prefix string, path []*Field, defs *[]FlagDefinition) error {
+	d, err := GetDescription(typ)
+	if err != nil {
+		return err
+	}
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		t := f.tags.Get("flag")
+		if t != nil && t.value == "-" {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], f)
+
+		if isFlagSection(f.typ) {
+			name := strings.ToLower(f.name)
+			if t != nil && t.value != "" {
+				name = t.value
+			}
+
+			if err := flagDefinitions(rv, derefType(f.typ), prefix+name+"-", fieldPath, defs); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if t == nil || t.value == "" {
+			continue
+		}
+
+		def := FlagDefinition{
+			Name:      prefix + t.value,
+			Shorthand: f.Shorthand(),
+			Usage:     f.Usage(),
+			Value:     &FlagValue{rv: rv, path: fieldPath},
+		}
+		if def.Value.IsBoolFlag() {
+			def.NoOptDefVal = "true"
+		}
+
+		*defs = append(*defs, def)
+	}
+
+	return nil
+}
+
+// isFlagSection reports whether fields of type typ should have their own
+// fields turned into flags, rather than being set from a single flag.
+func isFlagSection(typ reflect.Type) bool {
+	typ = derefType(typ)
+
+	if typ.Kind() != reflect.Struct || reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return false
+	}
+
+	return hasExportedFields(typ)
+}
+
+// RegisterFlags defines a flag on fs for each of FlagDefinitions(v). The flag
+// package has no shorthands, so each shorthand is registered as a second
+// flag sharing the same value.
+func RegisterFlags(fs *flag.FlagSet, v interface{}) error {
+	defs, err := FlagDefinitions(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.RegisterFlags: %w", err)
+	}
+
+	for _, d := range defs {
+		fs.Var(d.Value, d.Name, d.Usage)
+		if d.Shorthand != "" {
+			fs.Var(d.Value, d.Shorthand, d.Usage)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package reflectutil
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flagsTestDB struct {
+	Host    string        `flag:"host" usage:"database host, or a socket path"`
+	Timeout time.Duration `flag:"timeout"`
+}
+
+type flagsTestOptions struct {
+	Port     int         `flag:"port" shorthand:"p" usage:"port to listen on"`
+	Verbose  bool        `flag:"verbose" shorthand:"v"`
+	Tags     []string    `flag:"tag"`
+	DB       flagsTestDB `flag:"database"`
+	Replica  *flagsTestDB
+	Skipped  flagsTestDB `flag:"-"`
+	Untagged string
+}
+
+func TestFlagDefinitions(t *testing.T) {
+	a := assert.New(t)
+
+	o := flagsTestOptions{Port: 8080}
+
+	defs, err := FlagDefinitions(&o)
+	if !a.NoError(err) {
+		return
+	}
+
+	var names []string
+	for _, d := range defs {
+		names = append(names, d.Name)
+	}
+	a.Equal([]string{"port", "verbose", "tag", "database-host", "database-timeout", "replica-host", "replica-timeout"}, names)
+
+	a.Equal("p", defs[0].Shorthand)
+	a.Equal("port to listen on", defs[0].Usage)
+	a.Equal("8080", defs[0].Value.String())
+	a.Equal("int", defs[0].Value.Type())
+	a.Equal("", defs[0].NoOptDefVal)
+	a.Equal("true", defs[1].NoOptDefVal)
+	a.Equal("stringSlice", defs[2].Value.Type())
+	a.Equal("database host, or a socket path", defs[3].Usage)
+	a.Equal("duration", defs[4].Value.Type())
+	a.Equal("", defs[5].Value.String())
+
+	a.NoError(defs[5].Value.Set("replica.local"))
+	a.Equal(&flagsTestDB{Host: "replica.local"}, o.Replica)
+	a.True(defs[5].Value.Changed())
+	a.False(defs[3].Value.Changed())
+
+	a.Error(defs[0].Value.Set("x"))
+
+	_, err = FlagDefinitions(o)
+	a.Error(err)
+
+	_, err = FlagDefinitions(&struct {
+		A string `flag:"a"`
+		B string `flag:"b" shorthand:"a"`
+	}{})
+	a.ErrorContains(err, `flag "a" is used by both a and b`)
+}
+
+func TestRegisterFlags(t *testing.T) {
+	a := assert.New(t)
+
+	var o flagsTestOptions
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	if !a.NoError(RegisterFlags(fs, &o)) {
+		return
+	}
+
+	a.NoError(fs.Parse([]string{"-p", "9090", "-v", "--tag", "a,b", "--tag", "c", "--database-host", "db", "--database-timeout", "5s"}))
+	a.Equal(flagsTestOptions{
+		Port:    9090,
+		Verbose: true,
+		Tags:    []string{"a", "b", "c"},
+		DB:      flagsTestDB{Host: "db", Timeout: 5 * time.Second},
+	}, o)
+
+	var usage bytes.Buffer
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	a.Contains(usage.String(), "port to listen on")
+}
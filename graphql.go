@@ -0,0 +1,113 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GraphQLName returns the name of the argument or input object field that the
+// field is bound from: its graphql tag, then its json tag, and otherwise the
+// field name with its first letter lower cased, as is usual in GraphQL
+// schemas. A tag value of "-" excludes the field.
+func (f *Field) GraphQLName() (string, bool) {
+	for _, name := range []string{"graphql", "json"} {
+		t := f.tags.Get(name)
+		if t == nil {
+			continue
+		}
+
+		if t.value == "-" {
+			return "", false
+		}
+
+		if t.value != "" {
+			return t.value, true
+		}
+	}
+
+	r, n := utf8.DecodeRuneInString(f.name)
+
+	return string(unicode.ToLower(r)) + f.name[n:], true
+}
+
+// BindGraphQLArgs fills v, which must be a pointer to a struct, from the
+// argument values a GraphQL resolver receives. Input objects fill nested
+// structs, lists fill slices, and enum values (given as strings) are parsed
+// like any other string; values go through the same coercion rules as
+// SetFields. As GraphQL's input coercion requires, a single value given for a
+// list field becomes a list of one. Arguments that no field asked for are
+// subject to WithUnknownKeyPolicy.
+func BindGraphQLArgs(args map[string]interface{}, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.BindGraphQLArgs: %w", err)
+	}
+
+	if err := graphQLStructFromMap(args, rv, getOptions(opts)); err != nil {
+		return fmt.Errorf("reflectutil.BindGraphQLArgs: %w", err)
+	}
+
+	return nil
+}
+
+func graphQLStructFromMap(m map[string]interface{}, rv reflect.Value, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		key, ok := f.GraphQLName()
+		if !ok {
+			continue
+		}
+
+		e, ok := m[key]
+		if !ok {
+			continue
+		}
+
+		used[key] = true
+
+		if isGraphQLList(f.typ) && e != nil {
+			if k := reflect.TypeOf(e).Kind(); k != reflect.Slice && k != reflect.Array {
+				e = []interface{}{e}
+			}
+		}
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = assignValue(f, fv, e, graphQLStructFromMap, o.forKey(key, f.name))
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: key, Err: err})
+			continue
+		}
+
+		o.record(f.name, key, "graphql")
+	}
+
+	if err := unknownKeys(o, m, used); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func isGraphQLList(typ reflect.Type) bool {
+	typ = derefType(typ)
+
+	return (typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8) || typ.Kind() == reflect.Array
+}
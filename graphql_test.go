@@ -0,0 +1,97 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type graphQLTestFilter struct {
+	Status []string `graphql:"status"`
+	MinAge *int
+}
+
+type graphQLTestInput struct {
+	Title    string               `graphql:"title"`
+	Priority int                  `json:"prio"`
+	Tags     []string             `graphql:"tags"`
+	Filter   graphQLTestFilter    `graphql:"filter"`
+	Children []graphQLTestFilter  `graphql:"children"`
+	Owner    *graphQLTestFilter   `graphql:"owner"`
+	Internal string               `graphql:"-"`
+	Extra    map[string]string    `graphql:"extra"`
+	Kinds    [2]string            `graphql:"kinds"`
+	Nested   *[]graphQLTestFilter `graphql:"nested"`
+}
+
+func TestFieldGraphQLName(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(graphQLTestInput{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for field, expected := range map[string]string{
+		"Title":    "title",
+		"Priority": "prio",
+		"Internal": "",
+	} {
+		name, ok := d.Field(field).GraphQLName()
+		a.Equal(expected, name, field)
+		a.Equal(expected != "", ok, field)
+	}
+
+	d, err = GetDescription(graphQLTestFilter{})
+	if !a.NoError(err) {
+		return
+	}
+
+	name, ok := d.Field("MinAge").GraphQLName()
+	a.True(ok)
+	a.Equal("minAge", name)
+}
+
+func TestBindGraphQLArgs(t *testing.T) {
+	a := assert.New(t)
+
+	var v graphQLTestInput
+	a.NoError(BindGraphQLArgs(map[string]interface{}{
+		"title":    "Hello",
+		"prio":     3,
+		"tags":     "one",
+		"filter":   map[string]interface{}{"status": []interface{}{"OPEN", "CLOSED"}, "minAge": 18},
+		"children": []interface{}{map[string]interface{}{"status": "OPEN"}},
+		"owner":    map[string]interface{}{"minAge": nil},
+		"extra":    map[string]interface{}{"a": "b"},
+		"kinds":    []interface{}{"x", "y"},
+		"nested":   map[string]interface{}{"minAge": 1},
+	}, &v))
+
+	minAge := 18
+	one := 1
+	a.Equal(graphQLTestInput{
+		Title:    "Hello",
+		Priority: 3,
+		Tags:     []string{"one"},
+		Filter:   graphQLTestFilter{Status: []string{"OPEN", "CLOSED"}, MinAge: &minAge},
+		Children: []graphQLTestFilter{{Status: []string{"OPEN"}}},
+		Owner:    &graphQLTestFilter{},
+		Extra:    map[string]string{"a": "b"},
+		Kinds:    [2]string{"x", "y"},
+		Nested:   &[]graphQLTestFilter{{MinAge: &one}},
+	}, v)
+
+	var keys []string
+	a.NoError(BindGraphQLArgs(map[string]interface{}{
+		"title":    "x",
+		"Internal": "x",
+		"filter":   map[string]interface{}{"other": 1},
+	}, &v, WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"filter.other", "Internal"}, keys)
+
+	err := BindGraphQLArgs(map[string]interface{}{"filter": map[string]interface{}{"minAge": "old"}}, &v)
+	a.ErrorContains(err, "reflectutil.BindGraphQLArgs: filter: minAge: ")
+
+	a.Error(BindGraphQLArgs(nil, v))
+}
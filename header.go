@@ -0,0 +1,166 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strings"
+)
+
+// HeaderKey returns the canonical header name given by the field's header
+// tag, defaulting to the field name. A tag value of "-" excludes the field.
+func (f *Field) HeaderKey() (string, bool) {
+	t := f.tags.Get("header")
+	if t == nil || t.value == "-" {
+		return "", false
+	}
+
+	if t.value == "" {
+		return textproto.CanonicalMIMEHeaderKey(f.name), true
+	}
+
+	return textproto.CanonicalMIMEHeaderKey(t.value), true
+}
+
+// ToHeader returns the fields of v that have header tags as an http.Header.
+// Slices become one header value per item, or a single comma separated value
+// with a comma parameter (`header:"Vary,comma"`). Nil pointers are left out,
+// as are zero values of fields with an omitempty parameter.
+func ToHeader(v interface{}) (http.Header, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToHeader: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToHeader: %w", err)
+	}
+
+	h := make(http.Header)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		key, ok := f.HeaderKey()
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			continue
+		}
+
+		t := f.tags.Get("header")
+		if t.parameters.Has("omitempty") && fv.IsZero() {
+			continue
+		}
+
+		values, err := headerValues(f, reflect.Indirect(fv))
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		if t.parameters.Has("comma") {
+			h[key] = append(h[key], strings.Join(values, ", "))
+		} else {
+			h[key] = append(h[key], values...)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.ToHeader: %w", err)
+	}
+
+	return h, nil
+}
+
+func headerValues(f *Field, v reflect.Value) ([]string, error) {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		s, err := formatString(f, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{s}, nil
+	}
+
+	r := make([]string, v.Len())
+	for i := range r {
+		s, err := formatString(f, v.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		r[i] = s
+	}
+
+	return r, nil
+}
+
+// FromHeader fills v, which must be a pointer to a struct, from h. Values are
+// parsed the same way as BindRequest parses headers, except that fields with
+// a comma parameter are split on commas, with surrounding spaces trimmed.
+// Missing headers leave fields untouched.
+func FromHeader(h http.Header, v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromHeader: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromHeader: %w", err)
+	}
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		key, ok := f.HeaderKey()
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		values := h.Values(key)
+		if len(values) == 0 {
+			continue
+		}
+
+		if f.tags.Get("header").parameters.Has("comma") {
+			values = splitHeaderValues(values)
+		}
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = setFromStrings(f, fv, values)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.FromHeader: %w", err)
+	}
+
+	return nil
+}
+
+func splitHeaderValues(values []string) []string {
+	var r []string
+	for _, s := range values {
+		for _, e := range strings.Split(s, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				r = append(r, e)
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+package reflectutil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type headerTestMeta struct {
+	RequestID string        `header:"x-request-id"`
+	Forwarded []string      `header:"X-Forwarded-For"`
+	Vary      []string      `header:"Vary,comma"`
+	Timeout   time.Duration `header:"X-Timeout,omitempty"`
+	Retries   *int          `header:"X-Retries"`
+	Accept    string        `header:""`
+	Ignored   string        `header:"-"`
+	Untagged  string
+}
+
+func TestFieldHeaderKey(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(headerTestMeta{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for field, expected := range map[string]string{
+		"RequestID": "X-Request-Id",
+		"Forwarded": "X-Forwarded-For",
+		"Accept":    "Accept",
+		"Ignored":   "",
+		"Untagged":  "",
+	} {
+		key, ok := d.Field(field).HeaderKey()
+		a.Equal(expected, key, field)
+		a.Equal(expected != "", ok, field)
+	}
+}
+
+func TestToHeader(t *testing.T) {
+	a := assert.New(t)
+
+	h, err := ToHeader(&headerTestMeta{
+		RequestID: "r1",
+		Forwarded: []string{"10.0.0.1", "10.0.0.2"},
+		Vary:      []string{"Accept", "Origin"},
+		Accept:    "text/plain",
+		Ignored:   "x",
+		Untagged:  "x",
+	})
+	a.NoError(err)
+	a.Equal(http.Header{
+		"X-Request-Id":    {"r1"},
+		"X-Forwarded-For": {"10.0.0.1", "10.0.0.2"},
+		"Vary":            {"Accept, Origin"},
+		"Accept":          {"text/plain"},
+	}, h)
+
+	_, err = ToHeader(1)
+	a.Error(err)
+}
+
+func TestFromHeader(t *testing.T) {
+	a := assert.New(t)
+
+	h := http.Header{}
+	h.Set("X-Request-ID", "r1")
+	h.Add("X-Forwarded-For", "10.0.0.1")
+	h.Add("X-Forwarded-For", "10.0.0.2")
+	h.Add("Vary", "Accept, Origin")
+	h.Add("Vary", "Cookie")
+	h.Set("X-Timeout", "5s")
+	h.Set("X-Retries", "3")
+	h.Set("Ignored", "x")
+
+	var v headerTestMeta
+	a.NoError(FromHeader(h, &v))
+
+	retries := 3
+	a.Equal(headerTestMeta{
+		RequestID: "r1",
+		Forwarded: []string{"10.0.0.1", "10.0.0.2"},
+		Vary:      []string{"Accept", "Origin", "Cookie"},
+		Timeout:   5 * time.Second,
+		Retries:   &retries,
+	}, v)
+
+	h, err := ToHeader(v)
+	a.NoError(err)
+
+	var w headerTestMeta
+	a.NoError(FromHeader(h, &w))
+	a.Equal(v, w)
+
+	err = FromHeader(http.Header{"X-Timeout": {"soon"}}, &w)
+	var fieldError *FieldError
+	if a.ErrorAs(err, &fieldError) {
+		a.Equal("Timeout", fieldError.Field)
+	}
+
+	a.Error(FromHeader(h, w))
+}
@@ -0,0 +1,119 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldIterator yields the fields of a struct type one at a time, in the same
+// order as a description's FieldList, without building the list. Tags aren't
+// parsed unless asked for: LookupTag scans for a single tag without
+// allocating, and Field builds the full *Field for the current field. It suits
+// very wide structs when only a few fields are of interest.
+//
+//	it, err := reflectutil.IterateFields(Wide{})
+//	for it.Next() {
+//		if v, ok, _ := it.LookupTag("db"); ok && v == "id" {
+//			f, err := it.Field()
+//			...
+//		}
+//	}
+type FieldIterator struct {
+	typ     reflect.Type
+	fields  []reflect.StructField
+	i       int
+	current reflect.StructField
+	ctx     *describeContext
+}
+
+// IterateFields returns a FieldIterator over the fields of input, a struct,
+// pointer to struct, or reflect.Type of one. The options are used when
+// building each Field.
+func IterateFields(input interface{}, opts ...Option) (*FieldIterator, error) {
+	typ, ok := input.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(input)
+	}
+
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("reflectutil.IterateFields(%T): input should be struct or pointer to struct", input)
+	}
+
+	return &FieldIterator{
+		typ:    typ,
+		fields: visibleFields(typ),
+		ctx:    &describeContext{options: getOptions(opts), inProgress: make(map[reflect.Type]*StructDescription)},
+	}, nil
+}
+
+// Next advances to the next field, returning false when there are no more.
+func (it *FieldIterator) Next() bool {
+	if it.i >= len(it.fields) {
+		return false
+	}
+
+	it.current = it.fields[it.i]
+	it.i++
+
+	return true
+}
+
+func (it *FieldIterator) Name() string       { return it.current.Name }
+func (it *FieldIterator) Index() []int       { return it.current.Index }
+func (it *FieldIterator) Type() reflect.Type { return it.current.Type }
+func (it *FieldIterator) Embedded() bool     { return it.current.Anonymous }
+
+// RawTag returns the current field's struct tag, unparsed.
+func (it *FieldIterator) RawTag() reflect.StructTag { return it.current.Tag }
+
+// LookupTag returns the unquoted value of the current field's tag called
+// name; see the package level LookupTag.
+func (it *FieldIterator) LookupTag(name string) (string, bool, error) {
+	v, ok, err := LookupTag(string(it.current.Tag), name)
+	if err != nil {
+		return "", false, fmt.Errorf("reflectutil.FieldIterator.LookupTag: field %s: %w", it.current.Name, err)
+	}
+
+	return v, ok, nil
+}
+
+// Field builds the *Field for the current field, parsing all of its tags and,
+// if the iterator's options ask for them, its nested description. Promoted
+// fields have WithEmbeddedTagPolicy applied against the embedded fields they
+// were promoted through, as in a description.
+func (it *FieldIterator) Field() (*Field, error) {
+	f, err := getFieldFromStructField(it.typ, it.current, it.ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.FieldIterator.Field: %w", err)
+	}
+
+	if len(f.index) == 1 || it.ctx.options.embeddedTagPolicy == EmbeddedTagsIgnore {
+		return &f, nil
+	}
+
+	// the embedded fields come before the fields promoted from them, so they
+	// have all been seen already
+	var fields FieldList
+	for _, sf := range it.fields[:it.i-1] {
+		if len(sf.Index) >= len(f.index) || !equalIndex(sf.Index, f.index[:len(sf.Index)]) {
+			continue
+		}
+
+		tags, err := parseTagList(string(sf.Tag), it.ctx.options)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.FieldIterator.Field: could not get tags for field %s: %w", sf.Name, err)
+		}
+
+		owner, path := getOwnerAndPath(it.typ, sf.Index)
+		fields = append(fields, Field{name: sf.Name, index: sf.Index, typ: sf.Type, tags: tags, path: path, owner: owner, embedded: sf.Anonymous})
+	}
+
+	fields = append(fields, f)
+	resolveEmbeddedTags(fields, it.ctx.options.embeddedTagPolicy)
+
+	return &fields[len(fields)-1], nil
+}
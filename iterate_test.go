@@ -0,0 +1,89 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type iterateTestBase struct {
+	ID string `db:"id"`
+}
+
+type iterateTestNested struct {
+	City string
+}
+
+type iterateTestWide struct {
+	iterateTestBase
+	Name    string            `db:"name" json:"name,omitempty"`
+	Address iterateTestNested `db:"address"`
+}
+
+func TestIterateFields(t *testing.T) {
+	a := assert.New(t)
+
+	it, err := IterateFields(&iterateTestWide{}, WithNestedDescriptions(1))
+	if !a.NoError(err) {
+		return
+	}
+
+	var names []string
+	for it.Next() {
+		names = append(names, it.Name())
+
+		switch it.Name() {
+		case "iterateTestBase":
+			a.True(it.Embedded())
+		case "ID":
+			a.Equal([]int{0, 0}, it.Index())
+		case "Name":
+			v, ok, err := it.LookupTag("json")
+			a.NoError(err)
+			a.True(ok)
+			a.Equal("name,omitempty", v)
+
+			f, err := it.Field()
+			if a.NoError(err) {
+				a.Equal("name", f.Tag("json").Value())
+				a.True(f.Tag("json").Parameters().Has("omitempty"))
+			}
+		case "Address":
+			a.Equal(reflect.TypeOf(iterateTestNested{}), it.Type())
+
+			f, err := it.Field()
+			if a.NoError(err) && a.NotNil(f.Description()) {
+				a.Equal([]string{"City"}, f.Description().Fields().Names())
+			}
+		}
+	}
+
+	a.Equal([]string{"iterateTestBase", "ID", "Name", "Address"}, names)
+
+	it, err = IterateFields(reflect.StructOf([]reflect.StructField{
+		{Name: "Bad", Type: reflect.TypeOf(""), Tag: `db:"x" $`},
+	}))
+	if !a.NoError(err) || !a.True(it.Next()) {
+		return
+	}
+
+	a.Equal(reflect.StructTag(`db:"x" $`), it.RawTag())
+
+	v, ok, err := it.LookupTag("db")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal("x", v)
+
+	_, _, err = it.LookupTag("json")
+	a.Error(err)
+
+	_, err = it.Field()
+	a.Error(err)
+	a.False(it.Next())
+
+	_, err = IterateFields(1)
+	a.Error(err)
+	_, err = IterateFields(nil)
+	a.Error(err)
+}
@@ -0,0 +1,456 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSON:API documents are driven by jsonapi tags:
+//
+//	ID     string  `jsonapi:"primary,users"`
+//	Name   string  `jsonapi:"attr,name,omitempty"`
+//	Author *Person `jsonapi:"relation,author"`
+//
+// Relation fields must be pointers to structs, or slices of them, with their
+// own primary field.
+
+type jsonapiResource struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id,omitempty"`
+	Attributes    map[string]json.RawMessage     `json:"attributes,omitempty"`
+	Relationships map[string]jsonapiRelationship `json:"relationships,omitempty"`
+}
+
+type jsonapiRelationship struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type jsonapiIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type jsonapiDocument struct {
+	Data     json.RawMessage   `json:"data"`
+	Included []jsonapiResource `json:"included,omitempty"`
+}
+
+// JSONAPIField returns the kind ("primary", "attr" or "relation") and name of
+// the field's jsonapi tag. For primary fields the name is the resource type.
+func (f *Field) JSONAPIField() (kind, name string, ok bool) {
+	t := f.tags.Get("jsonapi")
+	if t == nil || len(t.parameters) == 0 {
+		return "", "", false
+	}
+
+	return t.value, t.parameters[0].name, true
+}
+
+type jsonapiEncoder struct {
+	included []jsonapiResource
+	seen     map[jsonapiIdentifier]bool
+}
+
+// MarshalJSONAPI renders v, a struct (or pointer to one) or a slice of them,
+// as a JSON:API document. Related resources are added to the document's
+// included member once each, unless they are already part of the primary
+// data.
+func MarshalJSONAPI(v interface{}) ([]byte, error) {
+	e := &jsonapiEncoder{seen: make(map[jsonapiIdentifier]bool)}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() != reflect.Struct {
+		rv = rv.Elem()
+	}
+
+	var data interface{}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			id, err := jsonapiIdentify(rv.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: item %d: %w", i, err)
+			}
+			e.seen[id] = true
+		}
+
+		resources := make([]jsonapiResource, rv.Len())
+		for i := range resources {
+			r, err := e.encodeResource(rv.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: item %d: %w", i, err)
+			}
+			resources[i] = *r
+		}
+		data = resources
+	} else {
+		id, err := jsonapiIdentify(rv)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: %w", err)
+		}
+		e.seen[id] = true
+
+		r, err := e.encodeResource(rv)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: %w", err)
+		}
+		data = r
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: %w", err)
+	}
+
+	b, err := json.Marshal(jsonapiDocument{Data: rawData, Included: e.included})
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.MarshalJSONAPI: %w", err)
+	}
+
+	return b, nil
+}
+
+func jsonapiIdentify(v reflect.Value) (jsonapiIdentifier, error) {
+	sv, err := structValue(v)
+	if err != nil {
+		return jsonapiIdentifier{}, err
+	}
+
+	d, err := GetDescription(sv.Type())
+	if err != nil {
+		return jsonapiIdentifier{}, err
+	}
+
+	for _, f := range d.fields {
+		if kind, name, ok := f.JSONAPIField(); ok && kind == "primary" {
+			id := jsonapiIdentifier{Type: name}
+
+			fv, ok := fieldValue(sv, f.index)
+			for ok && fv.Kind() == reflect.Ptr {
+				ok = !fv.IsNil()
+				fv = fv.Elem()
+			}
+
+			if ok && !fv.IsZero() {
+				s, err := formatString(&f, fv)
+				if err != nil {
+					return jsonapiIdentifier{}, fmt.Errorf("%s: %w", f.name, err)
+				}
+				id.ID = s
+			}
+
+			return id, nil
+		}
+	}
+
+	return jsonapiIdentifier{}, fmt.Errorf("%s has no jsonapi primary field", sv.Type())
+}
+
+func (e *jsonapiEncoder) encodeResource(v reflect.Value) (*jsonapiResource, error) {
+	sv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := GetDescription(sv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := jsonapiIdentify(sv)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &jsonapiResource{Type: id.Type, ID: id.ID}
+
+	for _, f := range d.fields {
+		kind, name, ok := f.JSONAPIField()
+		if !ok {
+			continue
+		}
+
+		fv, ok := fieldValue(sv, f.index)
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "primary":
+			// handled by jsonapiIdentify
+		case "attr":
+			if fv.IsZero() && f.Tag("jsonapi").parameters.Has("omitempty") {
+				continue
+			}
+
+			b, err := json.Marshal(fv.Interface())
+			if err != nil {
+				return nil, &FieldError{Field: f.name, Err: err}
+			}
+
+			if r.Attributes == nil {
+				r.Attributes = make(map[string]json.RawMessage)
+			}
+			r.Attributes[name] = b
+		case "relation":
+			rel, err := e.encodeRelationship(fv)
+			if err != nil {
+				return nil, &FieldError{Field: f.name, Err: err}
+			}
+
+			if r.Relationships == nil {
+				r.Relationships = make(map[string]jsonapiRelationship)
+			}
+			r.Relationships[name] = *rel
+		default:
+			return nil, &FieldError{Field: f.name, Err: fmt.Errorf("unknown jsonapi field kind %q", kind)}
+		}
+	}
+
+	return r, nil
+}
+
+func (e *jsonapiEncoder) encodeRelationship(v reflect.Value) (*jsonapiRelationship, error) {
+	encode := func(v reflect.Value) (*jsonapiIdentifier, error) {
+		id, err := jsonapiIdentify(v)
+		if err != nil {
+			return nil, err
+		}
+
+		// marking the resource as seen before encoding it keeps cycles of
+		// related resources from recursing forever.
+		if !e.seen[id] {
+			e.seen[id] = true
+
+			r, err := e.encodeResource(v)
+			if err != nil {
+				return nil, err
+			}
+
+			e.included = append(e.included, *r)
+		}
+
+		return &id, nil
+	}
+
+	var data interface{}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		ids := []jsonapiIdentifier{}
+		for i := 0; i < v.Len(); i++ {
+			if v.Index(i).Kind() == reflect.Ptr && v.Index(i).IsNil() {
+				continue
+			}
+
+			id, err := encode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, *id)
+		}
+		data = ids
+	case reflect.Ptr:
+		if v.IsNil() {
+			return &jsonapiRelationship{Data: json.RawMessage("null")}, nil
+		}
+
+		id, err := encode(v)
+		if err != nil {
+			return nil, err
+		}
+		data = id
+	default:
+		return nil, fmt.Errorf("relation fields should be pointers to structs or slices of them; got %s", v.Type())
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonapiRelationship{Data: b}, nil
+}
+
+type jsonapiDecoder struct {
+	included map[jsonapiIdentifier]*jsonapiResource
+	built    map[jsonapiIdentifier]reflect.Value
+}
+
+// UnmarshalJSONAPI reads a JSON:API document into v, which must be a pointer
+// to a struct or to a slice of them. Relationships are resolved against the
+// document's included resources where possible; otherwise only the related
+// resource's primary field is set.
+func UnmarshalJSONAPI(data []byte, v interface{}) error {
+	var doc jsonapiDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("reflectutil.UnmarshalJSONAPI: %w", err)
+	}
+
+	dec := &jsonapiDecoder{
+		included: make(map[jsonapiIdentifier]*jsonapiResource),
+		built:    make(map[jsonapiIdentifier]reflect.Value),
+	}
+	for i := range doc.Included {
+		r := &doc.Included[i]
+		dec.included[jsonapiIdentifier{r.Type, r.ID}] = r
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("reflectutil.UnmarshalJSONAPI: expected a non-nil pointer; got %T", v)
+	}
+	rv = rv.Elem()
+
+	if rv.Kind() == reflect.Slice {
+		var resources []jsonapiResource
+		if err := json.Unmarshal(doc.Data, &resources); err != nil {
+			return fmt.Errorf("reflectutil.UnmarshalJSONAPI: %w", err)
+		}
+
+		s := reflect.MakeSlice(rv.Type(), len(resources), len(resources))
+		for i := range resources {
+			if err := dec.decodeResource(&resources[i], s.Index(i)); err != nil {
+				return fmt.Errorf("reflectutil.UnmarshalJSONAPI: item %d: %w", i, err)
+			}
+		}
+		rv.Set(s)
+
+		return nil
+	}
+
+	var resource jsonapiResource
+	if err := json.Unmarshal(doc.Data, &resource); err != nil {
+		return fmt.Errorf("reflectutil.UnmarshalJSONAPI: %w", err)
+	}
+
+	if err := dec.decodeResource(&resource, rv); err != nil {
+		return fmt.Errorf("reflectutil.UnmarshalJSONAPI: %w", err)
+	}
+
+	return nil
+}
+
+func (dec *jsonapiDecoder) decodeResource(r *jsonapiResource, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("can't decode a resource into %s", v.Type())
+	}
+
+	d, err := GetDescription(v.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range d.fields {
+		kind, name, ok := f.JSONAPIField()
+		if !ok {
+			continue
+		}
+
+		fv, err := fieldByIndexErr(v, f.index)
+		if err != nil || !fv.CanSet() {
+			continue
+		}
+
+		switch kind {
+		case "primary":
+			if name != r.Type {
+				return fmt.Errorf("expected resource type %q; got %q", name, r.Type)
+			}
+
+			if r.ID != "" {
+				if err := setFromString(&f, fv, r.ID); err != nil {
+					return &FieldError{Field: f.name, Err: err}
+				}
+			}
+		case "attr":
+			raw, ok := r.Attributes[name]
+			if !ok {
+				continue
+			}
+
+			if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+				return &FieldError{Field: f.name, Err: err}
+			}
+		case "relation":
+			rel, ok := r.Relationships[name]
+			if !ok {
+				continue
+			}
+
+			if err := dec.decodeRelationship(rel, fv); err != nil {
+				return &FieldError{Field: f.name, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (dec *jsonapiDecoder) decodeRelationship(rel jsonapiRelationship, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Slice:
+		var ids []jsonapiIdentifier
+		if err := json.Unmarshal(rel.Data, &ids); err != nil {
+			return err
+		}
+
+		s := reflect.MakeSlice(v.Type(), len(ids), len(ids))
+		for i, id := range ids {
+			if err := dec.decodeRelated(id, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Ptr:
+		var id *jsonapiIdentifier
+		if err := json.Unmarshal(rel.Data, &id); err != nil {
+			return err
+		}
+
+		if id == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		return dec.decodeRelated(*id, v)
+	default:
+		return fmt.Errorf("relation fields should be pointers to structs or slices of them; got %s", v.Type())
+	}
+
+	return nil
+}
+
+func (dec *jsonapiDecoder) decodeRelated(id jsonapiIdentifier, v reflect.Value) error {
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("related resources should be pointers to structs; got %s", v.Type())
+	}
+
+	// resources can refer to one another, so each one is only built once and
+	// shared between everything that refers to it. Resources without an ID
+	// are all distinct.
+	if built, ok := dec.built[id]; ok && id.ID != "" && built.Type() == v.Type() {
+		v.Set(built)
+		return nil
+	}
+
+	v.Set(reflect.New(v.Type().Elem()))
+	if id.ID != "" {
+		dec.built[id] = v
+	}
+
+	r, ok := dec.included[id]
+	if !ok {
+		r = &jsonapiResource{Type: id.Type, ID: id.ID}
+	}
+
+	return dec.decodeResource(r, v)
+}
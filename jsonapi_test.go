@@ -0,0 +1,169 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonapiTestPerson struct {
+	ID    int                `jsonapi:"primary,people"`
+	Name  string             `jsonapi:"attr,name"`
+	Posts []*jsonapiTestPost `jsonapi:"relation,posts"`
+}
+
+type jsonapiTestComment struct {
+	ID   string `jsonapi:"primary,comments"`
+	Body string `jsonapi:"attr,body"`
+}
+
+type jsonapiTestPost struct {
+	ID       string                `jsonapi:"primary,posts"`
+	Title    string                `jsonapi:"attr,title"`
+	Summary  string                `jsonapi:"attr,summary,omitempty"`
+	Author   *jsonapiTestPerson    `jsonapi:"relation,author"`
+	Comments []*jsonapiTestComment `jsonapi:"relation,comments"`
+	Editor   *jsonapiTestPerson    `jsonapi:"relation,editor"`
+	Ignored  string
+}
+
+func TestMarshalJSONAPI(t *testing.T) {
+	a := assert.New(t)
+
+	author := &jsonapiTestPerson{ID: 9, Name: "Dan"}
+	post := &jsonapiTestPost{
+		ID:       "1",
+		Title:    "Hello",
+		Author:   author,
+		Comments: []*jsonapiTestComment{{ID: "5", Body: "First"}, {ID: "6", Body: "Second"}},
+		Ignored:  "x",
+	}
+	author.Posts = []*jsonapiTestPost{post}
+
+	b, err := MarshalJSONAPI(post)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.JSONEq(`{
+		"data": {
+			"type": "posts",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": [{"type": "comments", "id": "5"}, {"type": "comments", "id": "6"}]},
+				"editor": {"data": null}
+			}
+		},
+		"included": [
+			{
+				"type": "people",
+				"id": "9",
+				"attributes": {"name": "Dan"},
+				"relationships": {"posts": {"data": [{"type": "posts", "id": "1"}]}}
+			},
+			{"type": "comments", "id": "5", "attributes": {"body": "First"}},
+			{"type": "comments", "id": "6", "attributes": {"body": "Second"}}
+		]
+	}`, string(b))
+
+	b, err = MarshalJSONAPI([]*jsonapiTestComment{{ID: "5", Body: "First"}})
+	if a.NoError(err) {
+		a.JSONEq(`{"data": [{"type": "comments", "id": "5", "attributes": {"body": "First"}}]}`, string(b))
+	}
+
+	_, err = MarshalJSONAPI(struct{ A string }{})
+	a.ErrorContains(err, "has no jsonapi primary field")
+}
+
+func TestUnmarshalJSONAPI(t *testing.T) {
+	a := assert.New(t)
+
+	input := `{
+		"data": {
+			"type": "posts",
+			"id": "1",
+			"attributes": {"title": "Hello", "unknown": true},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": [{"type": "comments", "id": "5"}, {"type": "comments", "id": "7"}]},
+				"editor": {"data": null}
+			}
+		},
+		"included": [
+			{
+				"type": "people",
+				"id": "9",
+				"attributes": {"name": "Dan"},
+				"relationships": {"posts": {"data": [{"type": "posts", "id": "1"}]}}
+			},
+			{"type": "comments", "id": "5", "attributes": {"body": "First"}}
+		]
+	}`
+
+	var post jsonapiTestPost
+	if !a.NoError(UnmarshalJSONAPI([]byte(input), &post)) {
+		return
+	}
+
+	a.Equal("1", post.ID)
+	a.Equal("Hello", post.Title)
+	a.Nil(post.Editor)
+	if a.NotNil(post.Author) {
+		a.Equal(9, post.Author.ID)
+		a.Equal("Dan", post.Author.Name)
+		if a.Len(post.Author.Posts, 1) {
+			a.Equal("1", post.Author.Posts[0].ID)
+		}
+	}
+	a.Equal([]*jsonapiTestComment{{ID: "5", Body: "First"}, {ID: "7"}}, post.Comments)
+
+	var comments []jsonapiTestComment
+	if a.NoError(UnmarshalJSONAPI([]byte(`{"data": [{"type": "comments", "id": "1", "attributes": {"body": "x"}}]}`), &comments)) {
+		a.Equal([]jsonapiTestComment{{ID: "1", Body: "x"}}, comments)
+	}
+
+	a.ErrorContains(UnmarshalJSONAPI([]byte(`{"data": {"type": "people", "id": "1"}}`), &post), `expected resource type "posts"; got "people"`)
+	a.ErrorContains(UnmarshalJSONAPI([]byte(`{"data": {"type": "posts", "id": "1"}}`), post), "expected a non-nil pointer")
+	a.Error(UnmarshalJSONAPI([]byte(`{`), &post))
+}
+
+func TestJSONAPIPointerPrimary(t *testing.T) {
+	a := assert.New(t)
+
+	type Tag struct {
+		ID   *string `jsonapi:"primary,tags"`
+		Name string  `jsonapi:"attr,name"`
+	}
+
+	id := "t1"
+
+	b, err := MarshalJSONAPI(&Tag{ID: &id, Name: "go"})
+	if a.NoError(err) {
+		a.JSONEq(`{"data": {"type": "tags", "id": "t1", "attributes": {"name": "go"}}}`, string(b))
+	}
+
+	b, err = MarshalJSONAPI(&Tag{Name: "new"})
+	if a.NoError(err) {
+		a.JSONEq(`{"data": {"type": "tags", "attributes": {"name": "new"}}}`, string(b))
+	}
+}
+
+func TestUnmarshalJSONAPIWithoutIDs(t *testing.T) {
+	a := assert.New(t)
+
+	var post jsonapiTestPost
+	err := UnmarshalJSONAPI([]byte(`{
+		"data": {
+			"type": "posts",
+			"id": "1",
+			"relationships": {
+				"comments": {"data": [{"type": "comments", "id": ""}, {"type": "comments", "id": ""}]}
+			}
+		}
+	}`), &post)
+	if a.NoError(err) && a.Len(post.Comments, 2) {
+		a.NotSame(post.Comments[0], post.Comments[1])
+	}
+}
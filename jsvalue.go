@@ -0,0 +1,163 @@
+//go:build js && wasm
+
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"syscall/js"
+)
+
+// ToJSValue converts v, a struct or pointer to one, into a JavaScript object
+// keyed by the named tag, usually "js" or "json", following the same rules as
+// GetFields. Nested structs become nested objects, maps become objects,
+// slices and arrays become arrays, and values with a text form, like
+// time.Time, become strings.
+func ToJSValue(v interface{}, tag string) (js.Value, error) {
+	m, err := GetFields(v, tag)
+	if err != nil {
+		return js.Undefined(), fmt.Errorf("reflectutil.ToJSValue: %w", err)
+	}
+
+	r, err := toJSValue(reflect.ValueOf(m), tag)
+	if err != nil {
+		return js.Undefined(), fmt.Errorf("reflectutil.ToJSValue: %w", err)
+	}
+
+	return r, nil
+}
+
+func toJSValue(v reflect.Value, tag string) (js.Value, error) {
+	if !v.IsValid() {
+		return js.Null(), nil
+	}
+
+	if jv, ok := v.Interface().(js.Value); ok {
+		return jv, nil
+	}
+
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return js.Null(), nil
+		}
+
+		return toJSValue(v.Elem(), tag)
+	}
+
+	if isConfigSection(v) {
+		m, err := getFields(v, tag)
+		if err != nil {
+			return js.Undefined(), err
+		}
+
+		return toJSValue(reflect.ValueOf(m), tag)
+	}
+
+	if v.Type() != durationType && !v.Type().Implements(textMarshalerType) {
+		switch v.Kind() {
+		case reflect.Bool:
+			return js.ValueOf(v.Bool()), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return js.ValueOf(v.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return js.ValueOf(v.Uint()), nil
+		case reflect.Float32, reflect.Float64:
+			return js.ValueOf(v.Float()), nil
+		case reflect.String:
+			return js.ValueOf(v.String()), nil
+		case reflect.Slice, reflect.Array:
+			if v.Kind() == reflect.Slice && v.IsNil() {
+				return js.Null(), nil
+			}
+
+			if v.Type().Elem().Kind() == reflect.Uint8 {
+				break
+			}
+
+			r := js.Global().Get("Array").New(v.Len())
+			for i := 0; i < v.Len(); i++ {
+				e, err := toJSValue(v.Index(i), tag)
+				if err != nil {
+					return js.Undefined(), fmt.Errorf("item %d: %w", i, err)
+				}
+				r.SetIndex(i, e)
+			}
+			return r, nil
+		case reflect.Map:
+			if v.IsNil() {
+				return js.Null(), nil
+			}
+
+			r := js.Global().Get("Object").New()
+			iter := v.MapRange()
+			for iter.Next() {
+				k, err := formatString(nil, iter.Key())
+				if err != nil {
+					return js.Undefined(), fmt.Errorf("key %v: %w", iter.Key(), err)
+				}
+
+				e, err := toJSValue(iter.Value(), tag)
+				if err != nil {
+					return js.Undefined(), &FieldError{Field: k, Err: err}
+				}
+				r.Set(k, e)
+			}
+			return r, nil
+		}
+	}
+
+	s, err := formatString(nil, v)
+	if err != nil {
+		return js.Undefined(), err
+	}
+
+	return js.ValueOf(s), nil
+}
+
+// FromJSValue stores the properties of the JavaScript object val in v, which
+// must be a pointer to a struct, the same way as SetFields: properties are
+// matched against the named tag, nested objects fill nested structs, and
+// numbers are converted to the fields' types as long as they fit.
+func FromJSValue(val js.Value, v interface{}, tag string, opts ...Option) error {
+	if val.Type() != js.TypeObject {
+		return fmt.Errorf("reflectutil.FromJSValue: input should be an object; got %s", val.Type())
+	}
+
+	m, _ := fromJSValue(val).(map[string]interface{})
+
+	if err := SetFields(v, m, tag, opts...); err != nil {
+		return fmt.Errorf("reflectutil.FromJSValue: %w", err)
+	}
+
+	return nil
+}
+
+func fromJSValue(v js.Value) interface{} {
+	switch v.Type() {
+	case js.TypeBoolean:
+		return v.Bool()
+	case js.TypeNumber:
+		return v.Float()
+	case js.TypeString:
+		return v.String()
+	case js.TypeObject:
+		if js.Global().Get("Array").Call("isArray", v).Bool() {
+			r := make([]interface{}, v.Length())
+			for i := range r {
+				r[i] = fromJSValue(v.Index(i))
+			}
+			return r
+		}
+
+		keys := js.Global().Get("Object").Call("keys", v)
+
+		r := make(map[string]interface{}, keys.Length())
+		for i := 0; i < keys.Length(); i++ {
+			k := keys.Index(i).String()
+			r[k] = fromJSValue(v.Get(k))
+		}
+		return r
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+//go:build js && wasm
+
+package reflectutil
+
+import (
+	"syscall/js"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsValueTestAddress struct {
+	City string `js:"city"`
+}
+
+type jsValueTestUser struct {
+	Name    string             `js:"name"`
+	Age     int                `js:"age"`
+	Tags    []string           `js:"tags"`
+	Joined  time.Time          `js:"joined"`
+	Address jsValueTestAddress `js:"address"`
+	Skipped string             `js:"-"`
+}
+
+func TestToJSValue(t *testing.T) {
+	a := assert.New(t)
+
+	v, err := ToJSValue(&jsValueTestUser{
+		Name:    "Jo",
+		Age:     30,
+		Tags:    []string{"a", "b"},
+		Joined:  time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Address: jsValueTestAddress{City: "Perth"},
+		Skipped: "x",
+	}, "js")
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("Jo", v.Get("name").String())
+	a.Equal(30, v.Get("age").Int())
+	a.Equal(2, v.Get("tags").Length())
+	a.Equal("b", v.Get("tags").Index(1).String())
+	a.Equal("2023-01-02T03:04:05Z", v.Get("joined").String())
+	a.Equal("Perth", v.Get("address").Get("city").String())
+	a.True(v.Get("skipped").IsUndefined())
+}
+
+func TestFromJSValue(t *testing.T) {
+	a := assert.New(t)
+
+	obj := js.Global().Get("JSON").Call("parse", `{"name":"Jo","age":30,"tags":["a","b"],"address":{"city":"Perth"}}`)
+
+	var u jsValueTestUser
+	if !a.NoError(FromJSValue(obj, &u, "js")) {
+		return
+	}
+
+	a.Equal(jsValueTestUser{Name: "Jo", Age: 30, Tags: []string{"a", "b"}, Address: jsValueTestAddress{City: "Perth"}}, u)
+
+	a.Error(FromJSValue(js.ValueOf(1), &u, "js"))
+	a.Error(FromJSValue(js.Global().Get("JSON").Call("parse", `{"age":1.5}`), &u, "js"))
+}
@@ -0,0 +1,132 @@
+package reflectutil
+
+import (
+	"strings"
+)
+
+// WithFoldedKeys makes SetFields (and the decoders built on it) match input
+// keys to fields ignoring case, underscores and dashes, so "user_id",
+// "userId", "User-ID" and "USERID" all find the same field. Exact matches are
+// preferred, then case-insensitive ones, then folded ones, each trying tag
+// values before aliases before field names. If several keys find the same
+// field, the closest match wins (or the first in sorted order, if they're as
+// close as each other), and the rest are treated as unknown keys.
+func WithFoldedKeys() Option {
+	return func(o *options) {
+		o.foldKeys = true
+	}
+}
+
+// foldKey returns s lower cased, with underscores and dashes removed.
+func foldKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+const (
+	keyMatchExact = iota
+	keyMatchFold
+	keyMatchFolded
+	keyMatchNone
+)
+
+// getByFoldedKey is like getByKey, but falls back to case-insensitive and
+// then folded matches, returning how close the match was.
+func (l FieldList) getByFoldedKey(tag, key string) (*Field, int) {
+	if f := l.getByKey(tag, key); f != nil {
+		return f, keyMatchExact
+	}
+
+	named := func(f *Field) bool { return tag == "" || f.keyedByName(tag) }
+
+	if tag != "" {
+		if f := l.getByTagValue(tag, key, true); f != nil && !f.excludedBy(tag) {
+			return f, keyMatchFold
+		}
+	}
+
+	for i := range l {
+		if named(&l[i]) && strings.EqualFold(l[i].name, key) {
+			return &l[i], keyMatchFold
+		}
+	}
+
+	folded := foldKey(key)
+
+	if tag != "" {
+		for _, aliases := range []bool{false, true} {
+			for i := range l {
+				if !l[i].excludedBy(tag) && l[i].hasFoldedTagValue(tag, folded, aliases) {
+					return &l[i], keyMatchFolded
+				}
+			}
+		}
+	}
+
+	for i := range l {
+		if named(&l[i]) && foldKey(l[i].name) == folded {
+			return &l[i], keyMatchFolded
+		}
+	}
+
+	return nil, keyMatchNone
+}
+
+func (f *Field) hasFoldedTagValue(name, folded string, aliases bool) bool {
+	for _, t := range f.tags {
+		if t.name != name {
+			continue
+		}
+
+		if !aliases {
+			if t.value != "" && foldKey(t.value) == folded {
+				return true
+			}
+
+			continue
+		}
+
+		for _, p := range t.parameters {
+			if p.name == "alias" && p.Value() != "" && foldKey(p.Value()) == folded {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// foldedKeys resolves each of keys (which must be sorted) to a field, leaving
+// out every key but the closest match for each field.
+func (l FieldList) foldedKeys(tag string, keys []string) map[string]*Field {
+	type match struct {
+		key     string
+		closest int
+	}
+
+	r := make(map[string]*Field)
+	best := make(map[string]match)
+
+	for _, k := range keys {
+		f, closest := l.getByFoldedKey(tag, k)
+		if f == nil {
+			continue
+		}
+
+		if m, ok := best[f.name]; ok {
+			if m.closest <= closest {
+				continue
+			}
+			delete(r, m.key)
+		}
+
+		best[f.name] = match{key: k, closest: closest}
+		r[k] = f
+	}
+
+	return r
+}
@@ -0,0 +1,79 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type keyMatchTestAccount struct {
+	UserID    int    `json:"user_id"`
+	FullName  string `json:"full_name,alias:display-name"`
+	CreatedAt string
+	Address   struct {
+		PostCode string `json:"post_code"`
+	} `json:"address"`
+}
+
+func TestFoldKey(t *testing.T) {
+	a := assert.New(t)
+
+	for _, s := range []string{"user_id", "userId", "User-ID", "USERID", "userid"} {
+		a.Equal("userid", foldKey(s), s)
+	}
+}
+
+func TestSetFieldsFoldedKeys(t *testing.T) {
+	a := assert.New(t)
+
+	for _, key := range []string{"user_id", "userId", "User-ID", "USERID", "USER_ID"} {
+		var v keyMatchTestAccount
+		a.NoError(SetFields(&v, map[string]interface{}{key: 7}, "json", WithFoldedKeys()), key)
+		a.Equal(7, v.UserID, key)
+	}
+
+	var v keyMatchTestAccount
+	a.NoError(SetFields(&v, map[string]interface{}{
+		"fullName":   "a",
+		"created-at": "b",
+		"ADDRESS":    map[string]interface{}{"postCode": "c"},
+	}, "json", WithFoldedKeys()))
+	a.Equal("a", v.FullName)
+	a.Equal("b", v.CreatedAt)
+	a.Equal("c", v.Address.PostCode)
+
+	v = keyMatchTestAccount{}
+	a.NoError(SetFields(&v, map[string]interface{}{"DisplayName": "d"}, "json", WithFoldedKeys()))
+	a.Equal("d", v.FullName)
+
+	var keys []string
+	v = keyMatchTestAccount{}
+	a.NoError(SetFields(&v, map[string]interface{}{
+		"USERID":   1,
+		"userId":   2,
+		"user_id":  3,
+		"fullname": "x",
+		"FullName": "y",
+	}, "json", WithFoldedKeys(), WithUnknownKeyCollector(&keys)))
+	a.Equal(3, v.UserID)
+	a.Equal("y", v.FullName)
+	a.Equal([]string{"USERID", "fullname", "userId"}, keys)
+
+	v = keyMatchTestAccount{}
+	a.NoError(SetFields(&v, map[string]interface{}{"userId": 1}, "json"))
+	a.Equal(0, v.UserID)
+}
+
+func TestSetFieldsFoldedKeysEmptyTagValue(t *testing.T) {
+	a := assert.New(t)
+
+	type T struct {
+		DisplayName string `json:",omitempty"`
+	}
+
+	for _, key := range []string{"displayname", "display_name"} {
+		var v T
+		a.NoError(SetFields(&v, map[string]interface{}{key: "x"}, "json", WithFoldedKeys(), WithUnknownKeyPolicy(UnknownKeysError)), key)
+		a.Equal("x", v.DisplayName, key)
+	}
+}
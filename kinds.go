@@ -0,0 +1,34 @@
+package reflectutil
+
+import (
+	"reflect"
+)
+
+// BaseType returns the field's type with any pointer layers removed.
+func (f *Field) BaseType() reflect.Type { return derefType(f.typ) }
+
+// The following predicates look at the field's own type; use BaseType to see
+// through pointers.
+
+func (f *Field) IsPointer() bool   { return f.typ.Kind() == reflect.Ptr }
+func (f *Field) IsStruct() bool    { return f.typ.Kind() == reflect.Struct }
+func (f *Field) IsSlice() bool     { return f.typ.Kind() == reflect.Slice }
+func (f *Field) IsArray() bool     { return f.typ.Kind() == reflect.Array }
+func (f *Field) IsMap() bool       { return f.typ.Kind() == reflect.Map }
+func (f *Field) IsChan() bool      { return f.typ.Kind() == reflect.Chan }
+func (f *Field) IsFunc() bool      { return f.typ.Kind() == reflect.Func }
+func (f *Field) IsInterface() bool { return f.typ.Kind() == reflect.Interface }
+func (f *Field) IsString() bool    { return f.typ.Kind() == reflect.String }
+func (f *Field) IsBool() bool      { return f.typ.Kind() == reflect.Bool }
+func (f *Field) IsNumeric() bool   { return isNumberKind(f.typ.Kind()) }
+func (f *Field) IsTime() bool      { return f.typ == timeType }
+func (f *Field) IsDuration() bool  { return f.typ == durationType }
+
+// IsCollection reports whether the field is a slice, array or map.
+func (f *Field) IsCollection() bool { return f.IsSlice() || f.IsArray() || f.IsMap() }
+
+// IsBytes reports whether the field is a []byte (or another slice of a byte
+// kind).
+func (f *Field) IsBytes() bool {
+	return f.typ.Kind() == reflect.Slice && f.typ.Elem().Kind() == reflect.Uint8
+}
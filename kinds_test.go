@@ -0,0 +1,64 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindPredicates(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(struct {
+		Ptr      **int
+		Struct   struct{ A int }
+		Slice    []string
+		Bytes    []byte
+		Array    [2]int
+		Map      map[string]int
+		Chan     chan int
+		Func     func()
+		Iface    interface{}
+		String   string
+		Bool     bool
+		Float    float32
+		Time     time.Time
+		TimePtr  *time.Time
+		Duration time.Duration
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	check := func(name string, fn func(f *Field) bool, expected ...string) {
+		var r []string
+		for i := range d.fields {
+			if fn(&d.fields[i]) {
+				r = append(r, d.fields[i].name)
+			}
+		}
+		a.Equal(expected, r, name)
+	}
+
+	check("IsPointer", (*Field).IsPointer, "Ptr", "TimePtr")
+	check("IsStruct", (*Field).IsStruct, "Struct", "Time")
+	check("IsSlice", (*Field).IsSlice, "Slice", "Bytes")
+	check("IsArray", (*Field).IsArray, "Array")
+	check("IsMap", (*Field).IsMap, "Map")
+	check("IsChan", (*Field).IsChan, "Chan")
+	check("IsFunc", (*Field).IsFunc, "Func")
+	check("IsInterface", (*Field).IsInterface, "Iface")
+	check("IsString", (*Field).IsString, "String")
+	check("IsBool", (*Field).IsBool, "Bool")
+	check("IsNumeric", (*Field).IsNumeric, "Float", "Duration")
+	check("IsTime", (*Field).IsTime, "Time")
+	check("IsDuration", (*Field).IsDuration, "Duration")
+	check("IsCollection", (*Field).IsCollection, "Slice", "Bytes", "Array", "Map")
+	check("IsBytes", (*Field).IsBytes, "Bytes")
+
+	a.Equal(reflect.TypeOf(0), d.Field("Ptr").BaseType())
+	a.Equal(reflect.TypeOf(time.Time{}), d.Field("TimePtr").BaseType())
+	a.Equal(reflect.TypeOf(""), d.Field("String").BaseType())
+}
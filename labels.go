@@ -0,0 +1,119 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnboundedLabel is reported by Labels for a field that could take any
+// number of distinct values.
+var ErrUnboundedLabel = errors.New("unbounded label value")
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// LabelBounded reports whether the field can only take a small, fixed set of
+// values, and so is safe to use as a metric label: bools, fields that declare
+// an enum (see Field.Enum), and named integer types with a String method,
+// which are almost always enums themselves.
+func (f *Field) LabelBounded() bool {
+	if f.Enum() != nil {
+		return true
+	}
+
+	typ := derefType(f.typ)
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ.PkgPath() != "" && (typ.Implements(stringerType) || reflect.PtrTo(typ).Implements(stringerType))
+	}
+
+	return false
+}
+
+// Labels returns the fields of v that have a tag called tag as metric labels,
+// named by the tag's value (or the field name). Fields that aren't
+// LabelBounded are rejected with ErrUnboundedLabel unless their tag has an
+// unbounded parameter (`metric:"tenant,unbounded"`), and values outside a
+// declared enum are rejected too. Values are formatted with their String
+// method if they have one; nil pointers give empty labels, so every label is
+// always present.
+func Labels(v interface{}, tag string) (map[string]string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Labels: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.Labels: %w", err)
+	}
+
+	r := make(map[string]string)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		t := f.tags.Get(tag)
+		if t == nil || t.value == "-" || !f.Exported() {
+			continue
+		}
+
+		name := t.value
+		if name == "" {
+			name = f.name
+		}
+
+		s, err := labelValue(f, rv, t.parameters.Has("unbounded"))
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		r[name] = s
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.Labels: %w", err)
+	}
+
+	return r, nil
+}
+
+func labelValue(f *Field, rv reflect.Value, unbounded bool) (string, error) {
+	if !unbounded && !f.LabelBounded() {
+		return "", fmt.Errorf("%w: %s", ErrUnboundedLabel, f.typ)
+	}
+
+	fv, ok := fieldValue(rv, f.index)
+	if !ok {
+		return "", nil
+	}
+
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return "", nil
+		}
+		fv = fv.Elem()
+	}
+
+	var s string
+	if e, ok := fv.Interface().(fmt.Stringer); ok {
+		s = e.String()
+	} else {
+		var err error
+		if s, err = formatString(f, fv); err != nil {
+			return "", err
+		}
+	}
+
+	if allowed := f.Enum(); allowed != nil && !unbounded && !fv.IsZero() && !containsString(allowed, s) {
+		return "", fmt.Errorf("%w: %q is not one of the declared values", ErrUnboundedLabel, s)
+	}
+
+	return s, nil
+}
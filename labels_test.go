@@ -0,0 +1,82 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type labelsTestStatus int
+
+func (s labelsTestStatus) String() string {
+	switch s {
+	case 1:
+		return "active"
+	case 2:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+type labelsTestAccount struct {
+	ID      string            `metric:"-"`
+	Tenant  string            `metric:"tenant,unbounded"`
+	Plan    string            `metric:"plan" enum:"free|pro"`
+	Status  labelsTestStatus  `metric:"status"`
+	Trial   bool              `metric:""`
+	Region  *string           `metric:"region,unbounded"`
+	Balance int               `json:"balance"`
+	Parent  *labelsTestStatus `metric:"parent"`
+}
+
+func TestFieldLabelBounded(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(labelsTestAccount{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for field, expected := range map[string]bool{
+		"ID":      false,
+		"Tenant":  false,
+		"Plan":    true,
+		"Status":  true,
+		"Trial":   true,
+		"Balance": false,
+		"Parent":  true,
+	} {
+		a.Equal(expected, d.Field(field).LabelBounded(), field)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	a := assert.New(t)
+
+	l, err := Labels(&labelsTestAccount{ID: "a1", Tenant: "acme", Plan: "pro", Status: 1, Balance: 100}, "metric")
+	a.NoError(err)
+	a.Equal(map[string]string{
+		"tenant": "acme",
+		"plan":   "pro",
+		"status": "active",
+		"Trial":  "false",
+		"region": "",
+		"parent": "",
+	}, l)
+
+	_, err = Labels(labelsTestAccount{Plan: "enterprise"}, "metric")
+	a.True(errors.Is(err, ErrUnboundedLabel))
+	a.ErrorContains(err, `Plan: unbounded label value: "enterprise" is not one of the declared values`)
+
+	_, err = Labels(struct {
+		User  string `metric:"user"`
+		Count int    `metric:"count"`
+	}{}, "metric")
+	a.ErrorContains(err, "User: unbounded label value: string")
+	a.ErrorContains(err, "Count: unbounded label value: int")
+
+	_, err = Labels(1, "metric")
+	a.Error(err)
+}
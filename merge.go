@@ -0,0 +1,185 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type MergeStrategy int
+
+const (
+	// MergeOverwrite copies every non-zero source field over the destination.
+	MergeOverwrite MergeStrategy = iota
+	// MergeKeep only fills in destination fields that are still zero.
+	MergeKeep
+	// MergeAppend appends source slices to destination slices, and otherwise
+	// behaves like MergeOverwrite.
+	MergeAppend
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeOverwrite:
+		return "overwrite"
+	case MergeKeep:
+		return "keep"
+	case MergeAppend:
+		return "append"
+	default:
+		return fmt.Sprintf("[UNKNOWN STRATEGY %d]", int(s))
+	}
+}
+
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch s {
+	case "overwrite":
+		return MergeOverwrite, nil
+	case "keep":
+		return MergeKeep, nil
+	case "append":
+		return MergeAppend, nil
+	default:
+		return 0, fmt.Errorf("reflectutil.ParseMergeStrategy: unknown strategy %q", s)
+	}
+}
+
+// MergeStrategy returns the strategy named by the field's merge tag, or def if
+// it has none.
+func (f *Field) MergeStrategy(def MergeStrategy) (MergeStrategy, error) {
+	t := f.tags.Get("merge")
+	if t == nil || t.value == "" {
+		return def, nil
+	}
+
+	return ParseMergeStrategy(t.value)
+}
+
+// MergeValues copies non-zero fields from src into dst according to strategy,
+// which individual fields can override with a merge tag (`merge:"keep"`).
+// dst must be a pointer to a struct and src a struct (or pointer to one) of
+// the same type. Nested structs that have exported fields are merged field by
+// field; other values, like time.Time, are treated as a whole. Slices and
+// maps are copied into dst, so later changes to src don't show through, but
+// the copies are shallow: pointers, and references held by their elements,
+// still point at the same values as src.
+func MergeValues(dst, src interface{}, strategy MergeStrategy) error {
+	dv, err := settableStructValue(dst)
+	if err != nil {
+		return fmt.Errorf("reflectutil.MergeValues: destination: %w", err)
+	}
+
+	sv, err := structValue(src)
+	if err != nil {
+		return fmt.Errorf("reflectutil.MergeValues: source: %w", err)
+	}
+
+	if dv.Type() != sv.Type() {
+		return fmt.Errorf("reflectutil.MergeValues: destination type %s does not match source type %s", dv.Type(), sv.Type())
+	}
+
+	if err := mergeStruct(dv, sv, strategy, ""); err != nil {
+		return fmt.Errorf("reflectutil.MergeValues: %w", err)
+	}
+
+	return nil
+}
+
+func mergeStruct(dst, src reflect.Value, strategy MergeStrategy, prefix string) error {
+	d, err := GetDescription(dst.Type())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, f := range d.fields {
+		if len(f.index) != 1 {
+			continue
+		}
+
+		path := prefix + f.name
+
+		dv, sv := dst.Field(f.index[0]), src.Field(f.index[0])
+
+		// the exported fields of an unexported embedded struct can still be
+		// set, so embedded structs are merged even if they aren't settable.
+		if f.embedded && dv.Kind() == reflect.Struct {
+			if err := mergeStruct(dv, sv, strategy, prefix); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if !dv.CanSet() {
+			continue
+		}
+
+		fieldStrategy, err := f.MergeStrategy(strategy)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: path, Err: err})
+			continue
+		}
+
+		if err := mergeValue(dv, sv, fieldStrategy, path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func mergeValue(dst, src reflect.Value, strategy MergeStrategy, path string) error {
+	if src.IsZero() {
+		return nil
+	}
+
+	switch {
+	case dst.Kind() == reflect.Struct && hasExportedFields(dst.Type()):
+		return mergeStruct(dst, src, strategy, path+".")
+	case dst.Kind() == reflect.Ptr && dst.Type().Elem().Kind() == reflect.Struct && hasExportedFields(dst.Type().Elem()) && !dst.IsNil():
+		return mergeStruct(dst.Elem(), src.Elem(), strategy, path+".")
+	case dst.Kind() == reflect.Slice && strategy == MergeAppend:
+		r := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+		dst.Set(reflect.AppendSlice(reflect.AppendSlice(r, dst), src))
+		return nil
+	}
+
+	if strategy == MergeKeep && !dst.IsZero() {
+		return nil
+	}
+
+	dst.Set(shallowCopy(src))
+
+	return nil
+}
+
+// shallowCopy returns a copy of v if it's a slice or map, so that the copy
+// doesn't share storage with v, and v itself otherwise.
+func shallowCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		r := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(r, v)
+		return r
+	case reflect.Map:
+		r := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			r.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return r
+	}
+
+	return v
+}
+
+func hasExportedFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
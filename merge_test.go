@@ -0,0 +1,153 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeTestDatabase struct {
+	Host string
+	Port int
+}
+
+type mergeTestBase struct {
+	Region string
+}
+
+type mergeTestConfig struct {
+	mergeTestBase
+	Name     string
+	Debug    bool
+	Pinned   string `merge:"keep"`
+	Plugins  []string
+	Hosts    []string `merge:"append"`
+	Created  time.Time
+	Database mergeTestDatabase
+	Cache    *mergeTestDatabase
+	secret   string
+}
+
+func TestMergeValues(t *testing.T) {
+	when := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	base := func() mergeTestConfig {
+		return mergeTestConfig{
+			mergeTestBase: mergeTestBase{Region: "au"},
+			Name:          "base",
+			Pinned:        "base",
+			Plugins:       []string{"a"},
+			Hosts:         []string{"h1"},
+			Database:      mergeTestDatabase{Host: "db", Port: 5432},
+			Cache:         &mergeTestDatabase{Host: "cache", Port: 6379},
+		}
+	}
+
+	overlay := mergeTestConfig{
+		mergeTestBase: mergeTestBase{Region: "us"},
+		Name:          "overlay",
+		Debug:         true,
+		Pinned:        "overlay",
+		Plugins:       []string{"b"},
+		Hosts:         []string{"h2"},
+		Created:       when,
+		Database:      mergeTestDatabase{Port: 6543},
+		Cache:         &mergeTestDatabase{Host: "other"},
+		secret:        "x",
+	}
+
+	t.Run("overwrite", func(t *testing.T) {
+		a := assert.New(t)
+
+		dst := base()
+		if a.NoError(MergeValues(&dst, overlay, MergeOverwrite)) {
+			a.Equal("us", dst.Region)
+			a.Equal("overlay", dst.Name)
+			a.True(dst.Debug)
+			a.Equal("base", dst.Pinned)
+			a.Equal([]string{"b"}, dst.Plugins)
+			a.Equal([]string{"h1", "h2"}, dst.Hosts)
+			a.Equal(when, dst.Created)
+			a.Equal(mergeTestDatabase{Host: "db", Port: 6543}, dst.Database)
+			a.Equal(&mergeTestDatabase{Host: "other", Port: 6379}, dst.Cache)
+			a.Equal("", dst.secret)
+		}
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		a := assert.New(t)
+
+		dst := base()
+		if a.NoError(MergeValues(&dst, &overlay, MergeKeep)) {
+			a.Equal("au", dst.Region)
+			a.Equal("base", dst.Name)
+			a.True(dst.Debug)
+			a.Equal([]string{"a"}, dst.Plugins)
+			a.Equal([]string{"h1", "h2"}, dst.Hosts)
+			a.Equal(when, dst.Created)
+			a.Equal(mergeTestDatabase{Host: "db", Port: 5432}, dst.Database)
+		}
+	})
+
+	t.Run("append", func(t *testing.T) {
+		a := assert.New(t)
+
+		dst := base()
+		if a.NoError(MergeValues(&dst, overlay, MergeAppend)) {
+			a.Equal("overlay", dst.Name)
+			a.Equal([]string{"a", "b"}, dst.Plugins)
+			a.Equal([]string{"h1", "h2"}, dst.Hosts)
+		}
+	})
+
+	t.Run("nil destination pointer", func(t *testing.T) {
+		a := assert.New(t)
+
+		dst := mergeTestConfig{}
+		if a.NoError(MergeValues(&dst, overlay, MergeOverwrite)) {
+			a.Same(overlay.Cache, dst.Cache)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		a := assert.New(t)
+
+		dst := base()
+		a.ErrorContains(MergeValues(dst, overlay, MergeOverwrite), "destination")
+		a.ErrorContains(MergeValues(&dst, "x", MergeOverwrite), "source")
+		a.ErrorContains(MergeValues(&dst, mergeTestDatabase{}, MergeOverwrite), "does not match")
+
+		type Bad struct {
+			A string `merge:"sideways"`
+		}
+		a.ErrorContains(MergeValues(&Bad{}, Bad{A: "x"}, MergeOverwrite), `A: reflectutil.ParseMergeStrategy: unknown strategy "sideways"`)
+	})
+}
+
+func TestMergeValuesCopiesSlicesAndMaps(t *testing.T) {
+	a := assert.New(t)
+
+	type T struct {
+		Tags []string
+		More []string `merge:"append"`
+		Meta map[string]string
+	}
+
+	for _, strategy := range []MergeStrategy{MergeOverwrite, MergeAppend} {
+		src := T{Tags: []string{"a"}, More: []string{"b"}, Meta: map[string]string{"x": "1"}}
+
+		dst := T{More: make([]string, 1, 10)}
+		if !a.NoError(MergeValues(&dst, src, strategy)) {
+			return
+		}
+
+		src.Tags[0] = "changed"
+		src.More[0] = "changed"
+		src.Meta["x"] = "changed"
+
+		a.Equal("a", dst.Tags[0], strategy.String())
+		a.Equal([]string{"", "b"}, dst.More, strategy.String())
+		a.Equal("1", dst.Meta["x"], strategy.String())
+	}
+}
@@ -0,0 +1,199 @@
+package reflectutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// RenamedFrom returns the names the field was previously known by in the
+// given tag, from its renamed_from parameter
+// (`json:"full_name,renamed_from:name|fullname"`).
+func (f *Field) RenamedFrom(tag string) []string {
+	if t := f.tags.Get(tag); t != nil {
+		if p := t.parameters.Get("renamed_from"); p != nil {
+			return p.ValueList("|")
+		}
+	}
+
+	return nil
+}
+
+// Since returns the schema version that introduced the field, from the since
+// parameter on the given tag (`json:"email,since:2"`).
+func (f *Field) Since(tag string) (int, bool, error) {
+	return f.versionParameter(tag, "since")
+}
+
+// Removed returns the schema version that removed the field, from the removed
+// parameter on the given tag or, failing that, on a migrate tag. Removed
+// fields are usually blank placeholders kept to document the old key, which
+// need the migrate tag since go vet rejects json tags on unexported fields:
+//
+//	_ struct{} `migrate:"nickname,removed:3"`
+func (f *Field) Removed(tag string) (int, bool, error) {
+	if n, ok, err := f.versionParameter(tag, "removed"); ok || err != nil {
+		return n, ok, err
+	}
+
+	return f.versionParameter("migrate", "removed")
+}
+
+// removedName returns the key a removed field was known by.
+func (f *Field) removedName(tag string) (string, bool) {
+	if t := f.tags.Get(tag); t != nil && t.parameters.Has("removed") {
+		return f.EffectiveName(tag)
+	}
+
+	return f.EffectiveName("migrate")
+}
+
+func (f *Field) versionParameter(tag, name string) (int, bool, error) {
+	t := f.tags.Get(tag)
+	if t == nil {
+		return 0, false, nil
+	}
+
+	p := t.parameters.Get(name)
+	if p == nil {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(p.Value())
+	if err != nil {
+		return 0, false, fmt.Errorf("reflectutil.Field.%s: invalid version %q: %w", name, p.Value(), err)
+	}
+
+	return n, true, nil
+}
+
+// DroppedKey is a key from migrated data that didn't map to any current field.
+type DroppedKey struct {
+	Key    string
+	Value  interface{}
+	Reason string
+}
+
+// Migration is the result of Migrator.Migrate.
+type Migration struct {
+	// Data holds the migrated values, keyed by current names.
+	Data map[string]interface{}
+	// Renamed maps each old key that was found to its current name.
+	Renamed map[string]string
+	// Dropped lists the keys that were left out of Data, sorted by key.
+	Dropped []DroppedKey
+}
+
+// Migrator maps serialized data written by older versions of a struct onto
+// its current fields, using the renamed_from, since and removed parameters of
+// one tag (see Field.Removed for removed placeholders). Only top level keys
+// are migrated.
+type Migrator struct {
+	d   *StructDescription
+	tag string
+}
+
+// NewMigrator returns a Migrator for the struct described by d, using the
+// parameters of the given tag.
+func NewMigrator(d *StructDescription, tag string) *Migrator {
+	return &Migrator{d: d, tag: tag}
+}
+
+// Migrate maps data, written by the given schema version, onto the current
+// names. Old names are used only when the current name isn't present. Keys of
+// removed fields, values for fields introduced after version, and unknown keys
+// are dropped. A version of 0 means the data's version is unknown, so since
+// parameters are not checked.
+func (m *Migrator) Migrate(data map[string]interface{}, version int) (*Migration, error) {
+	r := &Migration{Data: make(map[string]interface{}), Renamed: make(map[string]string)}
+
+	used := make(map[string]bool)
+	reasons := make(map[string]string)
+
+	for i := range m.d.fields {
+		f := &m.d.fields[i]
+
+		removed, isRemoved, err := f.Removed(m.tag)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.Migrator.Migrate: %w", &FieldError{Field: f.name, Err: err})
+		}
+		if isRemoved {
+			if name, ok := f.removedName(m.tag); ok {
+				reasons[name] = fmt.Sprintf("removed in version %d", removed)
+			}
+			continue
+		}
+
+		name, ok := f.EffectiveName(m.tag)
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		since, hasSince, err := f.Since(m.tag)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.Migrator.Migrate: %w", &FieldError{Field: f.name, Err: err})
+		}
+		if hasSince && version != 0 && version < since {
+			reason := fmt.Sprintf("field %s was added in version %d", name, since)
+			reasons[name] = reason
+			for _, old := range f.RenamedFrom(m.tag) {
+				reasons[old] = reason
+			}
+			continue
+		}
+
+		if v, ok := data[name]; ok {
+			r.Data[name] = v
+			used[name] = true
+		}
+
+		for _, old := range f.RenamedFrom(m.tag) {
+			v, ok := data[old]
+			if !ok {
+				continue
+			}
+
+			if used[name] {
+				reasons[old] = fmt.Sprintf("renamed to %s, which is also present", name)
+				continue
+			}
+
+			r.Data[name] = v
+			r.Renamed[old] = name
+			used[name] = true
+			used[old] = true
+		}
+	}
+
+	for k, v := range data {
+		if used[k] {
+			continue
+		}
+
+		reason, ok := reasons[k]
+		if !ok {
+			reason = "no such field"
+		}
+
+		r.Dropped = append(r.Dropped, DroppedKey{Key: k, Value: v, Reason: reason})
+	}
+
+	sort.Slice(r.Dropped, func(i, j int) bool { return r.Dropped[i].Key < r.Dropped[j].Key })
+
+	return r, nil
+}
+
+// Decode migrates data and stores the result in v, which must be a pointer to
+// the described struct, with SetFields.
+func (m *Migrator) Decode(data map[string]interface{}, version int, v interface{}, opts ...Option) (*Migration, error) {
+	r, err := m.Migrate(data, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SetFields(v, r.Data, m.tag, opts...); err != nil {
+		return r, fmt.Errorf("reflectutil.Migrator.Decode: %w", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,89 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type migrateTestUser struct {
+	FullName string   `json:"full_name,renamed_from:name|fullname"`
+	Email    string   `json:"email,since:2"`
+	_        struct{} `migrate:"nickname,removed:3"`
+	Age      int      `json:"age"`
+	Legacy   string   `json:"legacy,removed:4"`
+}
+
+func TestMigrateParameters(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(migrateTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"name", "fullname"}, d.Field("FullName").RenamedFrom("json"))
+	a.Nil(d.Field("Age").RenamedFrom("json"))
+
+	n, ok, err := d.Field("Email").Since("json")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal(2, n)
+
+	_, ok, err = d.Field("Age").Removed("json")
+	a.NoError(err)
+	a.False(ok)
+
+	d, err = GetDescription(struct {
+		A string `json:"a,since:two"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, _, err = d.Field("A").Since("json")
+	a.ErrorContains(err, `invalid version "two"`)
+}
+
+func TestMigrator(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(migrateTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	m := NewMigrator(d, "json")
+
+	r, err := m.Migrate(map[string]interface{}{
+		"name":     "Jo",
+		"fullname": "Jo Bloggs",
+		"email":    "jo@example.com",
+		"nickname": "jojo",
+		"age":      30,
+		"extra":    true,
+		"legacy":   "x",
+	}, 1)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{"full_name": "Jo", "age": 30}, r.Data)
+	a.Equal(map[string]string{"name": "full_name"}, r.Renamed)
+	a.Equal([]DroppedKey{
+		{Key: "email", Value: "jo@example.com", Reason: "field email was added in version 2"},
+		{Key: "extra", Value: true, Reason: "no such field"},
+		{Key: "fullname", Value: "Jo Bloggs", Reason: "renamed to full_name, which is also present"},
+		{Key: "legacy", Value: "x", Reason: "removed in version 4"},
+		{Key: "nickname", Value: "jojo", Reason: "removed in version 3"},
+	}, r.Dropped)
+
+	var u migrateTestUser
+	r, err = m.Decode(map[string]interface{}{"fullname": "Jo", "email": "jo@example.com"}, 0, &u)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Empty(r.Dropped)
+	a.Equal(migrateTestUser{FullName: "Jo", Email: "jo@example.com"}, u)
+}
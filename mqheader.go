@@ -0,0 +1,224 @@
+package reflectutil
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// mqHeaderTypes are the binary encodings that can be chosen with an
+// mqheader tag's type parameter, with the Go types they're encoded as.
+var mqHeaderTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// MQHeaderTag is the parsed form of an mqheader tag, e.g.
+// `mqheader:"retries,type:int32"`.
+type MQHeaderTag struct {
+	Name      string
+	Type      string
+	OmitEmpty bool
+}
+
+// MQHeaderTag parses the field's mqheader tag. The name defaults to the field
+// name, and a tag value of "-" excludes the field. Type is "string" unless a
+// type parameter gives one of "json", "bool", or a sized number type like
+// "int32" or "float64", all encoded big endian.
+func (f *Field) MQHeaderTag() (MQHeaderTag, bool) {
+	t := f.tags.Get("mqheader")
+	if t == nil || t.value == "-" {
+		return MQHeaderTag{}, false
+	}
+
+	h := MQHeaderTag{Name: t.value, Type: "string", OmitEmpty: t.parameters.Has("omitempty")}
+	if h.Name == "" {
+		h.Name = f.name
+	}
+
+	if p := t.parameters.Get("type"); p != nil && p.Value() != "" {
+		h.Type = p.Value()
+	}
+
+	return h, true
+}
+
+// ToMQHeaders returns the fields of v that have mqheader tags as a map of
+// header values, as used for Kafka record headers or AMQP properties. Byte
+// slices are used as they are; other values are encoded according to their
+// tag's type. Nil pointers are left out, as are zero values of fields with an
+// omitempty parameter.
+func ToMQHeaders(v interface{}) (map[string][]byte, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToMQHeaders: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToMQHeaders: %w", err)
+	}
+
+	r := make(map[string][]byte)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		h, ok := f.MQHeaderTag()
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (fv.Kind() == reflect.Ptr && fv.IsNil()) || (h.OmitEmpty && fv.IsZero()) {
+			continue
+		}
+
+		b, err := encodeMQHeader(f, reflect.Indirect(fv), h.Type)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		r[h.Name] = b
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.ToMQHeaders: %w", err)
+	}
+
+	return r, nil
+}
+
+func encodeMQHeader(f *Field, v reflect.Value, typ string) ([]byte, error) {
+	switch typ {
+	case "string":
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), v.Bytes()...), nil
+		}
+
+		s, err := formatString(f, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(s), nil
+	case "json":
+		return json.Marshal(v.Interface())
+	}
+
+	t, ok := mqHeaderTypes[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown header type %q", typ)
+	}
+
+	n := reflect.New(t).Elem()
+	if err := assignValue(f, n, v.Interface(), nil, nil); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, t.Size())
+	if err := encodeBinaryField(b, n, binary.BigEndian); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// FromMQHeaders fills v, which must be a pointer to a struct, from headers,
+// decoding each value according to its field's tag. Headers that no field
+// asked for are subject to WithUnknownKeyPolicy.
+func FromMQHeaders(headers map[string][]byte, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromMQHeaders: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromMQHeaders: %w", err)
+	}
+
+	o := getOptions(opts)
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		h, ok := f.MQHeaderTag()
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		b, ok := headers[h.Name]
+		if !ok {
+			continue
+		}
+
+		used[h.Name] = true
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = decodeMQHeader(f, fv, b, h.Type)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		o.record(f.name, h.Name, "mqheader")
+	}
+
+	if err := unknownKeys(o, headers, used); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.FromMQHeaders: %w", err)
+	}
+
+	return nil
+}
+
+func decodeMQHeader(f *Field, v reflect.Value, b []byte, typ string) error {
+	switch typ {
+	case "string":
+		if derefType(v.Type()).Kind() == reflect.Slice && derefType(v.Type()).Elem().Kind() == reflect.Uint8 {
+			return assignValue(f, v, append([]byte(nil), b...), nil, nil)
+		}
+
+		return setFromString(f, v, string(b))
+	case "json":
+		return json.Unmarshal(b, v.Addr().Interface())
+	}
+
+	t, ok := mqHeaderTypes[typ]
+	if !ok {
+		return fmt.Errorf("unknown header type %q", typ)
+	}
+
+	if len(b) != int(t.Size()) {
+		return fmt.Errorf("got %d bytes for a %s header", len(b), typ)
+	}
+
+	n := reflect.New(t).Elem()
+	decodeBinaryField(n, b, binary.BigEndian)
+
+	return assignValue(f, v, n.Interface(), nil, nil)
+}
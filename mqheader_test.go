@@ -0,0 +1,96 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mqHeaderTestMessage struct {
+	ID        string            `mqheader:"message-id"`
+	Retries   int               `mqheader:"retries,type:int32"`
+	Priority  *uint8            `mqheader:"priority,type:uint8"`
+	Score     float64           `mqheader:"score,type:float64,omitempty"`
+	Urgent    bool              `mqheader:"urgent,type:bool"`
+	TTL       time.Duration     `mqheader:"ttl"`
+	Trace     []byte            `mqheader:"trace"`
+	Meta      map[string]string `mqheader:"meta,type:json"`
+	Ignored   string            `mqheader:"-"`
+	Untouched string
+}
+
+func TestFieldMQHeaderTag(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(mqHeaderTestMessage{})
+	if !a.NoError(err) {
+		return
+	}
+
+	h, ok := d.Field("Retries").MQHeaderTag()
+	a.True(ok)
+	a.Equal(MQHeaderTag{Name: "retries", Type: "int32"}, h)
+
+	h, ok = d.Field("Score").MQHeaderTag()
+	a.True(ok)
+	a.Equal(MQHeaderTag{Name: "score", Type: "float64", OmitEmpty: true}, h)
+
+	_, ok = d.Field("Ignored").MQHeaderTag()
+	a.False(ok)
+	_, ok = d.Field("Untouched").MQHeaderTag()
+	a.False(ok)
+}
+
+func TestToFromMQHeaders(t *testing.T) {
+	a := assert.New(t)
+
+	priority := uint8(9)
+
+	v := mqHeaderTestMessage{
+		ID:       "m1",
+		Retries:  258,
+		Priority: &priority,
+		Urgent:   true,
+		TTL:      time.Minute,
+		Trace:    []byte{0xde, 0xad},
+		Meta:     map[string]string{"a": "b"},
+		Ignored:  "x",
+	}
+
+	h, err := ToMQHeaders(&v)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string][]byte{
+		"message-id": []byte("m1"),
+		"retries":    {0, 0, 1, 2},
+		"priority":   {9},
+		"urgent":     {1},
+		"ttl":        []byte("1m0s"),
+		"trace":      {0xde, 0xad},
+		"meta":       []byte(`{"a":"b"}`),
+	}, h)
+
+	var w mqHeaderTestMessage
+	a.NoError(FromMQHeaders(h, &w))
+	v.Ignored = ""
+	a.Equal(v, w)
+
+	_, err = ToMQHeaders(mqHeaderTestMessage{Retries: 1 << 40})
+	a.ErrorContains(err, "Retries: 1099511627776 overflows int32")
+
+	_, err = ToMQHeaders(struct {
+		A int `mqheader:"a,type:varint"`
+	}{})
+	a.ErrorContains(err, `A: unknown header type "varint"`)
+
+	var keys []string
+	a.NoError(FromMQHeaders(map[string][]byte{"other": nil, "message-id": []byte("m2")}, &w, WithUnknownKeyCollector(&keys)))
+	a.Equal("m2", w.ID)
+	a.Equal([]string{"other"}, keys)
+
+	err = FromMQHeaders(map[string][]byte{"retries": {1}}, &w)
+	a.ErrorContains(err, "Retries: got 1 bytes for a int32 header")
+}
@@ -0,0 +1,99 @@
+package reflectutil
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"reflect"
+)
+
+// DefaultMultipartMemory is the maxMemory passed to ParseMultipartForm by
+// BindRequest. Larger uploads are spooled to temporary files.
+var DefaultMultipartMemory int64 = 32 << 20
+
+var (
+	fileHeaderType    = reflect.TypeOf(multipart.FileHeader{})
+	fileHeaderPtrType = reflect.TypeOf(&multipart.FileHeader{})
+)
+
+// MaxFileSize returns the limit given by the max parameter of the field's file
+// tag (e.g. `file:"avatar,max:2MiB"`), or zero if there isn't one. The limit
+// is checked against each upload after the request body has been parsed, so
+// it rejects oversized files but doesn't stop them being read: the body is
+// still buffered (up to DefaultMultipartMemory) and spooled to disk in full.
+// Callers that need to bound what's read should limit r.Body themselves,
+// e.g. with http.MaxBytesReader, before calling BindRequest.
+func (f *Field) MaxFileSize() (int64, error) {
+	t := f.tags.Get("file")
+	if t == nil {
+		return 0, nil
+	}
+
+	p := t.parameters.Get("max")
+	if p == nil {
+		return 0, nil
+	}
+
+	n, err := p.Bytes()
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Field.MaxFileSize(%s): %w", f.name, err)
+	}
+
+	return n, nil
+}
+
+// bindFile stores uploaded files in v, which may be a *multipart.FileHeader,
+// a []byte holding the file's contents, or a slice of either to accept more
+// than one file.
+func bindFile(f *Field, v reflect.Value, files []*multipart.FileHeader) error {
+	max, err := f.MaxFileSize()
+	if err != nil {
+		return err
+	}
+
+	for _, fh := range files {
+		if max > 0 && fh.Size > max {
+			return fmt.Errorf("file %q is %d bytes, over the limit of %d", fh.Filename, fh.Size, max)
+		}
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		r := reflect.MakeSlice(v.Type(), len(files), len(files))
+		for i, fh := range files {
+			if err := bindFileHeader(r.Index(i), fh); err != nil {
+				return fmt.Errorf("file %d: %w", i, err)
+			}
+		}
+		v.Set(r)
+
+		return nil
+	}
+
+	return bindFileHeader(v, files[0])
+}
+
+func bindFileHeader(v reflect.Value, fh *multipart.FileHeader) error {
+	switch {
+	case v.Type() == fileHeaderPtrType:
+		v.Set(reflect.ValueOf(fh))
+	case v.Type() == fileHeaderType:
+		v.Set(reflect.ValueOf(*fh))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		rd, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		defer rd.Close()
+
+		b, err := io.ReadAll(rd)
+		if err != nil {
+			return err
+		}
+
+		v.SetBytes(b)
+	default:
+		return fmt.Errorf("can't bind a file to a %s", v.Type())
+	}
+
+	return nil
+}
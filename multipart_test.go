@@ -0,0 +1,91 @@
+package reflectutil
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type multipartTestUpload struct {
+	Title       string                  `form:"title"`
+	Avatar      *multipart.FileHeader   `file:"avatar"`
+	Attachments []*multipart.FileHeader `file:"attachment"`
+	Small       []byte                  `file:"small,max:4"`
+	Missing     []byte                  `file:"missing"`
+}
+
+func TestBindRequestMultipart(t *testing.T) {
+	a := assert.New(t)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	a.NoError(w.WriteField("title", "Hello"))
+	for _, f := range [][2]string{
+		{"avatar", "me.png"},
+		{"attachment", "a.txt"},
+		{"attachment", "b.txt"},
+		{"small", "s.txt"},
+	} {
+		fw, err := w.CreateFormFile(f[0], f[1])
+		a.NoError(err)
+		_, err = fw.Write([]byte(f[1][:1] + "bc"))
+		a.NoError(err)
+	}
+	a.NoError(w.Close())
+
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	var v multipartTestUpload
+	if !a.NoError(BindRequest(r, nil, &v)) {
+		return
+	}
+
+	a.Equal("Hello", v.Title)
+	if a.NotNil(v.Avatar) {
+		a.Equal("me.png", v.Avatar.Filename)
+	}
+	if a.Len(v.Attachments, 2) {
+		a.Equal("b.txt", v.Attachments[1].Filename)
+	}
+	a.Equal([]byte("sbc"), v.Small)
+	a.Nil(v.Missing)
+}
+
+func TestBindRequestMultipartLimit(t *testing.T) {
+	a := assert.New(t)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fw, err := w.CreateFormFile("small", "big.txt")
+	a.NoError(err)
+	_, err = fw.Write([]byte("too big"))
+	a.NoError(err)
+	a.NoError(w.Close())
+
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+
+	var v multipartTestUpload
+	a.ErrorContains(BindRequest(r, nil, &v), `Small (file "small"): file "big.txt" is 7 bytes, over the limit of 4`)
+}
+
+func TestMaxFileSize(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(multipartTestUpload{})
+	if !a.NoError(err) {
+		return
+	}
+
+	n, err := d.Field("Small").MaxFileSize()
+	a.NoError(err)
+	a.Equal(int64(4), n)
+
+	n, err = d.Field("Avatar").MaxFileSize()
+	a.NoError(err)
+	a.Equal(int64(0), n)
+}
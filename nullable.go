@@ -0,0 +1,48 @@
+package reflectutil
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var nullableTypes = struct {
+	sync.RWMutex
+	types map[reflect.Type]bool
+}{types: make(map[reflect.Type]bool)}
+
+// RegisterNullableType marks typ as a nullable wrapper type (e.g.
+// null.String), so that fields of that type report true from Field.Nullable.
+func RegisterNullableType(typ reflect.Type) {
+	nullableTypes.Lock()
+	defer nullableTypes.Unlock()
+
+	nullableTypes.types[typ] = true
+}
+
+// Nullable reports whether the field can represent an absent value: pointers,
+// interfaces, the database/sql Null* types, anything registered with
+// RegisterNullableType, and registered wrappers marked as Nullable. Slices and
+// maps are not considered nullable, since their nil and empty values are
+// usually treated the same way.
+func (f *Field) Nullable() bool { return isNullableType(f.typ) }
+
+func isNullableType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return true
+	}
+
+	if typ.PkgPath() == "database/sql" && strings.HasPrefix(typ.Name(), "Null") {
+		return true
+	}
+
+	if w, ok := LookupWrapper(typ); ok && w.Nullable {
+		return true
+	}
+
+	nullableTypes.RLock()
+	defer nullableTypes.RUnlock()
+
+	return nullableTypes.types[typ]
+}
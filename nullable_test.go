@@ -0,0 +1,52 @@
+package reflectutil
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nullableTestWrapper struct {
+	Value string
+	Valid bool
+}
+
+func TestFieldNullable(t *testing.T) {
+	a := assert.New(t)
+
+	RegisterNullableType(reflect.TypeOf(nullableTestWrapper{}))
+
+	type S struct {
+		String     string
+		Pointer    *string
+		Interface  interface{}
+		Slice      []string
+		Map        map[string]string
+		NullString sql.NullString
+		NullTime   sql.NullTime
+		Wrapper    nullableTestWrapper
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field    string
+		nullable bool
+	}{
+		{"String", false},
+		{"Pointer", true},
+		{"Interface", true},
+		{"Slice", false},
+		{"Map", false},
+		{"NullString", true},
+		{"NullTime", true},
+		{"Wrapper", true},
+	} {
+		a.Equal(tc.nullable, d.Field(tc.field).Nullable(), tc.field)
+	}
+}
@@ -0,0 +1,146 @@
+package reflectutil
+
+import (
+	"reflect"
+)
+
+type Option func(*options)
+
+type options struct {
+	nested       bool
+	maxDepth     int
+	allowedTypes map[reflect.Type]bool
+	deniedTypes  map[reflect.Type]bool
+
+	duplicateParameterPolicy DuplicateParameterPolicy
+	embeddedTagPolicy        EmbeddedTagPolicy
+	spans                    bool
+
+	strict bool
+
+	readOnlyPolicy ReadOnlyPolicy
+
+	foldKeys bool
+
+	unknownKeys         UnknownKeyPolicy
+	unknownKeyCollector *[]string
+	keyPrefix           string
+
+	provenance  Provenance
+	fieldPrefix string
+
+	arena *describeArena
+
+	noCache bool
+}
+
+func getOptions(opts []Option) *options {
+	o := &options{}
+	for _, fn := range opts {
+		fn(o)
+	}
+	return o
+}
+
+// WithNestedDescriptions makes fields whose type is a struct (or a pointer to
+// one) carry a description of that type, available via Field.Description.
+// Recursion stops after maxDepth levels; a negative maxDepth means no limit,
+// in which case recursive types refer back to the description already being
+// built for them.
+func WithNestedDescriptions(maxDepth int) Option {
+	return func(o *options) {
+		o.nested = true
+		o.maxDepth = maxDepth
+	}
+}
+
+// WithAllowedTypes restricts nested descriptions to the given types.
+func WithAllowedTypes(types ...reflect.Type) Option {
+	return func(o *options) {
+		if o.allowedTypes == nil {
+			o.allowedTypes = make(map[reflect.Type]bool)
+		}
+		for _, typ := range types {
+			o.allowedTypes[typ] = true
+		}
+	}
+}
+
+// WithDeniedTypes prevents nested descriptions from being built for the given
+// types, e.g. time.Time or sql.NullString.
+func WithDeniedTypes(types ...reflect.Type) Option {
+	return func(o *options) {
+		if o.deniedTypes == nil {
+			o.deniedTypes = make(map[reflect.Type]bool)
+		}
+		for _, typ := range types {
+			o.deniedTypes[typ] = true
+		}
+	}
+}
+
+// WithStrictTypes makes decoders and setters refuse values that aren't
+// assignable to their fields, rather than converting them. Containers are
+// still filled item by item, and registered converters still apply, but
+// strings aren't parsed and numbers aren't widened. Decoders whose input is
+// only ever strings, like BindRequest, FromRedisHash and FromDynamoDBItem
+// (whose numbers are strings on the wire), parse regardless.
+func WithStrictTypes() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithUnknownKeyPolicy controls what decoders do with input keys that don't
+// match any field. The default is to ignore them.
+func WithUnknownKeyPolicy(policy UnknownKeyPolicy) Option {
+	return func(o *options) {
+		o.unknownKeys = policy
+	}
+}
+
+// WithUnknownKeyCollector selects UnknownKeysCollect, appending each unknown
+// key to dst. Keys inside nested maps are given as dotted paths, e.g.
+// "database.host".
+func WithUnknownKeyCollector(dst *[]string) Option {
+	return func(o *options) {
+		o.unknownKeys = UnknownKeysCollect
+		o.unknownKeyCollector = dst
+	}
+}
+
+func (o *options) shouldDescend(typ reflect.Type, depth int) bool {
+	if !o.nested {
+		return false
+	}
+
+	if o.maxDepth >= 0 && depth > o.maxDepth {
+		return false
+	}
+
+	if o.allowedTypes != nil && !o.allowedTypes[typ] {
+		return false
+	}
+
+	if o.deniedTypes[typ] {
+		return false
+	}
+
+	return true
+}
+
+// WithTagSpans records the position of each tag and parameter in the text
+// it was parsed from, available from Tag.Span and Parameter.Span.
+func WithTagSpans() Option {
+	return func(o *options) {
+		o.spans = true
+	}
+}
+
+// WithDuplicateParameterPolicy controls what happens when a parameter is
+// repeated within a single tag.
+func WithDuplicateParameterPolicy(policy DuplicateParameterPolicy) Option {
+	return func(o *options) {
+		o.duplicateParameterPolicy = policy
+	}
+}
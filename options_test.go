@@ -0,0 +1,104 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestedDescriptions(t *testing.T) {
+	type Address struct {
+		Street string
+		Since  time.Time
+	}
+
+	type Person struct {
+		Name    string
+		Home    Address
+		Work    *Address
+		Created time.Time
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		a := assert.New(t)
+
+		d, err := GetDescription(Person{})
+		if a.NoError(err) {
+			a.Nil(d.Field("Home").Description())
+		}
+	})
+
+	t.Run("unlimited depth", func(t *testing.T) {
+		a := assert.New(t)
+
+		d, err := GetDescription(Person{}, WithNestedDescriptions(-1))
+		if !a.NoError(err) {
+			return
+		}
+
+		a.Nil(d.Field("Name").Description())
+		if a.NotNil(d.Field("Home").Description()) {
+			a.Equal("Address", d.Field("Home").Description().Name())
+			a.NotNil(d.Field("Home").Description().Field("Since").Description())
+		}
+		if a.NotNil(d.Field("Work").Description()) {
+			a.Equal(reflect.TypeOf(Address{}), d.Field("Work").Description().Type())
+		}
+		a.NotNil(d.Field("Created").Description())
+	})
+
+	t.Run("limited depth", func(t *testing.T) {
+		a := assert.New(t)
+
+		d, err := GetDescription(Person{}, WithNestedDescriptions(1))
+		if !a.NoError(err) {
+			return
+		}
+
+		if a.NotNil(d.Field("Home").Description()) {
+			a.Nil(d.Field("Home").Description().Field("Since").Description())
+		}
+	})
+
+	t.Run("denied types", func(t *testing.T) {
+		a := assert.New(t)
+
+		d, err := GetDescription(Person{}, WithNestedDescriptions(-1), WithDeniedTypes(reflect.TypeOf(time.Time{})))
+		if !a.NoError(err) {
+			return
+		}
+
+		a.Nil(d.Field("Created").Description())
+		if a.NotNil(d.Field("Home").Description()) {
+			a.Nil(d.Field("Home").Description().Field("Since").Description())
+		}
+	})
+
+	t.Run("allowed types", func(t *testing.T) {
+		a := assert.New(t)
+
+		d, err := GetDescription(Person{}, WithNestedDescriptions(-1), WithAllowedTypes(reflect.TypeOf(Address{})))
+		if !a.NoError(err) {
+			return
+		}
+
+		a.NotNil(d.Field("Home").Description())
+		a.Nil(d.Field("Created").Description())
+	})
+
+	t.Run("recursive type", func(t *testing.T) {
+		a := assert.New(t)
+
+		type Node struct {
+			Value int
+			Next  *Node
+		}
+
+		d, err := GetDescription(Node{}, WithNestedDescriptions(-1))
+		if a.NoError(err) {
+			a.Same(d, d.Field("Next").Description())
+		}
+	})
+}
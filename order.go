@@ -0,0 +1,65 @@
+package reflectutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// OrderParam returns the order parameter of the field's tag called tag, e.g.
+// 10 for `wire:"id,order:10"`. Orders may be negative.
+func (f *Field) OrderParam(tag string) (int, bool, error) {
+	t := f.tags.Get(tag)
+	if t == nil {
+		return 0, false, nil
+	}
+
+	p := t.parameters.Get("order")
+	if p == nil {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(p.Value())
+	if err != nil {
+		return 0, false, fmt.Errorf("reflectutil.Field.OrderParam: invalid order %q for field %s", p.Value(), f.name)
+	}
+
+	return n, true, nil
+}
+
+// SortByOrderParam returns a copy of the list ordered by the order parameters
+// of each field's tag called tag. Fields with the same order keep their
+// relative positions, and fields without one (or with an invalid one) follow
+// the ordered fields in declaration order.
+func (l FieldList) SortByOrderParam(tag string) FieldList {
+	type entry struct {
+		order int
+		ok    bool
+	}
+
+	entries := make([]entry, len(l))
+	for i := range l {
+		n, ok, _ := l[i].OrderParam(tag)
+		entries[i] = entry{n, ok}
+	}
+
+	indexes := make([]int, len(l))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := entries[indexes[i]], entries[indexes[j]]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		return a.ok && a.order < b.order
+	})
+
+	r := make(FieldList, len(l))
+	for i, j := range indexes {
+		r[i] = l[j]
+	}
+
+	return r
+}
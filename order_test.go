@@ -0,0 +1,68 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderTestRecord struct {
+	Name    string `wire:"name,order:20"`
+	Notes   string
+	ID      int    `wire:"id,order:10"`
+	Kind    string `wire:"kind,order:20"`
+	Flags   int    `wire:"flags"`
+	Version int    `wire:"version,order:-1"`
+}
+
+func TestFieldOrderParam(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(orderTestRecord{})
+	if !a.NoError(err) {
+		return
+	}
+
+	n, ok, err := d.Field("ID").OrderParam("wire")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal(10, n)
+
+	n, ok, err = d.Field("Version").OrderParam("wire")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal(-1, n)
+
+	_, ok, err = d.Field("Flags").OrderParam("wire")
+	a.NoError(err)
+	a.False(ok)
+
+	_, ok, err = d.Field("ID").OrderParam("json")
+	a.NoError(err)
+	a.False(ok)
+
+	d, err = GetDescription(struct {
+		A int `wire:"a,order:first"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, ok, err = d.Field("A").OrderParam("wire")
+	a.False(ok)
+	a.EqualError(err, `reflectutil.Field.OrderParam: invalid order "first" for field A`)
+}
+
+func TestFieldListSortByOrderParam(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(orderTestRecord{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Version", "ID", "Name", "Kind", "Notes", "Flags"}, d.Fields().SortByOrderParam("wire").Names())
+	a.Equal([]string{"Name", "Notes", "ID", "Kind", "Flags", "Version"}, d.Fields().Names())
+	a.Equal(d.Fields().Names(), d.Fields().SortByOrderParam("json").Names())
+	a.Len(FieldList(nil).SortByOrderParam("wire"), 0)
+}
@@ -0,0 +1,131 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GetPartialDescription is like GetDescription, but only describes the named
+// fields, in declaration order, without parsing the tags of any others. It's
+// meant for hot paths that need a few fields of a very large struct. Naming a
+// field that doesn't exist is an error. With a DescriptionProvider installed,
+// the full description is filtered instead.
+func GetPartialDescription(input interface{}, fieldNames ...string) (*StructDescription, error) {
+	d, err := getPartialDescription(input, fieldNames)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.GetPartialDescription(%T): could not get description: %w", input, err)
+	}
+
+	return d, nil
+}
+
+func getPartialDescription(input interface{}, fieldNames []string) (*StructDescription, error) {
+	typ, ok := input.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(input)
+	}
+
+	if getDescriptionProvider() != nil {
+		full, err := getDescription(typ, nil, getOptions(nil))
+		if err != nil {
+			return nil, err
+		}
+
+		return full.partial(fieldNames)
+	}
+
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input should be struct or pointer to struct")
+	}
+
+	wanted := make(map[string]bool, len(fieldNames))
+	for _, name := range fieldNames {
+		wanted[name] = true
+	}
+
+	ctx := &describeContext{
+		options:    getOptions(nil),
+		inProgress: make(map[reflect.Type]*StructDescription),
+	}
+
+	d := &StructDescription{name: typ.Name(), typ: typ, anonymous: typ.Name() == ""}
+
+	var errs []error
+
+	for _, structField := range visibleFields(typ) {
+		if !wanted[structField.Name] {
+			continue
+		}
+
+		delete(wanted, structField.Name)
+
+		f, err := getFieldFromStructField(typ, structField, ctx, 0)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		d.fields = append(d.fields, f)
+	}
+
+	if err := missingFields(fieldNames, wanted); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	resolveEmbeddedTags(d.fields, ctx.options.embeddedTagPolicy)
+
+	stats.descriptionsBuilt.Add(1)
+
+	return d, nil
+}
+
+// partial returns a copy of s with only the named fields.
+func (s *StructDescription) partial(fieldNames []string) (*StructDescription, error) {
+	wanted := make(map[string]bool, len(fieldNames))
+	for _, name := range fieldNames {
+		wanted[name] = true
+	}
+
+	d := &StructDescription{name: s.name, typ: s.typ, anonymous: s.anonymous}
+
+	for _, f := range s.fields {
+		if wanted[f.name] {
+			delete(wanted, f.name)
+			d.fields = append(d.fields, f)
+		}
+	}
+
+	if err := missingFields(fieldNames, wanted); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// missingFields reports the names still in wanted, in the order they were
+// asked for.
+func missingFields(fieldNames []string, wanted map[string]bool) error {
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range fieldNames {
+		if wanted[name] {
+			missing = append(missing, name)
+			delete(wanted, name)
+		}
+	}
+
+	return fmt.Errorf("no such fields: %s", strings.Join(missing, ", "))
+}
@@ -0,0 +1,48 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type partialTestBase struct {
+	ID string `json:"id"`
+}
+
+type partialTestRecord struct {
+	partialTestBase
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Notes string `json:"notes"`
+}
+
+func TestGetPartialDescription(t *testing.T) {
+	a := assert.New(t)
+
+	ResetStats()
+
+	d, err := GetPartialDescription(&partialTestRecord{}, "Email", "ID")
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("partialTestRecord", d.Name())
+	a.Equal([]string{"ID", "Email"}, d.Fields().Names())
+	a.Equal("email", d.Field("Email").Tag("json").Value())
+	a.Equal([]int{0, 0}, d.Field("ID").Index())
+	a.Equal([]string{"partialTestBase"}, d.Field("ID").Path())
+	a.Nil(d.Field("Name"))
+	a.Equal(Stats{DescriptionsBuilt: 1, TagsParsed: 2}, GetStats())
+
+	full, err := GetDescription(partialTestRecord{})
+	if a.NoError(err) {
+		a.Equal(*full.Field("Email"), *d.Field("Email"))
+	}
+
+	_, err = GetPartialDescription(partialTestRecord{}, "Name", "Missing", "Other")
+	a.EqualError(err, "reflectutil.GetPartialDescription(reflectutil.partialTestRecord): could not get description: no such fields: Missing, Other")
+
+	_, err = GetPartialDescription(1, "Name")
+	a.Error(err)
+}
@@ -0,0 +1,140 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MergePatch compares two values of the same struct type, like Diff, and
+// returns the differences as an RFC 7386 JSON merge patch keyed by json
+// names. Nested structs become nested objects, and fields that are no longer
+// present in the new value's encoding (zero with omitempty, or promoted
+// through a nil pointer) are set to nil. Fields excluded from json and
+// sensitive fields are left out.
+func MergePatch(oldValue, newValue interface{}) (map[string]interface{}, error) {
+	changes, err := patchChanges(oldValue, newValue)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.MergePatch: %w", err)
+	}
+
+	r := map[string]interface{}{}
+
+	for _, c := range changes {
+		m := r
+		for _, name := range c.names[:len(c.names)-1] {
+			sub, ok := m[name].(map[string]interface{})
+			if !ok {
+				sub = map[string]interface{}{}
+				m[name] = sub
+			}
+			m = sub
+		}
+
+		var v interface{}
+		if c.newPresent {
+			v = c.New
+		}
+
+		m[c.names[len(c.names)-1]] = v
+	}
+
+	return r, nil
+}
+
+// PatchOperation is a single RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON leaves the value out of remove operations only, since a nil,
+// false or zero value is meaningful for the others.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// JSONPatch compares two values of the same struct type, like Diff, and
+// returns the differences as RFC 6902 JSON Patch operations, with paths made
+// of json names. Fields that appear in or disappear from the encoding (see
+// MergePatch) are added or removed rather than replaced. Fields excluded from
+// json and sensitive fields are left out.
+func JSONPatch(oldValue, newValue interface{}) ([]PatchOperation, error) {
+	changes, err := patchChanges(oldValue, newValue)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.JSONPatch: %w", err)
+	}
+
+	r := []PatchOperation{}
+
+	for _, c := range changes {
+		op := PatchOperation{Op: "replace", Path: jsonPointer(c.names), Value: c.New}
+
+		switch {
+		case !c.oldPresent && !c.newPresent:
+			continue
+		case !c.oldPresent:
+			op.Op = "add"
+		case !c.newPresent:
+			op.Op, op.Value = "remove", nil
+		}
+
+		r = append(r, op)
+	}
+
+	return r, nil
+}
+
+func patchChanges(oldValue, newValue interface{}) ([]diffChange, error) {
+	ov, err := structValue(oldValue)
+	if err != nil {
+		return nil, fmt.Errorf("old value: %w", err)
+	}
+
+	nv, err := structValue(newValue)
+	if err != nil {
+		return nil, fmt.Errorf("new value: %w", err)
+	}
+
+	if ov.Type() != nv.Type() {
+		return nil, fmt.Errorf("old type %s does not match new type %s", ov.Type(), nv.Type())
+	}
+
+	changes, err := diffValues(ov, nv)
+	if err != nil {
+		return nil, err
+	}
+
+	r := changes[:0]
+	for _, c := range changes {
+		if c.names != nil && !c.Sensitive {
+			r = append(r, c)
+		}
+	}
+
+	return r, nil
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPointer builds an RFC 6901 JSON pointer from names.
+func jsonPointer(names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteByte('/')
+		b.WriteString(jsonPointerEscaper.Replace(name))
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchTestAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type patchTestUser struct {
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname,omitempty"`
+	Bio      string            `json:"bio,omitempty"`
+	Password string            `json:"password,redact"`
+	Internal string            `json:"-"`
+	Path     string            `json:"a/b~c"`
+	Address  patchTestAddress  `json:"address"`
+	Billing  *patchTestAddress `json:"billing"`
+}
+
+func patchTestValues() (patchTestUser, patchTestUser) {
+	before := patchTestUser{
+		Name:     "Jo",
+		Bio:      "hi",
+		Password: "old",
+		Internal: "i1",
+		Path:     "x",
+		Address:  patchTestAddress{Street: "1 Main St", City: "Perth"},
+	}
+
+	after := before
+	after.Name = "Joanne"
+	after.Nickname = "Jo"
+	after.Bio = ""
+	after.Password = "new"
+	after.Internal = "i2"
+	after.Path = "y"
+	after.Address = patchTestAddress{Street: "2 Main St"}
+	after.Billing = &patchTestAddress{Street: "PO Box 1"}
+
+	return before, after
+}
+
+func TestMergePatch(t *testing.T) {
+	a := assert.New(t)
+
+	before, after := patchTestValues()
+
+	p, err := MergePatch(before, &after)
+	if !a.NoError(err) {
+		return
+	}
+
+	b, err := json.Marshal(p)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.JSONEq(`{
+		"name": "Joanne",
+		"nickname": "Jo",
+		"bio": null,
+		"a/b~c": "y",
+		"address": {"street": "2 Main St", "city": null},
+		"billing": {"street": "PO Box 1"}
+	}`, string(b))
+
+	p, err = MergePatch(before, before)
+	if a.NoError(err) {
+		a.Equal(map[string]interface{}{}, p)
+	}
+
+	_, err = MergePatch(before, diffTestUser{})
+	a.ErrorContains(err, "does not match")
+}
+
+func TestJSONPatch(t *testing.T) {
+	a := assert.New(t)
+
+	before, after := patchTestValues()
+
+	p, err := JSONPatch(before, &after)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]PatchOperation{
+		{Op: "replace", Path: "/name", Value: "Joanne"},
+		{Op: "add", Path: "/nickname", Value: "Jo"},
+		{Op: "remove", Path: "/bio"},
+		{Op: "replace", Path: "/a~1b~0c", Value: "y"},
+		{Op: "replace", Path: "/address/street", Value: "2 Main St"},
+		{Op: "remove", Path: "/address/city"},
+		{Op: "replace", Path: "/billing", Value: &patchTestAddress{Street: "PO Box 1"}},
+	}, p)
+
+	b, err := json.Marshal(p[1:3])
+	if a.NoError(err) {
+		a.JSONEq(`[{"op": "add", "path": "/nickname", "value": "Jo"}, {"op": "remove", "path": "/bio"}]`, string(b))
+	}
+
+	b, err = json.Marshal(PatchOperation{Op: "replace", Path: "/n", Value: 0})
+	if a.NoError(err) {
+		a.JSONEq(`{"op": "replace", "path": "/n", "value": 0}`, string(b))
+	}
+
+	p, err = JSONPatch(&before, &before)
+	if a.NoError(err) {
+		a.Equal([]PatchOperation{}, p)
+	}
+}
@@ -0,0 +1,148 @@
+package reflectutil
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtoTag is the parsed form of a protoc-gen-go struct tag, e.g.
+// `protobuf:"bytes,2,rep,name=tags,json=tagList,proto3"`.
+type ProtoTag struct {
+	WireType string
+	Number   int
+	Label    string
+	Name     string
+	JSONName string
+	Enum     string
+	Packed   bool
+	Proto3   bool
+	Oneof    bool
+}
+
+// ProtoTag parses the field's protobuf tag. It returns false if the field has
+// no protobuf tag or the tag has no field number.
+func (f *Field) ProtoTag() (*ProtoTag, bool) {
+	t := f.tags.Get("protobuf")
+	if t == nil {
+		return nil, false
+	}
+
+	parts := t.ValueList(",")
+	if len(parts) < 2 {
+		return nil, false
+	}
+
+	p := &ProtoTag{WireType: t.value}
+
+	for i, e := range parts[1:] {
+		if i == 0 {
+			n, err := strconv.Atoi(e)
+			if err != nil {
+				return nil, false
+			}
+			p.Number = n
+			continue
+		}
+
+		k, v, _ := strings.Cut(e, "=")
+		switch k {
+		case "opt", "req", "rep":
+			p.Label = k
+		case "name":
+			p.Name = v
+		case "json":
+			p.JSONName = v
+		case "enum":
+			p.Enum = v
+		case "packed":
+			p.Packed = true
+		case "proto3":
+			p.Proto3 = true
+		case "oneof":
+			p.Oneof = true
+		}
+	}
+
+	if p.Number == 0 {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// ProtoNumber returns the field's protobuf field number.
+func (f *Field) ProtoNumber() (int, bool) {
+	p, ok := f.ProtoTag()
+	if !ok {
+		return 0, false
+	}
+
+	return p.Number, true
+}
+
+// ByProtoNumber returns the first field with the given protobuf field number.
+func (l FieldList) ByProtoNumber(n int) *Field {
+	for _, e := range l {
+		if m, ok := e.ProtoNumber(); ok && m == n {
+			return &e
+		}
+	}
+
+	return nil
+}
+
+// ProtoField is one row of a description's protobuf field-number table.
+type ProtoField struct {
+	Number int
+	Name   string
+	Field  string
+	Tag    ProtoTag
+}
+
+// ProtoFieldTable lists every field with a protobuf field number, ordered by
+// number (and then by field order, if numbers are repeated).
+func (s *StructDescription) ProtoFieldTable() []ProtoField {
+	r := []ProtoField{}
+
+	for _, f := range s.fields {
+		if p, ok := f.ProtoTag(); ok {
+			r = append(r, ProtoField{Number: p.Number, Name: p.Name, Field: f.name, Tag: *p})
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Number < r[j].Number })
+
+	return r
+}
+
+// ProtoNumberCollision reports a protobuf field number claimed by more than
+// one field.
+type ProtoNumberCollision struct {
+	Number int
+	Fields []string
+}
+
+func (s *StructDescription) ProtoNumberCollisions() []ProtoNumberCollision {
+	r := []ProtoNumberCollision{}
+
+	table := s.ProtoFieldTable()
+	for i := 0; i < len(table); {
+		j := i + 1
+		for j < len(table) && table[j].Number == table[i].Number {
+			j++
+		}
+
+		if j-i > 1 {
+			c := ProtoNumberCollision{Number: table[i].Number}
+			for _, e := range table[i:j] {
+				c.Fields = append(c.Fields, e.Field)
+			}
+			r = append(r, c)
+		}
+
+		i = j
+	}
+
+	return r
+}
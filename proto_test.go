@@ -0,0 +1,70 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProto(t *testing.T) {
+	a := assert.New(t)
+
+	type Message struct {
+		state  struct{}
+		Tags   []string `protobuf:"bytes,3,rep,name=tags,json=tagList,proto3"`
+		ID     int64    `protobuf:"varint,1,opt,name=id,proto3"`
+		Kind   int32    `protobuf:"varint,2,opt,name=kind,proto3,enum=pkg.Kind"`
+		Other  int32    `protobuf:"varint,2,opt,name=other,proto3"`
+		Weird  string   `protobuf:"bytes,x"`
+		Empty  string   `protobuf:""`
+		Oneof  isOneof  `protobuf_oneof:"choice"`
+		Packed []int32  `protobuf:"varint,4,rep,packed,name=packed"`
+	}
+
+	d, err := GetDescription(Message{})
+	if !a.NoError(err) {
+		return
+	}
+
+	p, ok := d.Field("Tags").ProtoTag()
+	if a.True(ok) {
+		a.Equal(&ProtoTag{WireType: "bytes", Number: 3, Label: "rep", Name: "tags", JSONName: "tagList", Proto3: true}, p)
+	}
+
+	p, ok = d.Field("Kind").ProtoTag()
+	if a.True(ok) {
+		a.Equal("pkg.Kind", p.Enum)
+	}
+
+	p, ok = d.Field("Packed").ProtoTag()
+	if a.True(ok) {
+		a.True(p.Packed)
+		a.False(p.Proto3)
+	}
+
+	_, ok = d.Field("Weird").ProtoNumber()
+	a.False(ok)
+	_, ok = d.Field("Empty").ProtoNumber()
+	a.False(ok)
+	_, ok = d.Field("Oneof").ProtoNumber()
+	a.False(ok)
+
+	n, ok := d.Field("ID").ProtoNumber()
+	a.True(ok)
+	a.Equal(1, n)
+
+	a.Equal("Kind", d.Fields().ByProtoNumber(2).Name())
+	a.Nil(d.Fields().ByProtoNumber(9))
+
+	var numbers, fields []interface{}
+	for _, e := range d.ProtoFieldTable() {
+		numbers = append(numbers, e.Number)
+		fields = append(fields, e.Field)
+	}
+	a.Equal([]interface{}{1, 2, 2, 3, 4}, numbers)
+	a.Equal([]interface{}{"ID", "Kind", "Other", "Tags", "Packed"}, fields)
+
+	a.Equal([]ProtoNumberCollision{{Number: 2, Fields: []string{"Kind", "Other"}}}, d.ProtoNumberCollisions())
+}
+
+type isOneof interface{ isOneof() }
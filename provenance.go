@@ -0,0 +1,44 @@
+package reflectutil
+
+// ValueSource records where a decoded value came from.
+type ValueSource struct {
+	// Source names the decoder or input the value came from, e.g. "map" for
+	// SetFields, "query" for BindRequest, or "env" for ConfigLoader.
+	Source string
+	// Key is the input key that supplied the value, as a dotted path for
+	// values found in nested maps.
+	Key string
+	// Default is true if the value came from the field's default tag.
+	Default bool
+}
+
+// Provenance maps the dotted path of each field set by a decoder (e.g.
+// "Database.Host") to where its value came from.
+type Provenance map[string]ValueSource
+
+// WithProvenance makes decoders record where each field's value came from in
+// p. Fields set more than once (like ConfigLoader's layers) keep the last
+// source that set them.
+func WithProvenance(p Provenance) Option {
+	return func(o *options) {
+		o.provenance = p
+	}
+}
+
+// record notes that the field called name, relative to the struct being
+// decoded, was set from key.
+func (o *options) record(name, key, source string) {
+	if o == nil {
+		return
+	}
+
+	o.recordSource(name, ValueSource{Source: source, Key: o.keyPrefix + key})
+}
+
+func (o *options) recordSource(name string, s ValueSource) {
+	if o == nil || o.provenance == nil {
+		return
+	}
+
+	o.provenance[o.fieldPrefix+name] = s
+}
@@ -0,0 +1,95 @@
+package reflectutil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type provenanceTestAddress struct {
+	City string `json:"city"`
+}
+
+type provenanceTestUser struct {
+	Name    string                `json:"name"`
+	Age     int                   `json:"age"`
+	Address provenanceTestAddress `json:"address"`
+}
+
+func TestProvenanceSetFields(t *testing.T) {
+	a := assert.New(t)
+
+	p := Provenance{}
+
+	var v provenanceTestUser
+	a.NoError(SetFields(&v, map[string]interface{}{
+		"name":    "Jo",
+		"address": map[string]interface{}{"city": "Paris"},
+	}, "json", WithProvenance(p)))
+
+	a.Equal(Provenance{
+		"Name":         {Source: "map", Key: "name"},
+		"Address":      {Source: "map", Key: "address"},
+		"Address.City": {Source: "map", Key: "address.city"},
+	}, p)
+}
+
+func TestProvenanceConfigLoader(t *testing.T) {
+	a := assert.New(t)
+
+	p := Provenance{}
+
+	l := ConfigLoader{
+		File: map[string]interface{}{
+			"timeout":  "10s",
+			"database": map[string]interface{}{"port": 6543},
+		},
+		LookupEnv: func(key string) (string, bool) {
+			if key == "DB_HOST" {
+				return "db.internal", true
+			}
+			return "", false
+		},
+		Flags:   map[string]string{"debug": "true"},
+		Options: []Option{WithProvenance(p)},
+	}
+
+	var c configTest
+	_, err := l.Load(&c)
+	a.NoError(err)
+
+	a.Equal(Provenance{
+		"Addr":          {Source: "default", Default: true},
+		"Timeout":       {Source: "file", Key: "timeout"},
+		"Debug":         {Source: "flag", Key: "debug"},
+		"Database.Host": {Source: "env", Key: "DB_HOST"},
+		"Database.Port": {Source: "file", Key: "database.port"},
+	}, p)
+}
+
+func TestProvenanceBindRequest(t *testing.T) {
+	a := assert.New(t)
+
+	p := Provenance{}
+
+	var v struct {
+		ID    string `path:"id"`
+		Page  int    `query:"page"`
+		Token string `header:"X-Token"`
+	}
+
+	r := httptest.NewRequest("GET", "/?page=2", nil)
+	r.Header.Set("X-Token", "t")
+
+	a.NoError(BindRequest(r, map[string]string{"id": "7"}, &v, WithProvenance(p)))
+	a.Equal(Provenance{
+		"ID":    {Source: "path", Key: "id"},
+		"Page":  {Source: "query", Key: "page"},
+		"Token": {Source: "header", Key: "X-Token"},
+	}, p)
+
+	p = Provenance{}
+	a.NoError(FromRedisHash(map[string]string{"id": "s1"}, &redisTestSession{}, WithProvenance(p)))
+	a.Equal(Provenance{"ID": {Source: "redis", Key: "id"}}, p)
+}
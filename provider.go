@@ -0,0 +1,116 @@
+package reflectutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Provider is a source of configuration values, in the style of koanf and
+// viper. Keys nest either as maps or, when a delimiter is in use, as flat
+// keys such as "db.host".
+type Provider interface {
+	Read() (map[string]interface{}, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func() (map[string]interface{}, error)
+
+func (fn ProviderFunc) Read() (map[string]interface{}, error) { return fn() }
+
+// StructProvider returns a Provider that reads v, a struct or pointer to one,
+// the same way as GetFields. With a non-empty delim, nested structs are
+// flattened into keys joined by delim; otherwise they are nested maps. v is
+// read each time Read is called, so a pointer reflects later changes.
+func StructProvider(v interface{}, tag, delim string) Provider {
+	return ProviderFunc(func() (map[string]interface{}, error) {
+		m, err := GetFields(v, tag)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.StructProvider: %w", err)
+		}
+
+		if delim == "" {
+			return m, nil
+		}
+
+		return flattenMap(m, "", delim, make(map[string]interface{})), nil
+	})
+}
+
+// FillFromProvider reads p and stores the result in v, which must be a pointer
+// to a struct, the same way as SetFields. With a non-empty delim, flat keys
+// are split on it into nested maps first, so "db.host" fills the Host field
+// of the DB field.
+func FillFromProvider(p Provider, v interface{}, tag, delim string, opts ...Option) error {
+	m, err := p.Read()
+	if err != nil {
+		return fmt.Errorf("reflectutil.FillFromProvider: %w", err)
+	}
+
+	if delim != "" {
+		if m, err = unflattenMap(m, delim); err != nil {
+			return fmt.Errorf("reflectutil.FillFromProvider: %w", err)
+		}
+	}
+
+	if err := SetFields(v, m, tag, opts...); err != nil {
+		return fmt.Errorf("reflectutil.FillFromProvider: %w", err)
+	}
+
+	return nil
+}
+
+// flattenMap copies the leaves of m into r, keyed by their path joined by
+// delim. Empty maps are kept as leaves, so they aren't lost.
+func flattenMap(m map[string]interface{}, prefix, delim string, r map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+			flattenMap(sub, prefix+k+delim, delim, r)
+			continue
+		}
+
+		r[prefix+k] = v
+	}
+
+	return r
+}
+
+// unflattenMap is the inverse of flattenMap. Keys are processed in sorted
+// order, and a key that is both a value and a parent of other keys is an
+// error.
+func unflattenMap(m map[string]interface{}, delim string) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	r := make(map[string]interface{})
+
+	for _, k := range keys {
+		parts := strings.Split(k, delim)
+
+		c := r
+		for i, p := range parts[:len(parts)-1] {
+			switch e := c[p].(type) {
+			case nil:
+				sub := make(map[string]interface{})
+				c[p] = sub
+				c = sub
+			case map[string]interface{}:
+				c = e
+			default:
+				return nil, fmt.Errorf("key %q conflicts with %q", k, strings.Join(parts[:i+1], delim))
+			}
+		}
+
+		last := parts[len(parts)-1]
+		if _, ok := c[last].(map[string]interface{}); ok {
+			return nil, fmt.Errorf("key %q conflicts with nested keys", k)
+		}
+
+		c[last] = m[k]
+	}
+
+	return r, nil
+}
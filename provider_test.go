@@ -0,0 +1,65 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type providerTestDB struct {
+	Host string `koanf:"host"`
+	Port int    `koanf:"port"`
+}
+
+type providerTestConfig struct {
+	Name string         `koanf:"name"`
+	DB   providerTestDB `koanf:"db"`
+}
+
+func TestStructProvider(t *testing.T) {
+	a := assert.New(t)
+
+	c := providerTestConfig{Name: "api", DB: providerTestDB{Host: "localhost", Port: 5432}}
+
+	m, err := StructProvider(&c, "koanf", ".").Read()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{"name": "api", "db.host": "localhost", "db.port": 5432}, m)
+
+	m, err = StructProvider(c, "koanf", "").Read()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(map[string]interface{}{"name": "api", "db": map[string]interface{}{"host": "localhost", "port": 5432}}, m)
+
+	_, err = StructProvider(1, "koanf", ".").Read()
+	a.Error(err)
+}
+
+func TestFillFromProvider(t *testing.T) {
+	a := assert.New(t)
+
+	var c providerTestConfig
+	a.NoError(FillFromProvider(ProviderFunc(func() (map[string]interface{}, error) {
+		return map[string]interface{}{"name": "api", "db.host": "localhost", "db.port": "5432"}, nil
+	}), &c, "koanf", "."))
+	a.Equal(providerTestConfig{Name: "api", DB: providerTestDB{Host: "localhost", Port: 5432}}, c)
+
+	var copied providerTestConfig
+	a.NoError(FillFromProvider(StructProvider(&c, "koanf", "_"), &copied, "koanf", "_"))
+	a.Equal(c, copied)
+
+	err := FillFromProvider(ProviderFunc(func() (map[string]interface{}, error) {
+		return map[string]interface{}{"db": "x", "db.host": "localhost"}, nil
+	}), &c, "koanf", ".")
+	a.ErrorContains(err, `key "db.host" conflicts with "db"`)
+
+	err = FillFromProvider(ProviderFunc(func() (map[string]interface{}, error) {
+		return nil, errors.New("boom")
+	}), &c, "koanf", ".")
+	a.ErrorContains(err, "boom")
+}
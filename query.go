@@ -0,0 +1,137 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotSortable is returned for sort parameters that don't name a
+	// sortable field.
+	ErrNotSortable = errors.New("field is not sortable")
+	// ErrNotFilterable is returned for filter parameters that don't name a
+	// filterable field.
+	ErrNotFilterable = errors.New("field is not filterable")
+)
+
+// SortableFields returns the fields whose tag called tag has a sortable
+// parameter, e.g. `api:"created_at,sortable"`.
+func (s *StructDescription) SortableFields(tag string) FieldList {
+	return s.fields.withParameter(tag, "sortable")
+}
+
+// FilterableFields returns the fields whose tag called tag has a filterable
+// parameter, e.g. `api:"status,filterable"`.
+func (s *StructDescription) FilterableFields(tag string) FieldList {
+	return s.fields.withParameter(tag, "filterable")
+}
+
+func (l FieldList) withParameter(tag, name string) FieldList {
+	var r FieldList
+	for _, f := range l {
+		if t := f.tags.Get(tag); t != nil && t.value != "-" && t.parameters.Has(name) {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
+// SortColumn is one column to order a query by.
+type SortColumn struct {
+	Column string
+	Desc   bool
+}
+
+// QueryColumns translates the parameter names a list endpoint accepts into
+// column names, allowing only sortable and filterable fields.
+type QueryColumns struct {
+	sortable   map[string]string
+	filterable map[string]string
+}
+
+// QueryColumns returns a translator from the parameter names given by the
+// tag called tag (defaulting to the field name) to the column names given by
+// the tag called columnTag (defaulting to the parameter name), e.g.
+//
+//	CreatedAt time.Time `api:"created,sortable" db:"created_at"`
+func (s *StructDescription) QueryColumns(tag, columnTag string) *QueryColumns {
+	q := &QueryColumns{sortable: make(map[string]string), filterable: make(map[string]string)}
+
+	for _, e := range []struct {
+		fields FieldList
+		m      map[string]string
+	}{
+		{s.SortableFields(tag), q.sortable},
+		{s.FilterableFields(tag), q.filterable},
+	} {
+		for _, f := range e.fields {
+			name := f.tags.Get(tag).Value()
+			if name == "" {
+				name = f.name
+			}
+
+			column := name
+			if t := f.tags.Get(columnTag); t != nil && t.value != "" && t.value != "-" {
+				column = t.value
+			}
+
+			e.m[name] = column
+		}
+	}
+
+	return q
+}
+
+// SortColumn returns the column for the sort parameter name.
+func (q *QueryColumns) SortColumn(name string) (string, error) {
+	c, ok := q.sortable[name]
+	if !ok {
+		return "", fmt.Errorf("reflectutil.QueryColumns.SortColumn: %q: %w", name, ErrNotSortable)
+	}
+
+	return c, nil
+}
+
+// FilterColumn returns the column for the filter parameter name.
+func (q *QueryColumns) FilterColumn(name string) (string, error) {
+	c, ok := q.filterable[name]
+	if !ok {
+		return "", fmt.Errorf("reflectutil.QueryColumns.FilterColumn: %q: %w", name, ErrNotFilterable)
+	}
+
+	return c, nil
+}
+
+// ParseSort translates a comma separated sort parameter like "-created,name"
+// into columns, where a leading "-" sorts in descending order and a leading
+// "+" is allowed for ascending order. Every name must be sortable.
+func (q *QueryColumns) ParseSort(s string) ([]SortColumn, error) {
+	var r []SortColumn
+
+	var errs []error
+
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(e, "-")
+		e = strings.TrimLeft(e, "+-")
+
+		c, ok := q.sortable[e]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%q: %w", e, ErrNotSortable))
+			continue
+		}
+
+		r = append(r, SortColumn{Column: c, Desc: desc})
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.QueryColumns.ParseSort: %w", err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,69 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type queryTestUser struct {
+	ID        int       `api:"id,sortable,filterable" db:"user_id"`
+	Name      string    `api:"name,sortable"`
+	Status    string    `api:"status,filterable" db:"state"`
+	CreatedAt time.Time `api:"created,sortable" db:"created_at"`
+	Password  string    `api:"-,sortable"`
+	Notes     string    `api:"notes"`
+}
+
+func TestSortableFilterableFields(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(queryTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"ID", "Name", "CreatedAt"}, d.SortableFields("api").Names())
+	a.Equal([]string{"ID", "Status"}, d.FilterableFields("api").Names())
+	a.Len(d.SortableFields("json"), 0)
+}
+
+func TestQueryColumns(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(queryTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	q := d.QueryColumns("api", "db")
+
+	c, err := q.SortColumn("created")
+	a.NoError(err)
+	a.Equal("created_at", c)
+
+	c, err = q.SortColumn("name")
+	a.NoError(err)
+	a.Equal("name", c)
+
+	_, err = q.SortColumn("status")
+	a.True(errors.Is(err, ErrNotSortable))
+
+	c, err = q.FilterColumn("status")
+	a.NoError(err)
+	a.Equal("state", c)
+
+	_, err = q.FilterColumn("notes")
+	a.EqualError(err, `reflectutil.QueryColumns.FilterColumn: "notes": field is not filterable`)
+
+	r, err := q.ParseSort("-created, +name,id,")
+	a.NoError(err)
+	a.Equal([]SortColumn{{Column: "created_at", Desc: true}, {Column: "name"}, {Column: "user_id"}}, r)
+
+	_, err = q.ParseSort("name,-Password,notes")
+	a.True(errors.Is(err, ErrNotSortable))
+	a.ErrorContains(err, `"Password": field is not sortable`)
+	a.ErrorContains(err, `"notes": field is not sortable`)
+}
@@ -0,0 +1,86 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ReadOnly reports whether the field is managed by the server rather than
+// written by clients, marked either with a readonly parameter on any tag
+// (`json:"id,readonly"`) or a readonly tag with any value other than "false".
+func (f *Field) ReadOnly() bool {
+	for _, t := range f.tags {
+		if t.name == "readonly" && t.value != "false" {
+			return true
+		}
+
+		if t.parameters.Has("readonly") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Writable returns the exported fields that aren't read only.
+func (l FieldList) Writable() FieldList {
+	var r FieldList
+	for _, f := range l {
+		if f.Exported() && !f.ReadOnly() {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
+// ReadOnlyPolicy controls what SetFields and BindRequest do with values for
+// read only fields.
+type ReadOnlyPolicy int
+
+const (
+	// ReadOnlyAllow sets read only fields like any other.
+	ReadOnlyAllow ReadOnlyPolicy = iota
+	// ReadOnlyIgnore leaves read only fields untouched, silently.
+	ReadOnlyIgnore
+	// ReadOnlyError reports each value for a read only field as an error
+	// wrapping ErrReadOnly, and leaves the field untouched.
+	ReadOnlyError
+)
+
+func (p ReadOnlyPolicy) String() string {
+	switch p {
+	case ReadOnlyAllow:
+		return "Allow"
+	case ReadOnlyIgnore:
+		return "Ignore"
+	case ReadOnlyError:
+		return "Error"
+	default:
+		return fmt.Sprintf("[UNKNOWN POLICY %d]", int(p))
+	}
+}
+
+// ErrReadOnly is wrapped by the errors reported under ReadOnlyError.
+var ErrReadOnly = errors.New("field is read only")
+
+// WithReadOnlyPolicy controls what happens to values for read only fields
+// (see Field.ReadOnly) when setting fields from maps or requests.
+func WithReadOnlyPolicy(policy ReadOnlyPolicy) Option {
+	return func(o *options) {
+		o.readOnlyPolicy = policy
+	}
+}
+
+// readOnly applies the read only policy to f, returning whether the field
+// may be written and, if not, the error to report, if any.
+func (o *options) readOnly(f *Field) (bool, error) {
+	if o == nil || o.readOnlyPolicy == ReadOnlyAllow || !f.ReadOnly() {
+		return true, nil
+	}
+
+	if o.readOnlyPolicy == ReadOnlyError {
+		return false, ErrReadOnly
+	}
+
+	return false, nil
+}
@@ -0,0 +1,81 @@
+package reflectutil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type readOnlyTestUser struct {
+	ID        string `json:"id,readonly" query:"id"`
+	Name      string `json:"name" query:"name"`
+	CreatedAt string `json:"created_at" readonly:"true"`
+	Notes     string `json:"notes" readonly:"false"`
+	internal  string
+}
+
+func TestFieldReadOnly(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(readOnlyTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.True(d.Field("ID").ReadOnly())
+	a.False(d.Field("Name").ReadOnly())
+	a.True(d.Field("CreatedAt").ReadOnly())
+	a.False(d.Field("Notes").ReadOnly())
+
+	a.Equal([]string{"Name", "Notes"}, d.Fields().Writable().Names())
+
+	a.Equal("Error", ReadOnlyError.String())
+	a.Equal("[UNKNOWN POLICY 7]", ReadOnlyPolicy(7).String())
+}
+
+func TestSetFieldsReadOnlyPolicy(t *testing.T) {
+	values := map[string]interface{}{"id": "x", "name": "Jo", "created_at": "now"}
+
+	for _, tc := range []struct {
+		policy   ReadOnlyPolicy
+		expected readOnlyTestUser
+		errors   []string
+	}{
+		{ReadOnlyAllow, readOnlyTestUser{ID: "x", Name: "Jo", CreatedAt: "now"}, nil},
+		{ReadOnlyIgnore, readOnlyTestUser{Name: "Jo"}, nil},
+		{ReadOnlyError, readOnlyTestUser{Name: "Jo"}, []string{"created_at: field is read only", "id: field is read only"}},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			a := assert.New(t)
+
+			var u readOnlyTestUser
+			err := SetFields(&u, values, "json", WithReadOnlyPolicy(tc.policy))
+
+			a.Equal(tc.expected, u)
+
+			if tc.errors == nil {
+				a.NoError(err)
+			} else {
+				a.ErrorIs(err, ErrReadOnly)
+				for _, e := range tc.errors {
+					a.ErrorContains(err, e)
+				}
+			}
+		})
+	}
+}
+
+func TestBindRequestReadOnlyPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	var u readOnlyTestUser
+	a.NoError(BindRequest(httptest.NewRequest("GET", "/?id=x&name=Jo", nil), nil, &u, WithReadOnlyPolicy(ReadOnlyIgnore)))
+	a.Equal(readOnlyTestUser{Name: "Jo"}, u)
+
+	u = readOnlyTestUser{}
+	err := BindRequest(httptest.NewRequest("GET", "/?id=x&name=Jo", nil), nil, &u, WithReadOnlyPolicy(ReadOnlyError))
+	a.ErrorIs(err, ErrReadOnly)
+	a.ErrorContains(err, `ID (query "id"): field is read only`)
+	a.Equal(readOnlyTestUser{Name: "Jo"}, u)
+}
@@ -0,0 +1,118 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RedisTag is the parsed form of a redis tag. As with go-redis, only fields
+// with a redis tag are mapped.
+type RedisTag struct {
+	Name      string
+	Skip      bool
+	OmitEmpty bool
+}
+
+func (f *Field) RedisTag() RedisTag {
+	t := f.tags.Get("redis")
+	if t == nil || t.value == "-" || t.value == "" {
+		return RedisTag{Skip: true}
+	}
+
+	return RedisTag{Name: t.value, OmitEmpty: t.parameters.Has("omitempty")}
+}
+
+// ToRedisHash returns alternating field names and string values from v, in
+// field order, suitable for passing straight to HSET. Nil pointers are always
+// left out.
+func ToRedisHash(v interface{}) ([]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToRedisHash: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToRedisHash: %w", err)
+	}
+
+	var r []interface{}
+
+	for _, f := range d.fields {
+		tag := f.RedisTag()
+		if tag.Skip || !f.Exported() {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (tag.OmitEmpty && fv.IsZero()) || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			continue
+		}
+
+		s, err := formatString(&f, fv)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.ToRedisHash: %w", &FieldError{Field: f.name, Err: err})
+		}
+
+		r = append(r, tag.Name, s)
+	}
+
+	return r, nil
+}
+
+// FromRedisHash fills v, which must be a pointer to a struct, from the result
+// of HGETALL. Hash fields that don't match a tagged struct field are subject
+// to WithUnknownKeyPolicy.
+func FromRedisHash(m map[string]string, v interface{}, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromRedisHash: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromRedisHash: %w", err)
+	}
+
+	o := getOptions(opts)
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for _, f := range d.fields {
+		tag := f.RedisTag()
+		if tag.Skip || !f.Exported() {
+			continue
+		}
+
+		s, ok := m[tag.Name]
+		if !ok {
+			continue
+		}
+
+		used[tag.Name] = true
+
+		fv, err := fieldByIndexAlloc(&f, rv)
+		if err == nil {
+			err = setFromString(&f, fv, s)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		o.record(f.name, tag.Name, "redis")
+	}
+
+	if err := unknownKeys(o, m, used); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.FromRedisHash: %w", err)
+	}
+
+	return nil
+}
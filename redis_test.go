@@ -0,0 +1,69 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redisTestSession struct {
+	ID       string        `redis:"id"`
+	UserID   int64         `redis:"user_id"`
+	Admin    bool          `redis:"admin,omitempty"`
+	TTL      time.Duration `redis:"ttl"`
+	Scopes   []string      `redis:"scopes,omitempty"`
+	Ref      *int          `redis:"ref"`
+	Ignored  string        `redis:"-"`
+	Untagged string
+}
+
+func TestToRedisHash(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := ToRedisHash(&redisTestSession{
+		ID:       "s1",
+		UserID:   42,
+		TTL:      time.Hour,
+		Ignored:  "x",
+		Untagged: "x",
+	})
+	a.NoError(err)
+	a.Equal([]interface{}{"id", "s1", "user_id", "42", "ttl", "1h0m0s"}, r)
+
+	_, err = ToRedisHash(1)
+	a.Error(err)
+}
+
+func TestFromRedisHash(t *testing.T) {
+	a := assert.New(t)
+
+	var s redisTestSession
+	err := FromRedisHash(map[string]string{
+		"id":       "s1",
+		"user_id":  "42",
+		"admin":    "1",
+		"ttl":      "30m",
+		"scopes":   "read,write",
+		"ref":      "7",
+		"Untagged": "x",
+		"unknown":  "x",
+	}, &s)
+	a.NoError(err)
+
+	ref := 7
+	a.Equal(redisTestSession{
+		ID:     "s1",
+		UserID: 42,
+		Admin:  true,
+		TTL:    30 * time.Minute,
+		Scopes: []string{"read", "write"},
+		Ref:    &ref,
+	}, s)
+
+	err = FromRedisHash(map[string]string{"user_id": "x", "admin": "maybe"}, &s)
+	a.ErrorContains(err, "UserID")
+	a.ErrorContains(err, "Admin")
+
+	a.Error(FromRedisHash(nil, s))
+}
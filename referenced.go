@@ -0,0 +1,73 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ReferencedTypes returns a description of every struct type reachable from
+// the described type through its fields, including through pointers, slices,
+// arrays, and map keys and values. Each type is listed once, in the order
+// it's first reached, breadth first; the described type itself is left out
+// even if it refers back to itself. Embedded structs are skipped, since their
+// fields are already promoted into their parents. Nested descriptions are
+// used where the fields have them, and the rest come from GetDescription.
+func (s *StructDescription) ReferencedTypes() ([]*StructDescription, error) {
+	seen := map[reflect.Type]bool{s.typ: true}
+
+	var r []*StructDescription
+	var errs []error
+
+	for queue := []*StructDescription{s}; len(queue) > 0; queue = queue[1:] {
+		d := queue[0]
+
+		for i := range d.fields {
+			f := &d.fields[i]
+			if f.embedded && derefType(f.typ).Kind() == reflect.Struct {
+				continue
+			}
+
+			for _, typ := range referencedStructTypes(f.typ, nil) {
+				if seen[typ] {
+					continue
+				}
+
+				seen[typ] = true
+
+				nd := f.description
+				if nd == nil || nd.typ != typ {
+					var err error
+					if nd, err = GetDescription(typ); err != nil {
+						errs = append(errs, &FieldError{Field: d.name + "." + f.name, Err: err})
+						continue
+					}
+				}
+
+				r = append(r, nd)
+				queue = append(queue, nd)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return r, fmt.Errorf("reflectutil.StructDescription.ReferencedTypes: %w", err)
+	}
+
+	return r, nil
+}
+
+// referencedStructTypes appends the struct types found in typ to r, looking
+// through pointers, slices, arrays and maps.
+func referencedStructTypes(typ reflect.Type, r []reflect.Type) []reflect.Type {
+	switch typ.Kind() {
+	case reflect.Struct:
+		return append(r, typ)
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return referencedStructTypes(typ.Elem(), r)
+	case reflect.Map:
+		return referencedStructTypes(typ.Elem(), referencedStructTypes(typ.Key(), r))
+	}
+
+	return r
+}
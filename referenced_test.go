@@ -0,0 +1,79 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type referencedTestKey struct{ A, B string }
+
+type referencedTestTag struct{ Name string }
+
+type referencedTestComment struct {
+	Author  *referencedTestUser
+	Replies []referencedTestComment
+}
+
+type referencedTestUser struct{ Name string }
+
+type referencedTestBase struct{ ID int }
+
+type referencedTestPost struct {
+	referencedTestBase
+	Title    string
+	Tags     []*referencedTestTag
+	Comments map[referencedTestKey][]referencedTestComment
+	Parent   *referencedTestPost
+	Fixed    [2]referencedTestTag
+}
+
+func TestReferencedTypes(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(referencedTestPost{})
+	if !a.NoError(err) {
+		return
+	}
+
+	r, err := d.ReferencedTypes()
+	if !a.NoError(err) {
+		return
+	}
+
+	var names []string
+	for _, d := range r {
+		names = append(names, d.Name())
+	}
+
+	a.Equal([]string{"referencedTestTag", "referencedTestKey", "referencedTestComment", "referencedTestUser"}, names)
+}
+
+func TestReferencedTypesNested(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(referencedTestComment{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	r, err := d.ReferencedTypes()
+	if !a.NoError(err) || !a.Len(r, 1) {
+		return
+	}
+
+	a.Same(d.Field("Author").Description(), r[0])
+}
+
+func TestReferencedTypesNone(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(referencedTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	r, err := d.ReferencedTypes()
+	a.NoError(err)
+	a.Empty(r)
+}
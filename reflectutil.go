@@ -1,29 +1,45 @@
 package reflectutil
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // main entry point
 
-func GetDescription(input interface{}) (*StructDescription, error) {
-	switch input := input.(type) {
-	case reflect.Type:
-		d, err := getDescriptionFromReflectType(input)
-		if err != nil {
-			return nil, fmt.Errorf("reflectutil.GetDescription(%T): could not get description: %w", input, err)
-		}
+// GetDescription describes input, a struct, pointer to struct, or
+// reflect.Type of either. Without options, descriptions come from a cache
+// shared by every caller, so they must not be modified; see WithoutCache.
+func GetDescription(input interface{}, opts ...Option) (*StructDescription, error) {
+	d, err := getDescription(input, opts, getOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.GetDescription(%T): could not get description: %w", input, err)
+	}
 
-		return d, nil
-	default:
-		d, err := getDescriptionFromReflectType(reflect.TypeOf(input))
-		if err != nil {
-			return nil, fmt.Errorf("reflectutil.GetDescription(%T): could not get description: %w", input, err)
-		}
+	return d, nil
+}
+
+// getDescription describes input with the current DescriptionProvider. o
+// holds the already applied opts, for the built in provider.
+func getDescription(input interface{}, opts []Option, o *options) (*StructDescription, error) {
+	typ, ok := input.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(input)
+	}
+
+	if p := getDescriptionProvider(); p != nil {
+		return p.Describe(typ, opts...)
+	}
 
-		return d, nil
+	if len(opts) == 0 && typ != nil {
+		return getCachedDescription(typ, o)
 	}
+
+	return getDescriptionFromReflectType(typ, o)
 }
 
 // GetDescriptionFromType is deprecated - use GetDescriptionFromReflectType
@@ -32,8 +48,8 @@ func GetDescriptionFromType(typ reflect.Type) (*StructDescription, error) {
 	return GetDescriptionFromReflectType(typ)
 }
 
-func GetDescriptionFromReflectType(typ reflect.Type) (*StructDescription, error) {
-	d, err := getDescriptionFromReflectType(typ)
+func GetDescriptionFromReflectType(typ reflect.Type, opts ...Option) (*StructDescription, error) {
+	d, err := getDescription(typ, opts, getOptions(opts))
 	if err != nil {
 		return nil, fmt.Errorf("reflectutil.GetDescriptionFromReflectType: could not get description: %w", err)
 	}
@@ -44,24 +60,44 @@ func GetDescriptionFromReflectType(typ reflect.Type) (*StructDescription, error)
 // struct
 
 type StructDescription struct {
-	name   string
-	typ    reflect.Type
-	fields FieldList
+	name      string
+	typ       reflect.Type
+	fields    FieldList
+	anonymous bool
 }
 
 func (s *StructDescription) Name() string       { return s.name }
 func (s *StructDescription) Type() reflect.Type { return s.typ }
 func (s *StructDescription) Fields() FieldList  { return s.fields }
 
-func (s *StructDescription) Field(name string) *Field { return s.fields.Get(name) }
+// IsAnonymousType reports whether the described type is an anonymous struct
+// literal. Those reached through a field are given a synthetic name made of
+// the enclosing type's name and the path to the field, e.g. "Config.Server".
+func (s *StructDescription) IsAnonymousType() bool { return s.anonymous }
+
+func (s *StructDescription) Field(name string) *Field     { return s.fields.Get(name) }
+func (s *StructDescription) FieldFold(name string) *Field { return s.fields.GetFold(name) }
+
+func (s *StructDescription) FieldByTagValue(tag, value string) *Field {
+	return s.fields.GetByTagValue(tag, value)
+}
+func (s *StructDescription) FieldByTagValueFold(tag, value string) *Field {
+	return s.fields.GetByTagValueFold(tag, value)
+}
 
 // field
 
 type Field struct {
-	name  string
-	index []int
-	typ   reflect.Type
-	tags  TagList
+	name        string
+	index       []int
+	typ         reflect.Type
+	tags        TagList
+	path        []string
+	owner       reflect.Type
+	embedded    bool
+	description *StructDescription
+
+	tagConflicts []TagConflict
 }
 
 func (f *Field) Name() string       { return f.name }
@@ -69,12 +105,117 @@ func (f *Field) Index() []int       { return f.index }
 func (f *Field) Type() reflect.Type { return f.typ }
 func (f *Field) Tags() TagList      { return f.tags }
 
-func (f *Field) Tag(name string) *Tag { return f.tags.Get(name) }
+// EffectiveName returns the name the field is known by in the given tag,
+// following the encoding/json convention: the tag's value if it has one, or
+// the field's name otherwise. It returns false if the tag's value is "-".
+func (f *Field) EffectiveName(tag string) (string, bool) {
+	t := f.tags.Get(tag)
+	switch {
+	case t == nil || t.value == "":
+		return f.name, true
+	case t.value == "-":
+		return "", false
+	default:
+		return t.value, true
+	}
+}
+
+// Aliases returns the values of every alias parameter on the named tag, e.g.
+// []string{"user_id", "uid"} for `json:"id,alias:user_id,alias:uid"`.
+func (f *Field) Aliases(tag string) []string {
+	r := []string{}
+
+	for _, t := range f.tags {
+		if t.name != tag {
+			continue
+		}
+
+		for _, p := range t.parameters {
+			if p.name == "alias" && p.Value() != "" {
+				r = append(r, p.Value())
+			}
+		}
+	}
+
+	return r
+}
+
+func (f *Field) hasTagValue(name, value string, fold, aliases bool) bool {
+	match := func(s string) bool {
+		return s == value || (fold && strings.EqualFold(s, value))
+	}
+
+	for _, t := range f.tags {
+		if t.name != name {
+			continue
+		}
+
+		if !aliases {
+			if match(t.value) {
+				return true
+			}
+
+			continue
+		}
+
+		for _, p := range t.parameters {
+			if p.name == "alias" && p.Value() != "" && match(p.Value()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Path returns the names of the embedded fields this field was promoted
+// through, outermost first. It is empty for fields declared directly on the
+// described struct.
+func (f *Field) Path() []string { return f.path }
+
+// Owner returns the struct type that declares the field - the described
+// struct itself, or the embedded struct the field was promoted from.
+func (f *Field) Owner() reflect.Type { return f.owner }
+
+// Exported reports whether the field's name is exported, and so whether its
+// value can be read and written from outside the declaring package.
+func (f *Field) Exported() bool {
+	r, _ := utf8.DecodeRuneInString(f.name)
+	return unicode.IsUpper(r)
+}
+
+// Embedded reports whether the field is an embedded (anonymous) field. The
+// fields promoted from it follow it in the FieldList.
+func (f *Field) Embedded() bool { return f.embedded }
+
+// Description returns the description of the field's struct type. It is only
+// populated when the description was built with WithNestedDescriptions.
+// Nested descriptions are shared: every field of the same type, in any parent
+// described with the same options, returns the same *StructDescription, so
+// they can be compared by identity. The exceptions are descriptions built with
+// WithAllowedTypes, WithDeniedTypes, WithArena or WithoutCache, and those of
+// recursive types, which refer back to their parents.
+func (f *Field) Description() *StructDescription { return f.description }
+
+func (f *Field) Tag(name string) *Tag     { return f.tags.Get(name) }
+func (f *Field) TagFold(name string) *Tag { return f.tags.GetFold(name) }
 
 // field list
 
+// FieldList is ordered the same way as reflect.VisibleFields: fields appear
+// in declaration order, with the fields promoted from an embedded struct
+// following immediately after the embedded field itself.
 type FieldList []Field
 
+func (l FieldList) Len() int { return len(l) }
+func (l FieldList) At(i int) *Field {
+	if i < 0 || i >= len(l) {
+		return nil
+	}
+
+	return &l[i]
+}
+
 func (l FieldList) Names() []string {
 	r := make([]string, len(l))
 	for i, e := range l {
@@ -91,6 +232,61 @@ func (l FieldList) Get(name string) *Field {
 
 	return nil
 }
+
+// GetFold is like Get, but falls back to a case-insensitive match if there is
+// no exact one, the same way encoding/json matches object keys.
+func (l FieldList) GetFold(name string) *Field {
+	if e := l.Get(name); e != nil {
+		return e
+	}
+
+	for _, e := range l {
+		if strings.EqualFold(e.name, name) {
+			return &e
+		}
+	}
+
+	return nil
+}
+
+// GetByTagValue returns the first field that has a tag with the given name
+// and value, e.g. the field named "id" in the json tag. If no tag value
+// matches, the tag's alias parameters are tried next (see Field.Aliases).
+func (l FieldList) GetByTagValue(tag, value string) *Field {
+	return l.getByTagValue(tag, value, false)
+}
+
+// GetByTagValueFold is like GetByTagValue, but falls back to comparing tag
+// values and aliases case-insensitively if there is no exact match.
+func (l FieldList) GetByTagValueFold(tag, value string) *Field {
+	if e := l.getByTagValue(tag, value, false); e != nil {
+		return e
+	}
+
+	return l.getByTagValue(tag, value, true)
+}
+
+func (l FieldList) getByTagValue(tag, value string, fold bool) *Field {
+	for _, aliases := range []bool{false, true} {
+		for _, e := range l {
+			if e.hasTagValue(tag, value, fold, aliases) {
+				return &e
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l FieldList) GetByIndex(index []int) *Field {
+	for _, e := range l {
+		if equalIndex(e.index, index) {
+			return &e
+		}
+	}
+
+	return nil
+}
 func (l FieldList) Has(name string) bool {
 	for _, e := range l {
 		if e.name == name {
@@ -117,6 +313,21 @@ loop:
 
 	return r
 }
+
+// WithTagPrefix returns the fields that have at least one tag whose name
+// starts with prefix, e.g. "swagger_".
+func (l FieldList) WithTagPrefix(prefix string) FieldList {
+	r := make(FieldList, 0, len(l))
+
+	for _, f := range l {
+		if len(f.tags.WithPrefix(prefix)) > 0 {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
 func (l FieldList) WithoutTag(name string) FieldList {
 	r := make(FieldList, 0, len(l))
 
@@ -167,19 +378,74 @@ loop:
 	return r
 }
 
+// ImplementingInterface returns the fields whose type implements iface, either
+// directly or through a pointer to the field (as with most implementations of
+// encoding.TextUnmarshaler). Like reflect.Type.Implements, it panics if iface
+// is not an interface type.
+func (l FieldList) ImplementingInterface(iface reflect.Type) FieldList {
+	r := make(FieldList, 0, len(l))
+
+	for _, f := range l {
+		if f.typ.Implements(iface) || reflect.PtrTo(f.typ).Implements(iface) {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
 // tag
 
 type Tag struct {
-	name       string
-	value      string
-	parameters ParameterList
+	name                string
+	value               string
+	parameters          ParameterList
+	duplicateParameters bool
+	span                Span
 }
 
 func (t *Tag) Name() string              { return t.name }
 func (t *Tag) Value() string             { return t.value }
 func (t *Tag) Parameters() ParameterList { return t.parameters }
 
-func (t *Tag) Parameter(name string) *Parameter { return t.parameters.Get(name) }
+func (t *Tag) Parameter(name string) *Parameter     { return t.parameters.Get(name) }
+func (t *Tag) ParameterFold(name string) *Parameter { return t.parameters.GetFold(name) }
+
+// HasDuplicateParameters reports whether any parameter was repeated in the
+// original tag, even if the duplicate parameter policy has since removed the
+// repeats.
+func (t *Tag) HasDuplicateParameters() bool { return t.duplicateParameters }
+
+// Span returns the position of the whole tag, e.g. `json:"name,omitempty"`,
+// within the struct tag it was parsed from. It's only recorded when parsing
+// with WithTagSpans.
+func (t *Tag) Span() Span { return t.span }
+
+// ValueList splits the tag's value into a list on sep, e.g. a "read write"
+// value with a sep of " ". Splitting on "," covers the value and the raw text
+// of every parameter, since commas would otherwise separate parameters. See
+// SplitList for the escaping and trimming rules.
+func (t *Tag) ValueList(sep string) []string {
+	if sep == "," {
+		return SplitList(t.rawValue(), sep)
+	}
+
+	return SplitList(t.value, sep)
+}
+
+func (t *Tag) rawValue() string {
+	if len(t.parameters) == 0 {
+		return t.value
+	}
+
+	var b strings.Builder
+	b.WriteString(t.value)
+	for _, p := range t.parameters {
+		b.WriteByte(',')
+		b.WriteString(p.rawValue())
+	}
+	return b.String()
+}
 
 // tag list
 
@@ -201,6 +467,22 @@ func (l TagList) Get(name string) *Tag {
 
 	return nil
 }
+
+// GetFold is like Get, but falls back to a case-insensitive match if there
+// is no exact one.
+func (l TagList) GetFold(name string) *Tag {
+	if e := l.Get(name); e != nil {
+		return e
+	}
+
+	for _, e := range l {
+		if strings.EqualFold(e.name, name) {
+			return &e
+		}
+	}
+
+	return nil
+}
 func (l TagList) Has(name string) bool {
 	for _, e := range l {
 		if e.name == name {
@@ -223,20 +505,144 @@ func (l TagList) WithName(name string) TagList {
 	return r
 }
 
+// WithPrefix returns the tags whose names start with prefix, in their original
+// order, so a family of related tags (`swagger_type:".." swagger_format:".."`)
+// can be gathered without listing each name.
+func (l TagList) WithPrefix(prefix string) TagList {
+	r := make(TagList, 0, len(l))
+
+	for _, e := range l {
+		if strings.HasPrefix(e.name, prefix) {
+			r = append(r, e)
+		}
+	}
+
+	return r
+}
+
 // parameter
 
 type Parameter struct {
 	name  string
 	value string
+	span  Span
+}
+
+func (p *Parameter) Name() string { return p.name }
+
+// Value returns the parameter's value. Values written as double or single
+// quoted strings, e.g. `msg:"hello, world"` or `msg:'hello, world'`, are
+// unquoted; RawValue returns them as written.
+func (p *Parameter) Value() string {
+	if s, ok := unquoteParameterValue(p.value); ok {
+		return s
+	}
+
+	return p.value
 }
 
-func (p *Parameter) Name() string  { return p.name }
-func (p *Parameter) Value() string { return p.value }
+func (p *Parameter) RawValue() string { return p.value }
+
+// Span returns the position of the parameter's raw text, e.g. "max:10",
+// within the struct tag it was parsed from, or within the tag value given to
+// ParseTag. It's only recorded when parsing with WithTagSpans.
+func (p *Parameter) Span() Span { return p.span }
+
+// ValueList splits the parameter's value into a list on sep, e.g. a
+// "read|write" value with a sep of "|". See SplitList for the escaping and
+// trimming rules.
+func (p *Parameter) ValueList(sep string) []string { return SplitList(p.Value(), sep) }
+
+func (p *Parameter) rawValue() string {
+	if p.value == "" {
+		return p.name
+	}
+
+	return p.name + ":" + p.value
+}
 
 // parameter list
 
+// ParameterList is an ordered multimap of a tag's parameters. Parameters keep
+// the order they were written in, and a name may appear more than once
+// (subject to WithDuplicateParameterPolicy); lookups by name find the first.
+// Set and Delete return new lists rather than modifying the receiver, so
+// lists taken from a description can be rewritten safely.
 type ParameterList []Parameter
 
+func (l ParameterList) Len() int { return len(l) }
+func (l ParameterList) At(i int) *Parameter {
+	if i < 0 || i >= len(l) {
+		return nil
+	}
+
+	return &l[i]
+}
+
+// Index returns the position of the first parameter called name, or -1.
+func (l ParameterList) Index(name string) int {
+	for i := range l {
+		if l[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// All returns every parameter called name, in order.
+func (l ParameterList) All(name string) []Parameter {
+	var r []Parameter
+	for _, e := range l {
+		if e.name == name {
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
+// Set returns a copy of the list with a single parameter called name, given
+// value. It takes the place of the first existing parameter with that name,
+// and any others are dropped; if there are none, it's added to the end.
+func (l ParameterList) Set(name, value string) ParameterList {
+	r := make(ParameterList, 0, len(l)+1)
+
+	found := false
+	for _, e := range l {
+		if e.name != name {
+			r = append(r, e)
+		} else if !found {
+			r = append(r, Parameter{name: name, value: value})
+			found = true
+		}
+	}
+
+	if !found {
+		r = append(r, Parameter{name: name, value: value})
+	}
+
+	return r
+}
+
+// Add returns a copy of the list with a parameter called name added to the
+// end, even if there's one by that name already.
+func (l ParameterList) Add(name, value string) ParameterList {
+	r := make(ParameterList, len(l), len(l)+1)
+	copy(r, l)
+	return append(r, Parameter{name: name, value: value})
+}
+
+// Delete returns a copy of the list without any parameters called name.
+func (l ParameterList) Delete(name string) ParameterList {
+	r := make(ParameterList, 0, len(l))
+	for _, e := range l {
+		if e.name != name {
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
 func (l ParameterList) Names() []string {
 	r := make([]string, len(l))
 	for i, e := range l {
@@ -253,6 +659,22 @@ func (l ParameterList) Get(name string) *Parameter {
 
 	return nil
 }
+
+// GetFold is like Get, but falls back to a case-insensitive match if there
+// is no exact one.
+func (l ParameterList) GetFold(name string) *Parameter {
+	if e := l.Get(name); e != nil {
+		return e
+	}
+
+	for _, e := range l {
+		if strings.EqualFold(e.name, name) {
+			return &e
+		}
+	}
+
+	return nil
+}
 func (l ParameterList) Has(name string) bool {
 	for _, e := range l {
 		if e.name == name {
@@ -265,7 +687,15 @@ func (l ParameterList) Has(name string) bool {
 
 // reflect implementation
 
-func getDescriptionFromReflectType(typ reflect.Type) (*StructDescription, error) {
+type describeContext struct {
+	options    *options
+	inProgress map[reflect.Type]*StructDescription
+	// inProgressRefs counts the fields given a description that was still
+	// being built.
+	inProgressRefs int
+}
+
+func getDescriptionFromReflectType(typ reflect.Type, o *options) (*StructDescription, error) {
 	if typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
@@ -274,38 +704,151 @@ func getDescriptionFromReflectType(typ reflect.Type) (*StructDescription, error)
 		return nil, fmt.Errorf("reflectutil.getDescriptionFromReflectType: input should be struct or pointer to struct")
 	}
 
-	fields, err := getFieldsFromReflectType(typ)
+	ctx := &describeContext{
+		options:    o,
+		inProgress: make(map[reflect.Type]*StructDescription),
+	}
+
+	return getDescriptionWithContext(typ, typ.Name(), ctx, 0)
+}
+
+func getDescriptionWithContext(typ reflect.Type, name string, ctx *describeContext, depth int) (*StructDescription, error) {
+	d := ctx.options.arena.newDescription()
+	d.name = name
+	d.typ = typ
+	d.anonymous = typ.Name() == ""
+
+	ctx.inProgress[typ] = d
+	defer delete(ctx.inProgress, typ)
+
+	fields, err := getFieldsFromReflectType(typ, ctx, depth)
 	if err != nil {
-		return nil, fmt.Errorf("reflectutil.getDescriptionFromReflectType: could not get field descriptions: %w", err)
+		return nil, fmt.Errorf("reflectutil.getDescriptionWithContext: could not get field descriptions: %w", err)
 	}
 
-	return &StructDescription{
-		name:   typ.Name(),
-		typ:    typ,
-		fields: fields,
-	}, nil
+	d.fields = fields
+
+	stats.descriptionsBuilt.Add(1)
+
+	return d, nil
 }
 
-func getFieldsFromReflectType(typ reflect.Type) (FieldList, error) {
-	fields := FieldList{}
+func getFieldsFromReflectType(typ reflect.Type, ctx *describeContext, depth int) (FieldList, error) {
+	structFields := visibleFields(typ)
 
-	structFields := reflect.VisibleFields(typ)
+	fields := ctx.options.arena.fieldList(len(structFields))
 
-	for i := range structFields {
-		structField := structFields[i]
+	var errs []error
 
-		tags, err := ParseTagList(string(structField.Tag))
+	for i := range structFields {
+		field, err := getFieldFromStructField(typ, structFields[i], ctx, depth)
 		if err != nil {
-			return nil, fmt.Errorf("reflectutil.getFieldsFromReflectType: could not get tags for field %s: %w", structField.Name, err)
+			errs = append(errs, err)
+			continue
 		}
 
-		fields = append(fields, Field{
-			name:  structField.Name,
-			index: structField.Index,
-			typ:   structField.Type,
-			tags:  tags,
-		})
+		fields = append(fields, field)
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.getFieldsFromReflectType: %w", err)
+	}
+
+	resolveEmbeddedTags(fields, ctx.options.embeddedTagPolicy)
+
 	return fields, nil
 }
+
+func getFieldFromStructField(typ reflect.Type, structField reflect.StructField, ctx *describeContext, depth int) (Field, error) {
+	tags, err := parseTagList(string(structField.Tag), ctx.options)
+	if err != nil {
+		return Field{}, fmt.Errorf("could not get tags for field %s: %w", structField.Name, err)
+	}
+
+	owner, path := getOwnerAndPath(typ, structField.Index)
+
+	field := Field{
+		name:     structField.Name,
+		index:    structField.Index,
+		typ:      structField.Type,
+		tags:     tags,
+		path:     path,
+		owner:    owner,
+		embedded: structField.Anonymous,
+	}
+
+	if nestedType := derefType(structField.Type); nestedType.Kind() == reflect.Struct && ctx.options.shouldDescend(nestedType, depth+1) {
+		nested, ok := ctx.inProgress[nestedType]
+		if ok {
+			ctx.inProgressRefs++
+		} else {
+			name := nestedType.Name()
+			if name == "" {
+				name = anonymousTypeName(typ, structField.Index, ctx)
+			}
+
+			nested, err = getNestedDescription(nestedType, name, ctx, depth+1)
+			if err != nil {
+				return Field{}, fmt.Errorf("could not describe field %s: %w", structField.Name, err)
+			}
+		}
+
+		field.description = nested
+	}
+
+	return field, nil
+}
+
+// anonymousTypeName makes up a name for the anonymous struct type of the field
+// at index within typ, from the name of the type that declares the field and
+// the field's name. Fields promoted from embedded structs are named after the
+// embedded type, so they get the same name wherever they're reached from.
+func anonymousTypeName(typ reflect.Type, index []int, ctx *describeContext) string {
+	owner, _ := getOwnerAndPath(typ, index)
+
+	name := owner.Name()
+	if d, ok := ctx.inProgress[owner]; ok {
+		name = d.name
+	}
+
+	fieldName := owner.Field(index[len(index)-1]).Name
+	if name == "" {
+		return fieldName
+	}
+
+	return name + "." + fieldName
+}
+
+func getOwnerAndPath(typ reflect.Type, index []int) (reflect.Type, []string) {
+	var path []string
+
+	for _, i := range index[:len(index)-1] {
+		structField := typ.Field(i)
+		path = append(path, structField.Name)
+		typ = derefType(structField.Type)
+	}
+
+	return typ, path
+}
+
+func equalIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
@@ -1,8 +1,12 @@
 package reflectutil
 
 import (
+	"encoding"
+	"encoding/json"
+	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -68,12 +72,12 @@ var getDescriptionTestCases = []struct {
 		},
 		result: &StructDescription{name: "S", fields: FieldList{
 			{name: "F1", index: []int{0}, typ: reflect.TypeOf(""), tags: []Tag{
-				{"t1", "v1", ParameterList{{"p1", ""}, {"p2k", "p2v"}}},
-				{"t2", "", ParameterList{{"p3", ""}, {"p4k", "p4v"}}},
+				{name: "t1", value: "v1", parameters: ParameterList{{name: "p1", value: ""}, {name: "p2k", value: "p2v"}}},
+				{name: "t2", value: "", parameters: ParameterList{{name: "p3", value: ""}, {name: "p4k", value: "p4v"}}},
 			}},
 			{name: "F2", index: []int{1}, typ: reflect.TypeOf(""), tags: []Tag{
-				{"t1", "v1", ParameterList{{"p1", ""}, {"p2k", "p2v"}}},
-				{"t2", "", ParameterList{{"p3", ""}, {"p4k", "p4v"}}},
+				{name: "t1", value: "v1", parameters: ParameterList{{name: "p1", value: ""}, {name: "p2k", value: "p2v"}}},
+				{name: "t2", value: "", parameters: ParameterList{{name: "p3", value: ""}, {name: "p4k", value: "p4v"}}},
 			}},
 		}},
 	},
@@ -88,10 +92,10 @@ var getDescriptionTestCases = []struct {
 		},
 		result: &StructDescription{name: "S", fields: FieldList{
 			{name: "ID", index: []int{0}, typ: reflect.TypeOf(int(1)), tags: []Tag{
-				{"sql", "id", ParameterList{{"table", "t"}}},
+				{name: "sql", value: "id", parameters: ParameterList{{name: "table", value: "t"}}},
 			}},
 			{name: "Name", index: []int{1}, typ: reflect.TypeOf(""), tags: []Tag{
-				{"sql", "name", ParameterList{}},
+				{name: "sql", value: "name", parameters: ParameterList{}},
 			}},
 		}},
 	},
@@ -106,15 +110,27 @@ var getDescriptionTestCases = []struct {
 		},
 		result: &StructDescription{name: "S", fields: FieldList{
 			{name: "ID", index: []int{0}, typ: reflect.TypeOf(int(1)), tags: []Tag{
-				{"json", "id", ParameterList{}},
+				{name: "json", value: "id", parameters: ParameterList{}},
 			}},
 			{name: "Name", index: []int{1}, typ: reflect.TypeOf(""), tags: []Tag{
-				{"json", "name", ParameterList{{"omitempty", ""}}},
+				{name: "json", value: "name", parameters: ParameterList{{name: "omitempty", value: ""}}},
 			}},
 		}},
 	},
 }
 
+// withOwner fills in the owner of each expected field; the test cases only
+// declare fields directly on their (function-local) struct types, so the owner
+// is always the described type.
+func withOwner(l FieldList, owner reflect.Type) FieldList {
+	r := make(FieldList, len(l))
+	for i, f := range l {
+		f.owner = owner
+		r[i] = f
+	}
+	return r
+}
+
 func TestGetDescription(t *testing.T) {
 	for _, tc := range getDescriptionTestCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -127,7 +143,7 @@ func TestGetDescription(t *testing.T) {
 			} else {
 				if a.NotNil(d) {
 					a.Equal(tc.result.name, d.Name())
-					a.Equal(tc.result.fields, d.Fields())
+					a.Equal(withOwner(tc.result.fields, d.Type()), d.Fields())
 				}
 			}
 
@@ -140,6 +156,22 @@ func TestGetDescription(t *testing.T) {
 	}
 }
 
+func TestGetDescriptionReportsEveryField(t *testing.T) {
+	a := assert.New(t)
+
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "A", Type: reflect.TypeOf(""), Tag: `json:"a`},
+		{Name: "B", Type: reflect.TypeOf(""), Tag: `json:"b"`},
+		{Name: "C", Type: reflect.TypeOf(""), Tag: `json:c`},
+	})
+
+	d, err := GetDescription(reflect.New(typ).Interface())
+	a.Nil(d)
+	a.ErrorContains(err, "could not get tags for field A")
+	a.NotContains(err.Error(), "field B")
+	a.ErrorContains(err, "could not get tags for field C")
+}
+
 func BenchmarkGetDescription(b *testing.B) {
 	for _, tc := range getDescriptionTestCases {
 		b.Run(tc.name, func(b *testing.B) {
@@ -163,7 +195,7 @@ func TestGetDescriptionFromReflectType(t *testing.T) {
 			} else {
 				if a.NotNil(d) {
 					a.Equal(tc.result.name, d.Name())
-					a.Equal(tc.result.fields, d.Fields())
+					a.Equal(withOwner(tc.result.fields, d.Type()), d.Fields())
 				}
 			}
 
@@ -239,9 +271,9 @@ func TestAccessors(t *testing.T) {
 	t.Run("StructDescription.Field", func(t *testing.T) {
 		a, d := get(t)
 
-		a.Equal(&Field{name: "Populated", index: []int{0}, typ: reflect.TypeOf(""), tags: []Tag{
-			{"sql", "populated", ParameterList{{"table", "t"}}},
-			{"json", "populated", ParameterList{{"omitempty", ""}}},
+		a.Equal(&Field{name: "Populated", index: []int{0}, typ: reflect.TypeOf(""), owner: reflect.TypeOf(S{}), tags: []Tag{
+			{name: "sql", value: "populated", parameters: ParameterList{{name: "table", value: "t"}}},
+			{name: "json", value: "populated", parameters: ParameterList{{name: "omitempty", value: ""}}},
 		}}, d.Field("Populated"))
 	})
 
@@ -292,17 +324,17 @@ func TestAccessors(t *testing.T) {
 		field, tag string
 		result     *Tag
 	}{
-		{"Populated", "sql", &Tag{"sql", "populated", ParameterList{{"table", "t"}}}},
-		{"Populated", "json", &Tag{"json", "populated", ParameterList{{"omitempty", ""}}}},
-		{"SQLEmpty", "sql", &Tag{"sql", "", ParameterList{}}},
-		{"SQLEmpty", "json", &Tag{"json", "sqlEmpty", ParameterList{}}},
-		{"SQLDash", "sql", &Tag{"sql", "-", ParameterList{}}},
-		{"SQLDash", "json", &Tag{"json", "sqlDash", ParameterList{}}},
-		{"JSONEmpty", "json", &Tag{"json", "", ParameterList{}}},
-		{"JSONEmpty", "sql", &Tag{"sql", "json_empty", ParameterList{}}},
-		{"JSONDash", "json", &Tag{"json", "-", ParameterList{}}},
-		{"JSONDash", "sql", &Tag{"sql", "json_dash", ParameterList{}}},
-		{"Repeated", "z", &Tag{"z", "x", ParameterList{{"x", "1"}, {"x", "2"}}}},
+		{"Populated", "sql", &Tag{name: "sql", value: "populated", parameters: ParameterList{{name: "table", value: "t"}}}},
+		{"Populated", "json", &Tag{name: "json", value: "populated", parameters: ParameterList{{name: "omitempty", value: ""}}}},
+		{"SQLEmpty", "sql", &Tag{name: "sql", value: "", parameters: ParameterList{}}},
+		{"SQLEmpty", "json", &Tag{name: "json", value: "sqlEmpty", parameters: ParameterList{}}},
+		{"SQLDash", "sql", &Tag{name: "sql", value: "-", parameters: ParameterList{}}},
+		{"SQLDash", "json", &Tag{name: "json", value: "sqlDash", parameters: ParameterList{}}},
+		{"JSONEmpty", "json", &Tag{name: "json", value: "", parameters: ParameterList{}}},
+		{"JSONEmpty", "sql", &Tag{name: "sql", value: "json_empty", parameters: ParameterList{}}},
+		{"JSONDash", "json", &Tag{name: "json", value: "-", parameters: ParameterList{}}},
+		{"JSONDash", "sql", &Tag{name: "sql", value: "json_dash", parameters: ParameterList{}}},
+		{"Repeated", "z", &Tag{name: "z", value: "x", parameters: ParameterList{{name: "x", value: "1"}, {name: "x", value: "2"}}, duplicateParameters: true}},
 	} {
 		t.Run("Field.Tag "+tc.field+"/"+tc.tag, func(t *testing.T) {
 			a, d := get(t)
@@ -316,12 +348,327 @@ func TestAccessors(t *testing.T) {
 		a, d := get(t)
 
 		a.Equal(TagList{
-			{"z", "x", ParameterList{{"x", "1"}, {"x", "2"}}},
-			{"z", "y", ParameterList{{"y", "1"}, {"y", "2"}}},
+			{name: "z", value: "x", parameters: ParameterList{{name: "x", value: "1"}, {name: "x", value: "2"}}, duplicateParameters: true},
+			{name: "z", value: "y", parameters: ParameterList{{name: "y", value: "1"}, {name: "y", value: "2"}}, duplicateParameters: true},
 		}, d.Field("Repeated").Tags().WithName("z"))
 	})
 }
 
+func TestTagPrefix(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		A string `swagger_type:"string" json:"a" swagger_format:"email"`
+		B string `json:"b" swagger:"b"`
+		C string `swaggerish:"c"`
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"swagger_type", "swagger_format"}, d.Field("A").Tags().WithPrefix("swagger_").Names())
+	a.Empty(d.Field("B").Tags().WithPrefix("swagger_"))
+	a.Equal([]string{"A"}, d.Fields().WithTagPrefix("swagger_").Names())
+	a.Equal([]string{"A", "B", "C"}, d.Fields().WithTagPrefix("swagger").Names())
+	a.Empty(d.Fields().WithTagPrefix("xml"))
+}
+
+func TestParameterListMultimap(t *testing.T) {
+	a := assert.New(t)
+
+	l := ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "a", value: "2"}, {name: "c", value: "x"}}
+
+	a.Equal(4, l.Len())
+	a.Equal(&Parameter{name: "b", value: ""}, l.At(1))
+	a.Nil(l.At(4))
+	a.Nil(l.At(-1))
+
+	a.Equal(0, l.Index("a"))
+	a.Equal(3, l.Index("c"))
+	a.Equal(-1, l.Index("z"))
+
+	a.Equal([]Parameter{{name: "a", value: "1"}, {name: "a", value: "2"}}, l.All("a"))
+	a.Nil(l.All("z"))
+
+	a.Equal(ParameterList{{name: "a", value: "3"}, {name: "b", value: ""}, {name: "c", value: "x"}}, l.Set("a", "3"))
+	a.Equal(ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "a", value: "2"}, {name: "c", value: "x"}, {name: "d", value: "4"}}, l.Set("d", "4"))
+	a.Equal(ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "a", value: "2"}, {name: "c", value: "x"}, {name: "a", value: "5"}}, l.Add("a", "5"))
+	a.Equal(ParameterList{{name: "b", value: ""}, {name: "c", value: "x"}}, l.Delete("a"))
+	a.Equal(ParameterList{}, ParameterList(nil).Delete("a"))
+
+	a.Equal(ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "a", value: "2"}, {name: "c", value: "x"}}, l, "receiver is not modified")
+}
+
+func TestFoldLookups(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		A string `GORM:"column:a,primaryKey" gorm:"column:b,omitEmpty,omitempty"`
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	f := d.Field("A")
+
+	a.Nil(f.Tag("Gorm"))
+	a.Equal("GORM", f.TagFold("Gorm").Name())
+	a.Equal("gorm", f.TagFold("gorm").Name())
+	a.Equal("GORM", f.Tags().GetFold("GORM").Name())
+	a.Nil(f.TagFold("sql"))
+
+	pk := f.Tag("GORM")
+	a.Nil(pk.Parameter("primarykey"))
+	a.Equal("primaryKey", pk.ParameterFold("primarykey").Name())
+	a.Nil(pk.ParameterFold("primary"))
+
+	oe := f.Tag("gorm")
+	a.Equal("omitEmpty", oe.ParameterFold("OMITEMPTY").Name())
+	a.Equal("omitempty", oe.ParameterFold("omitempty").Name())
+	a.Equal("omitEmpty", oe.Parameters().GetFold("omitEmpty").Name())
+}
+
+func TestFieldFoldLookups(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		UserID   string `json:"userId"`
+		UserId   string `json:"user_id"`
+		Username string `json:"USERNAME"`
+		Skipped  string `json:"-"`
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("UserId", d.FieldFold("UserId").Name())
+	a.Equal("UserID", d.FieldFold("userid").Name())
+	a.Equal("Username", d.Fields().GetFold("USERNAME").Name())
+	a.Nil(d.FieldFold("missing"))
+
+	a.Equal("UserID", d.FieldByTagValue("json", "userId").Name())
+	a.Nil(d.FieldByTagValue("json", "USERID"))
+	a.Nil(d.FieldByTagValue("sql", "userId"))
+
+	a.Equal("UserId", d.FieldByTagValueFold("json", "user_id").Name())
+	a.Equal("UserID", d.FieldByTagValueFold("json", "USERID").Name())
+	a.Equal("Username", d.FieldByTagValueFold("json", "username").Name())
+	a.Equal("Username", d.Fields().GetByTagValueFold("json", "USERNAME").Name())
+	a.Nil(d.FieldByTagValueFold("json", "nope"))
+}
+
+func TestFieldEffectiveName(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		Tagged   string `json:"tagged"`
+		Empty    string `json:",omitempty"`
+		Untagged string
+		Skipped  string `json:"-"`
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field, name string
+		ok          bool
+	}{
+		{"Tagged", "tagged", true},
+		{"Empty", "Empty", true},
+		{"Untagged", "Untagged", true},
+		{"Skipped", "", false},
+	} {
+		name, ok := d.Field(tc.field).EffectiveName("json")
+		a.Equal(tc.name, name, tc.field)
+		a.Equal(tc.ok, ok, tc.field)
+	}
+}
+
+func TestFieldAliases(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		ID     string `json:"id,alias:user_id,alias:uid"`
+		UserID string `json:"uid"`
+		Name   string `json:"name"`
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"user_id", "uid"}, d.Field("ID").Aliases("json"))
+	a.Equal([]string{}, d.Field("ID").Aliases("sql"))
+	a.Equal([]string{}, d.Field("Name").Aliases("json"))
+
+	a.Equal("ID", d.FieldByTagValue("json", "id").Name())
+	a.Equal("ID", d.FieldByTagValue("json", "user_id").Name())
+	a.Equal("UserID", d.FieldByTagValue("json", "uid").Name())
+	a.Nil(d.FieldByTagValue("json", "USER_ID"))
+	a.Equal("ID", d.FieldByTagValueFold("json", "USER_ID").Name())
+}
+
+func TestFieldListImplementingInterface(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		Name    string
+		Created time.Time
+		Updated *time.Time
+		Raw     json.RawMessage
+		Reader  io.Reader
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Created", "Updated"}, d.Fields().ImplementingInterface(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()).Names())
+	a.Equal([]string{"Created", "Updated", "Raw"}, d.Fields().ImplementingInterface(reflect.TypeOf((*json.Marshaler)(nil)).Elem()).Names())
+	a.Equal([]string{"Reader"}, d.Fields().ImplementingInterface(reflect.TypeOf((*io.Reader)(nil)).Elem()).Names())
+	a.Equal([]string{}, d.Fields().ImplementingInterface(reflect.TypeOf((*io.Writer)(nil)).Elem()).Names())
+}
+
+func TestFieldListOrderAndIndex(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct{ X, Y string }
+	type S struct {
+		A string
+		Inner
+		B string
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	l := d.Fields()
+
+	a.Equal([]string{"A", "Inner", "X", "Y", "B"}, l.Names())
+	a.Equal(5, l.Len())
+
+	for i, structField := range reflect.VisibleFields(reflect.TypeOf(S{})) {
+		if a.NotNil(l.At(i)) {
+			a.Equal(structField.Name, l.At(i).Name())
+		}
+
+		if a.NotNil(l.GetByIndex(structField.Index)) {
+			a.Equal(structField.Name, l.GetByIndex(structField.Index).Name())
+		}
+	}
+
+	a.Nil(l.At(-1))
+	a.Nil(l.At(5))
+	a.Nil(l.GetByIndex([]int{1, 2}))
+	a.Nil(l.GetByIndex(nil))
+}
+
+func TestFieldAncestry(t *testing.T) {
+	a := assert.New(t)
+
+	type Address struct{ Street string }
+	type Contact struct {
+		*Address
+		Phone string
+	}
+	type S struct {
+		Name string
+		Contact
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field string
+		path  []string
+		owner reflect.Type
+	}{
+		{"Name", nil, reflect.TypeOf(S{})},
+		{"Contact", nil, reflect.TypeOf(S{})},
+		{"Address", []string{"Contact"}, reflect.TypeOf(Contact{})},
+		{"Street", []string{"Contact", "Address"}, reflect.TypeOf(Address{})},
+		{"Phone", []string{"Contact"}, reflect.TypeOf(Contact{})},
+	} {
+		if f := d.Field(tc.field); a.NotNil(f, tc.field) {
+			a.Equal(tc.path, f.Path(), tc.field)
+			a.Equal(tc.owner, f.Owner(), tc.field)
+		}
+	}
+}
+
+func TestAnonymousTypeNames(t *testing.T) {
+	a := assert.New(t)
+
+	type Base struct {
+		Meta struct{ Version int }
+	}
+	type Config struct {
+		Base
+		Server struct {
+			Addr string
+			TLS  *struct{ Cert string }
+		}
+		Named Base
+	}
+
+	d, err := GetDescription(Config{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("Config", d.Name())
+	a.False(d.IsAnonymousType())
+
+	for _, tc := range []struct {
+		field []string
+		name  string
+	}{
+		{[]string{"Server"}, "Config.Server"},
+		{[]string{"Server", "TLS"}, "Config.Server.TLS"},
+		{[]string{"Meta"}, "Base.Meta"},
+		{[]string{"Base", "Meta"}, "Base.Meta"},
+		{[]string{"Named"}, "Base"},
+	} {
+		var f *Field
+		for i, name := range tc.field {
+			if i == 0 {
+				f = d.Field(name)
+			} else {
+				f = f.Description().Field(name)
+			}
+		}
+
+		if a.NotNil(f, tc.name) && a.NotNil(f.Description(), tc.name) {
+			a.Equal(tc.name, f.Description().Name())
+			a.Equal(tc.name != "Base", f.Description().IsAnonymousType(), tc.name)
+		}
+	}
+
+	d, err = GetDescription(struct{ A string }{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("", d.Name())
+	a.True(d.IsAnonymousType())
+}
+
 func BenchmarkAccessors(b *testing.B) {
 	type S struct {
 		Populated string `sql:"populated,table:t" json:"populated,omitempty"`
@@ -0,0 +1,55 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var typeRegistry = struct {
+	sync.RWMutex
+	descriptions map[reflect.Type]*StructDescription
+}{descriptions: make(map[reflect.Type]*StructDescription)}
+
+// RegisterType describes v, a struct or pointer to one, and adds it to the
+// type registry, so that checks like CheckConventions can be run over every
+// model in a program. Registering a type again replaces its description.
+func RegisterType(v interface{}, opts ...Option) error {
+	d, err := GetDescription(v, opts...)
+	if err != nil {
+		return fmt.Errorf("reflectutil.RegisterType: %w", err)
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	typeRegistry.descriptions[d.typ] = d
+
+	return nil
+}
+
+// UnregisterType removes typ, or the type it points to, from the type
+// registry.
+func UnregisterType(typ reflect.Type) {
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	delete(typeRegistry.descriptions, derefType(typ))
+}
+
+// RegisteredTypes returns the descriptions of every registered type, sorted
+// by FullName.
+func RegisteredTypes() []*StructDescription {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+
+	r := make([]*StructDescription, 0, len(typeRegistry.descriptions))
+	for _, d := range typeRegistry.descriptions {
+		r = append(r, d)
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].FullName() < r[j].FullName() })
+
+	return r
+}
@@ -0,0 +1,31 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryTestA struct{ A string }
+type registryTestB struct{ B string }
+
+func TestRegisterType(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(RegisterType(&registryTestB{}))
+	a.NoError(RegisterType(registryTestA{}))
+	defer UnregisterType(reflect.TypeOf(registryTestA{}))
+	defer UnregisterType(reflect.TypeOf(&registryTestB{}))
+
+	var names []string
+	for _, d := range RegisteredTypes() {
+		names = append(names, d.Name())
+	}
+	a.Equal([]string{"registryTestA", "registryTestB"}, names)
+
+	UnregisterType(reflect.TypeOf(&registryTestB{}))
+	a.Len(RegisteredTypes(), 1)
+
+	a.Error(RegisterType(1))
+}
@@ -0,0 +1,107 @@
+package reflectutil
+
+import (
+	"errors"
+	"reflect"
+)
+
+// The functions in this file stand in for parts of the reflect package that
+// some runtimes, like TinyGo, don't support. Builds with the tinygo tag use
+// them in place of the originals; other builds only use them in tests, which
+// check that they behave the same.
+//
+// Some features have no stand in, and are left out of tinygo builds instead:
+// RegisterConverter panics, since converters are called with
+// reflect.Value.Call, and Attributes falls back to []string for slices
+// rather than building typed ones with reflect.SliceOf.
+
+// walkVisibleFields is a port of reflect.VisibleFields that only relies on
+// NumField and Field.
+func walkVisibleFields(typ reflect.Type) []reflect.StructField {
+	w := &visibleFieldsWalker{
+		byName:   make(map[string]int),
+		visiting: make(map[reflect.Type]bool),
+		fields:   make([]reflect.StructField, 0, typ.NumField()),
+		index:    make([]int, 0, 2),
+	}
+
+	w.walk(typ)
+
+	// drop the fields that were hidden by others
+	r := w.fields[:0]
+	for _, f := range w.fields {
+		if f.Name != "" {
+			r = append(r, f)
+		}
+	}
+
+	return r
+}
+
+type visibleFieldsWalker struct {
+	byName   map[string]int
+	visiting map[reflect.Type]bool
+	fields   []reflect.StructField
+	index    []int
+}
+
+func (w *visibleFieldsWalker) walk(typ reflect.Type) {
+	if w.visiting[typ] {
+		return
+	}
+
+	w.visiting[typ] = true
+	defer delete(w.visiting, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		w.index = append(w.index, i)
+
+		add := true
+		if n, ok := w.byName[f.Name]; ok {
+			old := &w.fields[n]
+			switch {
+			case len(w.index) == len(old.Index):
+				// same depth: both are hidden
+				old.Name = ""
+				add = false
+			case len(w.index) < len(old.Index):
+				// the shallower field wins
+				old.Name = ""
+			default:
+				add = false
+			}
+		}
+
+		if add {
+			f.Index = append([]int(nil), w.index...)
+			w.byName[f.Name] = len(w.fields)
+			w.fields = append(w.fields, f)
+		}
+
+		if f.Anonymous {
+			if t := derefType(f.Type); t.Kind() == reflect.Struct {
+				w.walk(t)
+			}
+		}
+
+		w.index = w.index[:len(w.index)-1]
+	}
+}
+
+// walkFieldByIndex is reflect.Value.FieldByIndexErr without relying on it.
+func walkFieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, n := range index {
+		if i > 0 && v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct {
+			if v.IsNil() {
+				return reflect.Value{}, errors.New("reflect: indirection through nil pointer to embedded struct field " + v.Type().Elem().Name())
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(n)
+	}
+
+	return v, nil
+}
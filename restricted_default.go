@@ -0,0 +1,19 @@
+//go:build !tinygo
+
+package reflectutil
+
+import (
+	"reflect"
+)
+
+func visibleFields(typ reflect.Type) []reflect.StructField { return reflect.VisibleFields(typ) }
+
+func fieldByIndexErr(v reflect.Value, index []int) (reflect.Value, error) {
+	return v.FieldByIndexErr(index)
+}
+
+func sliceOf(typ reflect.Type) (reflect.Type, bool) { return reflect.SliceOf(typ), true }
+
+const callSupported = true
+
+func callFunc(fn reflect.Value, in []reflect.Value) []reflect.Value { return fn.Call(in) }
@@ -0,0 +1,61 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type restrictedTestA struct {
+	X, Y int
+}
+
+type restrictedTestB struct {
+	X int
+	Z string
+}
+
+type restrictedTestC struct {
+	*restrictedTestA
+	restrictedTestB
+	Y     string
+	inner int
+}
+
+type restrictedTestRecursive struct {
+	*restrictedTestRecursive
+	V int
+}
+
+func TestWalkVisibleFields(t *testing.T) {
+	for _, v := range []interface{}{
+		restrictedTestA{},
+		restrictedTestC{},
+		restrictedTestRecursive{},
+		arenaTestB{},
+		bsonTestUser{},
+		struct{}{},
+	} {
+		typ := reflect.TypeOf(v)
+		t.Run(typ.String(), func(t *testing.T) {
+			assert.Equal(t, reflect.VisibleFields(typ), walkVisibleFields(typ))
+		})
+	}
+}
+
+func TestWalkFieldByIndex(t *testing.T) {
+	a := assert.New(t)
+
+	v := reflect.ValueOf(restrictedTestC{Y: "y", restrictedTestB: restrictedTestB{Z: "z"}})
+
+	for _, index := range [][]int{{2}, {1, 1}, {0, 1}} {
+		want, wantErr := v.FieldByIndexErr(index)
+		got, gotErr := walkFieldByIndex(v, index)
+
+		a.Equal(wantErr, gotErr)
+		if wantErr == nil {
+			a.Equal(want.Interface(), got.Interface())
+		}
+	}
+}
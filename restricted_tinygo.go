@@ -0,0 +1,21 @@
+//go:build tinygo
+
+package reflectutil
+
+import (
+	"reflect"
+)
+
+func visibleFields(typ reflect.Type) []reflect.StructField { return walkVisibleFields(typ) }
+
+func fieldByIndexErr(v reflect.Value, index []int) (reflect.Value, error) {
+	return walkFieldByIndex(v, index)
+}
+
+func sliceOf(typ reflect.Type) (reflect.Type, bool) { return nil, false }
+
+const callSupported = false
+
+func callFunc(fn reflect.Value, in []reflect.Value) []reflect.Value {
+	panic("reflectutil: calling functions through reflection isn't supported with the tinygo tag")
+}
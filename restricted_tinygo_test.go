@@ -0,0 +1,25 @@
+//go:build tinygo
+
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTinyGoUnsupported(t *testing.T) {
+	a := assert.New(t)
+
+	a.Panics(func() { RegisterConverter(func(s string) (int, error) { return len(s), nil }) })
+
+	var v struct {
+		Counts []int `otel:"counts"`
+	}
+	v.Counts = []int{1, 2}
+
+	attrs, err := Attributes(v, "otel")
+	if a.NoError(err) && a.Len(attrs, 1) {
+		a.Equal([]string{"1", "2"}, attrs[0].Value)
+	}
+}
@@ -0,0 +1,176 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// RowIndex returns the position of the field within a row for the tag called
+// tag, as given by an index parameter (`csv:"id,index:0"`), and whether it has
+// one.
+func (f *Field) RowIndex(tag string) (int, bool, error) {
+	t := f.tags.Get(tag)
+	if t == nil {
+		return 0, false, nil
+	}
+
+	p := t.parameters.Get("index")
+	if p == nil {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(p.Value())
+	if err != nil || n < 0 {
+		return 0, false, fmt.Errorf("reflectutil.Field.RowIndex: invalid index %q for field %s", p.Value(), f.name)
+	}
+
+	return n, true, nil
+}
+
+// rowField is a field along with its position in a row.
+type rowField struct {
+	field *Field
+	index int
+}
+
+// rowFields returns the fields of d mapped to row positions for tag. Exported
+// fields are mapped unless their tag is "-"; a field without an index
+// parameter takes its position among the mapped fields in declaration order.
+func rowFields(d *StructDescription, tag string) ([]rowField, int, error) {
+	var r []rowField
+
+	var errs []error
+
+	used := make(map[int]string)
+	width := 0
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		if t := f.tags.Get(tag); t != nil && t.value == "-" {
+			continue
+		}
+
+		n, ok, err := f.RowIndex(tag)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			n = len(r)
+		}
+
+		if other, ok := used[n]; ok {
+			errs = append(errs, fmt.Errorf("fields %s and %s both have index %d", other, f.name, n))
+			continue
+		}
+
+		used[n] = f.name
+		r = append(r, rowField{field: f, index: n})
+
+		if n >= width {
+			width = n + 1
+		}
+	}
+
+	return r, width, errors.Join(errs...)
+}
+
+// ToRow returns the values of v's fields as a row, placing each field at the
+// index given by its tag called tag or, failing that, at its position in
+// declaration order. Positions no field claims are nil, as are fields
+// promoted through nil embedded pointers.
+func ToRow(v interface{}, tag string) ([]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToRow: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToRow: %w", err)
+	}
+
+	fields, width, err := rowFields(d, tag)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToRow: %w", err)
+	}
+
+	r := make([]interface{}, width)
+
+	for _, e := range fields {
+		if fv, ok := fieldValue(rv, e.field.index); ok {
+			r[e.index] = fv.Interface()
+		}
+	}
+
+	return r, nil
+}
+
+// FromRow fills v, which must be a pointer to a struct, from row, using the
+// same positions as ToRow. Values are converted the same way as SetFields
+// converts them, so rows of strings (e.g. from encoding/csv) work too. Short
+// rows leave the remaining fields untouched; positions that no field claims
+// are subject to WithUnknownKeyPolicy, and are collected by index.
+func FromRow(row []interface{}, v interface{}, tag string, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromRow: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromRow: %w", err)
+	}
+
+	fields, _, err := rowFields(d, tag)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromRow: %w", err)
+	}
+
+	o := getOptions(opts)
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for _, e := range fields {
+		if e.index >= len(row) {
+			continue
+		}
+
+		key := strconv.Itoa(e.index)
+		used[key] = true
+
+		fv, err := fieldByIndexAlloc(e.field, rv)
+		if err == nil {
+			err = assignValue(e.field, fv, row[e.index], nil, o.forKey(key, e.field.name))
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: e.field.name, Err: err})
+			continue
+		}
+
+		o.record(e.field.name, key, "row")
+	}
+
+	positions := make(map[string]interface{}, len(row))
+	for i, e := range row {
+		positions[strconv.Itoa(i)] = e
+	}
+
+	if err := unknownKeys(o, positions, used); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.FromRow: %w", err)
+	}
+
+	return nil
+}
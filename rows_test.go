@@ -0,0 +1,77 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rowsTestTrade struct {
+	Symbol string
+	Price  float64
+	Size   int
+	At     time.Duration
+	Note   string `row:"-"`
+	hidden string
+}
+
+type rowsTestCandle struct {
+	Close float64 `row:",index:4"`
+	Open  float64 `row:",index:1"`
+	Time  int64   `row:",index:0"`
+}
+
+func TestToRow(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := ToRow(rowsTestTrade{Symbol: "ABC", Price: 1.5, Size: 10, At: time.Second, Note: "x", hidden: "x"}, "row")
+	a.NoError(err)
+	a.Equal([]interface{}{"ABC", 1.5, 10, time.Second}, r)
+
+	r, err = ToRow(&rowsTestCandle{Close: 3, Open: 2, Time: 100}, "row")
+	a.NoError(err)
+	a.Equal([]interface{}{int64(100), 2.0, nil, nil, 3.0}, r)
+
+	_, err = ToRow(struct {
+		A int `row:",index:1"`
+		B int
+		C int `row:",index:x"`
+	}{}, "row")
+	a.ErrorContains(err, "fields A and B both have index 1")
+	a.ErrorContains(err, `invalid index "x" for field C`)
+
+	_, err = ToRow(1, "row")
+	a.Error(err)
+}
+
+func TestFromRow(t *testing.T) {
+	a := assert.New(t)
+
+	var v rowsTestTrade
+	a.NoError(FromRow([]interface{}{"ABC", "1.5", 10.0, "2s"}, &v, "row"))
+	a.Equal(rowsTestTrade{Symbol: "ABC", Price: 1.5, Size: 10, At: 2 * time.Second}, v)
+
+	var c rowsTestCandle
+	a.NoError(FromRow([]interface{}{int64(100), 2.0, 9.0, 9.0, 3.0}, &c, "row"))
+	a.Equal(rowsTestCandle{Close: 3, Open: 2, Time: 100}, c)
+
+	c = rowsTestCandle{}
+	a.NoError(FromRow([]interface{}{100}, &c, "row"))
+	a.Equal(rowsTestCandle{Time: 100}, c)
+
+	var keys []string
+	a.NoError(FromRow([]interface{}{1, 2, 3, 4, 5, 6}, &c, "row", WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"2", "3", "5"}, keys)
+
+	err := FromRow([]interface{}{1, 2, 3}, &c, "row", WithUnknownKeyPolicy(UnknownKeysError))
+	a.ErrorIs(err, ErrUnknownKey)
+
+	err = FromRow([]interface{}{"ABC", "x"}, &v, "row")
+	var fieldError *FieldError
+	if a.ErrorAs(err, &fieldError) {
+		a.Equal("Price", fieldError.Field)
+	}
+
+	a.Error(FromRow(nil, v, "row"))
+}
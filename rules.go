@@ -0,0 +1,128 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConditionalRuleKind is the name of a cross-field requirement rule, as used
+// by go-playground/validator.
+type ConditionalRuleKind string
+
+const (
+	RequiredIf         ConditionalRuleKind = "required_if"
+	RequiredUnless     ConditionalRuleKind = "required_unless"
+	RequiredWith       ConditionalRuleKind = "required_with"
+	RequiredWithAll    ConditionalRuleKind = "required_with_all"
+	RequiredWithout    ConditionalRuleKind = "required_without"
+	RequiredWithoutAll ConditionalRuleKind = "required_without_all"
+)
+
+// ConditionalRule is a requirement on a field that depends on other fields of
+// the same struct, referenced by name. For required_if and required_unless,
+// Values holds the value each of Fields is compared against.
+type ConditionalRule struct {
+	Kind   ConditionalRuleKind
+	Fields []string
+	Values []string
+}
+
+// ConditionalRules parses the conditional requirement rules from the field's
+// validate tag. Both `validate:"required_if=Status active"` and
+// `validate:",required_if:Status active"` are understood; arguments are
+// separated by spaces.
+func (f *Field) ConditionalRules() ([]ConditionalRule, error) {
+	t := f.tags.Get("validate")
+	if t == nil {
+		return nil, nil
+	}
+
+	var r []ConditionalRule
+
+	add := func(name, arg string) error {
+		k := ConditionalRuleKind(name)
+
+		switch k {
+		case RequiredIf, RequiredUnless:
+			args := strings.Fields(arg)
+			if len(args) == 0 || len(args)%2 != 0 {
+				return fmt.Errorf("reflectutil.Field.ConditionalRules(%s): %s needs field and value pairs; got %q", f.name, k, arg)
+			}
+
+			rule := ConditionalRule{Kind: k}
+			for i := 0; i < len(args); i += 2 {
+				rule.Fields = append(rule.Fields, args[i])
+				rule.Values = append(rule.Values, args[i+1])
+			}
+			r = append(r, rule)
+		case RequiredWith, RequiredWithAll, RequiredWithout, RequiredWithoutAll:
+			args := strings.Fields(arg)
+			if len(args) == 0 {
+				return fmt.Errorf("reflectutil.Field.ConditionalRules(%s): %s needs at least one field", f.name, k)
+			}
+
+			r = append(r, ConditionalRule{Kind: k, Fields: args})
+		}
+
+		return nil
+	}
+
+	if name, arg, ok := strings.Cut(t.value, "="); ok {
+		if err := add(name, arg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, p := range t.parameters {
+		name, arg, ok := strings.Cut(p.name, "=")
+		if !ok {
+			name, arg = p.name, p.Value()
+		}
+
+		if err := add(name, arg); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// ConditionalRuleDependencies maps the name of each field with conditional
+// rules to the names of the fields its rules refer to, in the order they're
+// first mentioned. References to fields that don't exist are an error.
+func (s *StructDescription) ConditionalRuleDependencies() (map[string][]string, error) {
+	r := make(map[string][]string)
+
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		rules, err := f.ConditionalRules()
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.StructDescription.ConditionalRuleDependencies: %w", err)
+		}
+
+		for _, rule := range rules {
+			for _, name := range rule.Fields {
+				if !s.fields.Has(name) {
+					return nil, fmt.Errorf("reflectutil.StructDescription.ConditionalRuleDependencies: field %s refers to unknown field %s", f.name, name)
+				}
+
+				if !containsString(r[f.name], name) {
+					r[f.name] = append(r[f.name], name)
+				}
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func containsString(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
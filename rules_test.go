@@ -0,0 +1,78 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rulesTestSignup struct {
+	Status  string
+	Kind    string
+	Email   string
+	Phone   string
+	Company string `validate:"required_if=Status business Kind paid"`
+	Contact string `validate:",required_with:Email Phone,required_unless:Status guest"`
+	Reason  string `validate:"required,required_without_all:Email Phone"`
+}
+
+func TestConditionalRules(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(rulesTestSignup{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		field string
+		rules []ConditionalRule
+	}{
+		{"Status", nil},
+		{"Company", []ConditionalRule{{Kind: RequiredIf, Fields: []string{"Status", "Kind"}, Values: []string{"business", "paid"}}}},
+		{"Contact", []ConditionalRule{
+			{Kind: RequiredWith, Fields: []string{"Email", "Phone"}},
+			{Kind: RequiredUnless, Fields: []string{"Status"}, Values: []string{"guest"}},
+		}},
+		{"Reason", []ConditionalRule{{Kind: RequiredWithoutAll, Fields: []string{"Email", "Phone"}}}},
+	} {
+		rules, err := d.Field(tc.field).ConditionalRules()
+		a.NoError(err, tc.field)
+		a.Equal(tc.rules, rules, tc.field)
+	}
+
+	deps, err := d.ConditionalRuleDependencies()
+	a.NoError(err)
+	a.Equal(map[string][]string{
+		"Company": {"Status", "Kind"},
+		"Contact": {"Email", "Phone", "Status"},
+		"Reason":  {"Email", "Phone"},
+	}, deps)
+}
+
+func TestConditionalRulesErrors(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(struct {
+		A string `validate:"required_if=B"`
+		B string `validate:",required_with"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, err = d.Field("A").ConditionalRules()
+	a.ErrorContains(err, "required_if needs field and value pairs")
+	_, err = d.Field("B").ConditionalRules()
+	a.ErrorContains(err, "required_with needs at least one field")
+
+	d, err = GetDescription(struct {
+		A string `validate:"required_with=Missing"`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	_, err = d.ConditionalRuleDependencies()
+	a.ErrorContains(err, "field A refers to unknown field Missing")
+}
@@ -0,0 +1,164 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SafeStringMaxDepth is how many levels of nested values SafeString renders
+// before abbreviating them as "...".
+var SafeStringMaxDepth = 5
+
+// SafeString renders v for debugging, like fmt's %v but with field names and
+// with sensitive fields (see Field.Sensitive) replaced by RedactionMask:
+//
+//	reflectutil.user{Name: "Jo", Password: ***, Address: &reflectutil.address{City: "Perth"}}
+//
+// Only exported fields are shown. Structs without exported fields, such as
+// time.Time, use their String method if they have one.
+func SafeString(v interface{}) string {
+	var b strings.Builder
+	safeString(&b, reflect.ValueOf(v), 0)
+	return b.String()
+}
+
+func safeString(b *strings.Builder, v reflect.Value, depth int) {
+	if !v.IsValid() {
+		b.WriteString("<nil>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct && hasExportedFields(v.Elem().Type()) {
+			b.WriteString("&")
+		}
+
+		safeString(b, v.Elem(), depth)
+		return
+	case reflect.Struct:
+		if hasExportedFields(v.Type()) {
+			safeStringStruct(b, v, depth)
+			return
+		}
+	}
+
+	if depth > SafeStringMaxDepth {
+		b.WriteString("...")
+		return
+	}
+
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			b.WriteString(s.String())
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		b.WriteString(strconv.Quote(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			b.WriteString("[]")
+			return
+		}
+
+		b.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			safeString(b, v.Index(i), depth+1)
+		}
+		b.WriteString("]")
+	case reflect.Map:
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		sort.Sort(byName{keys, names})
+
+		b.WriteString("map[")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			safeString(b, k, depth+1)
+			b.WriteString(": ")
+			safeString(b, v.MapIndex(k), depth+1)
+		}
+		b.WriteString("]")
+	default:
+		fmt.Fprint(b, v.Interface())
+	}
+}
+
+func safeStringStruct(b *strings.Builder, v reflect.Value, depth int) {
+	b.WriteString(v.Type().String())
+
+	if depth >= SafeStringMaxDepth {
+		b.WriteString("{...}")
+		return
+	}
+
+	d, err := GetDescription(v.Type())
+	if err != nil {
+		b.WriteString("{...}")
+		return
+	}
+
+	b.WriteString("{")
+
+	first := true
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		fv, ok := fieldValue(v, f.index)
+		if !ok {
+			continue
+		}
+
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+
+		b.WriteString(f.name)
+		b.WriteString(": ")
+
+		if f.Sensitive() {
+			b.WriteString(RedactionMask)
+			continue
+		}
+
+		safeString(b, fv, depth+1)
+	}
+
+	b.WriteString("}")
+}
+
+// byName sorts map keys by their printed form.
+type byName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (s byName) Len() int           { return len(s.keys) }
+func (s byName) Less(i, j int) bool { return s.names[i] < s.names[j] }
+func (s byName) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+}
@@ -0,0 +1,53 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type safeStringTestAddress struct {
+	City string
+}
+
+type safeStringTestUser struct {
+	Name     string
+	Password string `json:"password,redact"`
+	Age      int
+	Joined   time.Time
+	Address  *safeStringTestAddress
+	Previous []safeStringTestAddress
+	Labels   map[string]int
+	Missing  *safeStringTestAddress
+	private  string
+}
+
+type safeStringTestNode struct {
+	Next *safeStringTestNode
+}
+
+func TestSafeString(t *testing.T) {
+	a := assert.New(t)
+
+	u := safeStringTestUser{
+		Name:     "Jo",
+		Password: "hunter2",
+		Age:      30,
+		Joined:   time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Address:  &safeStringTestAddress{City: "Perth"},
+		Previous: []safeStringTestAddress{{City: "Sydney"}},
+		Labels:   map[string]int{"b": 2, "a": 1},
+		private:  "x",
+	}
+
+	a.Equal(`&reflectutil.safeStringTestUser{Name: "Jo", Password: ***, Age: 30, Joined: 2023-01-02 03:04:05 +0000 UTC, Address: &reflectutil.safeStringTestAddress{City: "Perth"}, Previous: [reflectutil.safeStringTestAddress{City: "Sydney"}], Labels: map["a": 1, "b": 2], Missing: <nil>}`, SafeString(&u))
+	a.Equal(`reflectutil.safeStringTestAddress{City: "Perth"}`, SafeString(*u.Address))
+
+	a.Equal(`"x"`, SafeString("x"))
+	a.Equal("<nil>", SafeString(nil))
+
+	n := &safeStringTestNode{}
+	n.Next = n
+	a.Equal("&reflectutil.safeStringTestNode{Next: &reflectutil.safeStringTestNode{Next: &reflectutil.safeStringTestNode{Next: &reflectutil.safeStringTestNode{Next: &reflectutil.safeStringTestNode{Next: &reflectutil.safeStringTestNode{...}}}}}}", SafeString(n))
+}
@@ -0,0 +1,189 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DefaultValue returns the raw value of the field's default tag, and whether
+// it has one. Commas are kept, so `default:"a,b"` gives "a,b".
+func (f *Field) DefaultValue() (string, bool) {
+	if t := f.tags.Get("default"); t != nil {
+		return t.rawValue(), true
+	}
+
+	return "", false
+}
+
+// ApplyDefaults sets every zero-valued field of v that has a default tag to
+// the tag's value, converted to the field's type. Defaults like "now()" are
+// computed by the named DefaultProvider instead. v must be a pointer to a
+// struct; nested structs are handled too.
+func ApplyDefaults(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ApplyDefaults: %w", err)
+	}
+
+	if err := walkValue(rv, "", applyFieldDefault); err != nil {
+		return fmt.Errorf("reflectutil.ApplyDefaults: %w", err)
+	}
+
+	return nil
+}
+
+func applyFieldDefault(f *Field, v reflect.Value, path string) (bool, error) {
+	s, ok := f.DefaultValue()
+	if !ok || !v.CanSet() || !v.IsZero() {
+		return true, nil
+	}
+
+	if err := setDefault(f, v, s); err != nil {
+		return false, &FieldError{Field: path, Err: fmt.Errorf("invalid default %q: %w", s, err)}
+	}
+
+	return true, nil
+}
+
+// Sensitive reports whether the field holds sensitive data, marked either
+// with a sensitive tag (`sensitive:"true"`, or any value other than "false"),
+// a secret tag, or a sensitive or redact parameter on any tag
+// (`json:"password,redact"`).
+func (f *Field) Sensitive() bool {
+	for _, t := range f.tags {
+		if t.name == "sensitive" && t.value != "false" {
+			return true
+		}
+
+		if t.name == "secret" && t.value != "" {
+			return true
+		}
+
+		if t.parameters.Has("sensitive") || t.parameters.Has("redact") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact sets every sensitive field of v to its zero value. v must be a
+// pointer to a struct; nested structs are handled too.
+func Redact(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.Redact: %w", err)
+	}
+
+	if err := walkValue(rv, "", redactField); err != nil {
+		return fmt.Errorf("reflectutil.Redact: %w", err)
+	}
+
+	return nil
+}
+
+func redactField(f *Field, v reflect.Value, path string) (bool, error) {
+	if !f.Sensitive() {
+		return true, nil
+	}
+
+	if v.CanSet() {
+		v.Set(reflect.Zero(v.Type()))
+	}
+
+	return false, nil
+}
+
+// ClearByTag sets every field of v that has the given tag value to its zero
+// value, e.g. ClearByTag(&user, "scope", "internal"). v must be a pointer to a
+// struct; nested structs, and slices and arrays of them, are handled too.
+func ClearByTag(v interface{}, tag, value string) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ClearByTag: %w", err)
+	}
+
+	err = walkValue(rv, "", func(f *Field, v reflect.Value, path string) (bool, error) {
+		if !f.hasTagValue(tag, value, false, false) {
+			return true, nil
+		}
+
+		if v.CanSet() {
+			v.Set(reflect.Zero(v.Type()))
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("reflectutil.ClearByTag: %w", err)
+	}
+
+	return nil
+}
+
+// SanitizePhase selects the steps run by Sanitize. They always run in the
+// order they are declared: transforms, then defaults (so a value transformed
+// to empty still gets its default), then redaction.
+type SanitizePhase int
+
+const (
+	SanitizeTransforms SanitizePhase = 1 << iota
+	SanitizeDefaults
+	SanitizeRedaction
+)
+
+func (p SanitizePhase) String() string {
+	switch p {
+	case SanitizeTransforms:
+		return "transforms"
+	case SanitizeDefaults:
+		return "defaults"
+	case SanitizeRedaction:
+		return "redaction"
+	default:
+		return fmt.Sprintf("[UNKNOWN PHASE %d]", int(p))
+	}
+}
+
+type SanitizeOptions struct {
+	// Phases is the set of phases to run. The zero value runs transforms and
+	// defaults - redaction has to be asked for explicitly, since it throws
+	// data away.
+	Phases SanitizePhase
+}
+
+// Sanitize runs the selected phases over v, which must be a pointer to a
+// struct. Every phase runs even if an earlier one reported problems; all the
+// per-field errors are returned together.
+func Sanitize(v interface{}, opts SanitizeOptions) error {
+	if _, err := settableStructValue(v); err != nil {
+		return fmt.Errorf("reflectutil.Sanitize: %w", err)
+	}
+
+	phases := opts.Phases
+	if phases == 0 {
+		phases = SanitizeTransforms | SanitizeDefaults
+	}
+
+	var errs []error
+
+	for _, phase := range []struct {
+		phase SanitizePhase
+		fn    func(interface{}) error
+	}{
+		{SanitizeTransforms, ApplyTransforms},
+		{SanitizeDefaults, ApplyDefaults},
+		{SanitizeRedaction, Redact},
+	} {
+		if phases&phase.phase == 0 {
+			continue
+		}
+
+		if err := phase.fn(v); err != nil {
+			errs = append(errs, fmt.Errorf("reflectutil.Sanitize: %s: %w", phase.phase, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
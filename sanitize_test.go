@@ -0,0 +1,165 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sanitizeTestRequest struct {
+	Username string        `json:"username" transform:"trim,lower"`
+	Password string        `json:"password,redact"`
+	Token    string        `sensitive:"true"`
+	Role     string        `transform:"trim" default:"user"`
+	Limit    int           `default:"10"`
+	Timeout  time.Duration `default:"30s"`
+	Scopes   []string      `default:"read,write"`
+	Started  time.Time     `default:"2023-01-02" json:",format:DateOnly"`
+	Note     string        `sensitive:"false"`
+	Nested   struct {
+		Enabled *bool `default:"true"`
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	a := assert.New(t)
+
+	r := sanitizeTestRequest{Limit: 5}
+
+	if !a.NoError(ApplyDefaults(&r)) {
+		return
+	}
+
+	a.Equal("user", r.Role)
+	a.Equal(5, r.Limit)
+	a.Equal(30*time.Second, r.Timeout)
+	a.Equal([]string{"read", "write"}, r.Scopes)
+	a.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), r.Started)
+	if a.NotNil(r.Nested.Enabled) {
+		a.True(*r.Nested.Enabled)
+	}
+
+	type Bad struct {
+		N int `default:"lots"`
+	}
+
+	var fieldError *FieldError
+	err := ApplyDefaults(&Bad{})
+	if a.True(errors.As(err, &fieldError)) {
+		a.Equal("N", fieldError.Field)
+	}
+	a.ErrorContains(err, `invalid default "lots"`)
+}
+
+func TestSensitiveAndRedact(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(sanitizeTestRequest{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"Password", "Token"}, func() []string {
+		var r []string
+		for _, f := range d.Fields() {
+			if f.Sensitive() {
+				r = append(r, f.Name())
+			}
+		}
+		return r
+	}())
+
+	r := sanitizeTestRequest{Username: "u", Password: "p", Token: "t", Note: "n"}
+	if a.NoError(Redact(&r)) {
+		a.Equal("u", r.Username)
+		a.Equal("", r.Password)
+		a.Equal("", r.Token)
+		a.Equal("n", r.Note)
+	}
+}
+
+func TestClearByTag(t *testing.T) {
+	a := assert.New(t)
+
+	type Note struct {
+		Text   string
+		Author string `scope:"internal"`
+	}
+
+	type Account struct {
+		ID       string
+		Balance  int    `scope:"internal"`
+		Comment  string `scope:"public"`
+		Notes    []Note
+		Pinned   *Note
+		Internal *Note `scope:"internal"`
+	}
+
+	v := Account{
+		ID:       "1",
+		Balance:  100,
+		Comment:  "hi",
+		Notes:    []Note{{"a", "alice"}, {"b", "bob"}},
+		Pinned:   &Note{"p", "pat"},
+		Internal: &Note{"i", "ivy"},
+	}
+
+	if a.NoError(ClearByTag(&v, "scope", "internal")) {
+		a.Equal(Account{
+			ID:      "1",
+			Comment: "hi",
+			Notes:   []Note{{Text: "a"}, {Text: "b"}},
+			Pinned:  &Note{Text: "p"},
+		}, v)
+	}
+
+	a.ErrorContains(ClearByTag(v, "scope", "internal"), "pointer to a struct")
+}
+
+func TestSanitize(t *testing.T) {
+	t.Run("default phases", func(t *testing.T) {
+		a := assert.New(t)
+
+		r := sanitizeTestRequest{Username: " Admin ", Password: "secret", Role: "   "}
+
+		if a.NoError(Sanitize(&r, SanitizeOptions{})) {
+			a.Equal("admin", r.Username)
+			a.Equal("user", r.Role)
+			a.Equal(10, r.Limit)
+			a.Equal("secret", r.Password)
+		}
+	})
+
+	t.Run("with redaction", func(t *testing.T) {
+		a := assert.New(t)
+
+		r := sanitizeTestRequest{Username: " Admin ", Password: "secret"}
+
+		if a.NoError(Sanitize(&r, SanitizeOptions{Phases: SanitizeTransforms | SanitizeRedaction})) {
+			a.Equal("admin", r.Username)
+			a.Equal("", r.Role)
+			a.Equal("", r.Password)
+		}
+	})
+
+	t.Run("errors from every phase", func(t *testing.T) {
+		a := assert.New(t)
+
+		type Bad struct {
+			A string `transform:"nope"`
+			B int    `default:"x"`
+		}
+
+		err := Sanitize(&Bad{}, SanitizeOptions{})
+		a.ErrorContains(err, `reflectutil.Sanitize: transforms: `)
+		a.ErrorContains(err, `A: unknown transformer "nope"`)
+		a.ErrorContains(err, `reflectutil.Sanitize: defaults: `)
+		a.ErrorContains(err, `B: invalid default "x"`)
+	})
+
+	t.Run("requires a pointer", func(t *testing.T) {
+		assert.ErrorContains(t, Sanitize(sanitizeTestRequest{}, SanitizeOptions{}), "pointer to a struct")
+	})
+}
@@ -0,0 +1,137 @@
+package reflectutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SecretRef is a parsed secret tag, such as `secret:"vault:db/prod#password"`,
+// which names a provider, a path within it, and optionally a key at that path.
+type SecretRef struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r SecretRef) String() string {
+	if r.Key == "" {
+		return r.Provider + ":" + r.Path
+	}
+
+	return r.Provider + ":" + r.Path + "#" + r.Key
+}
+
+// ParseSecretRef parses a reference in the form "provider:path#key", where
+// the key is optional.
+func ParseSecretRef(s string) (SecretRef, error) {
+	provider, rest, ok := strings.Cut(s, ":")
+	if !ok || provider == "" || rest == "" {
+		return SecretRef{}, fmt.Errorf("reflectutil.ParseSecretRef: expected provider:path#key; got %q", s)
+	}
+
+	path, key, _ := strings.Cut(rest, "#")
+
+	return SecretRef{Provider: provider, Path: path, Key: key}, nil
+}
+
+// SecretRef returns the parsed secret tag of the field, if it has one.
+func (f *Field) SecretRef() (SecretRef, bool, error) {
+	t := f.tags.Get("secret")
+	if t == nil || t.value == "" {
+		return SecretRef{}, false, nil
+	}
+
+	r, err := ParseSecretRef(t.value)
+	if err != nil {
+		return SecretRef{}, false, fmt.Errorf("reflectutil.Field.SecretRef(%s): %w", f.name, err)
+	}
+
+	return r, true, nil
+}
+
+// SecretResolver fetches the value of a secret from some backing store.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref SecretRef) (string, error)
+}
+
+// SecretResolverFunc adapts a function to the SecretResolver interface.
+type SecretResolverFunc func(ctx context.Context, ref SecretRef) (string, error)
+
+func (fn SecretResolverFunc) ResolveSecret(ctx context.Context, ref SecretRef) (string, error) {
+	return fn(ctx, ref)
+}
+
+var secretResolvers = struct {
+	sync.RWMutex
+	resolvers map[string]SecretResolver
+}{resolvers: map[string]SecretResolver{}}
+
+// RegisterSecretResolver makes r responsible for secret references with the
+// given provider name, replacing any existing resolver for it.
+func RegisterSecretResolver(provider string, r SecretResolver) {
+	secretResolvers.Lock()
+	defer secretResolvers.Unlock()
+
+	secretResolvers.resolvers[provider] = r
+}
+
+func getSecretResolver(provider string) (SecretResolver, bool) {
+	secretResolvers.RLock()
+	defer secretResolvers.RUnlock()
+
+	r, ok := secretResolvers.resolvers[provider]
+	return r, ok
+}
+
+// ResolveSecrets sets every field of v that has a secret tag to the value
+// returned by the registered resolver for its provider, converted to the
+// field's type. v must be a pointer to a struct; nested structs are handled
+// too. Failures, including references to unregistered providers, are
+// reported as *FieldError values.
+func ResolveSecrets(ctx context.Context, v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ResolveSecrets: %w", err)
+	}
+
+	if err := resolveSecrets(ctx, rv, "", nil); err != nil {
+		return fmt.Errorf("reflectutil.ResolveSecrets: %w", err)
+	}
+
+	return nil
+}
+
+func resolveSecrets(ctx context.Context, rv reflect.Value, prefix string, resolved func(path string, ref SecretRef)) error {
+	return walkValue(rv, prefix, func(f *Field, v reflect.Value, path string) (bool, error) {
+		ref, ok, err := f.SecretRef()
+		if err != nil {
+			return false, &FieldError{Field: path, Err: err}
+		}
+		if !ok || !v.CanSet() {
+			return true, nil
+		}
+
+		r, ok := getSecretResolver(ref.Provider)
+		if !ok {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("no secret resolver registered for %q", ref.Provider)}
+		}
+
+		s, err := r.ResolveSecret(ctx, ref)
+		if err != nil {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("resolving %s: %w", ref, err)}
+		}
+
+		if err := setFromString(f, v, s); err != nil {
+			return false, &FieldError{Field: path, Err: err}
+		}
+
+		if resolved != nil {
+			resolved(path, ref)
+		}
+
+		return false, nil
+	})
+}
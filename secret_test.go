@@ -0,0 +1,109 @@
+package reflectutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type secretTestDatabase struct {
+	Password string `secret:"secrettest:db/prod#password"`
+}
+
+type secretTestConfig struct {
+	Token    string `secret:"secrettest:api"`
+	Port     int    `secret:"secrettest:db/prod#port"`
+	Database secretTestDatabase
+	Plain    string
+}
+
+func init() {
+	RegisterSecretResolver("secrettest", SecretResolverFunc(func(ctx context.Context, ref SecretRef) (string, error) {
+		switch ref.String() {
+		case "secrettest:api":
+			return "tok", nil
+		case "secrettest:db/prod#password":
+			return "hunter2", nil
+		case "secrettest:db/prod#port":
+			return "5432", nil
+		}
+
+		return "", errors.New("not found")
+	}))
+}
+
+func TestParseSecretRef(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		result SecretRef
+		error  string
+	}{
+		{"vault:db/prod#password", SecretRef{Provider: "vault", Path: "db/prod", Key: "password"}, ""},
+		{"env:API_TOKEN", SecretRef{Provider: "env", Path: "API_TOKEN"}, ""},
+		{"nope", SecretRef{}, "expected provider:path#key"},
+		{":path", SecretRef{}, "expected provider:path#key"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			a := assert.New(t)
+
+			r, err := ParseSecretRef(tc.input)
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+				a.Equal(tc.input, r.String())
+			}
+
+			a.Equal(tc.result, r)
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	a := assert.New(t)
+
+	var c secretTestConfig
+	if !a.NoError(ResolveSecrets(context.Background(), &c)) {
+		return
+	}
+
+	a.Equal(secretTestConfig{Token: "tok", Port: 5432, Database: secretTestDatabase{Password: "hunter2"}}, c)
+
+	d, err := GetDescription(c)
+	if a.NoError(err) {
+		a.True(d.Field("Token").Sensitive())
+		a.False(d.Field("Plain").Sensitive())
+	}
+
+	err = ResolveSecrets(context.Background(), &struct {
+		A string `secret:"missing:x"`
+		B string `secret:"secrettest:unknown"`
+	}{})
+	a.ErrorContains(err, `A: no secret resolver registered for "missing"`)
+	a.ErrorContains(err, "B: resolving secrettest:unknown: not found")
+}
+
+func TestConfigLoaderSecrets(t *testing.T) {
+	a := assert.New(t)
+
+	l := ConfigLoader{
+		File:      map[string]interface{}{"Token": "from-file", "Plain": "p"},
+		LookupEnv: func(string) (string, bool) { return "", false },
+	}
+
+	var c secretTestConfig
+	p, err := l.Load(&c)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("tok", c.Token)
+	a.Equal(Provenance{
+		"Token":             {Source: "secret", Key: "secrettest:api"},
+		"Port":              {Source: "secret", Key: "secrettest:db/prod#port"},
+		"Database.Password": {Source: "secret", Key: "secrettest:db/prod#password"},
+		"Plain":             {Source: "file", Key: "Plain"},
+	}, p)
+}
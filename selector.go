@@ -0,0 +1,218 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Criterion decides whether a field is selected by a FieldSelector.
+type Criterion func(f *Field) bool
+
+// HasTag selects fields with a tag called name.
+func HasTag(name string) Criterion {
+	return func(f *Field) bool { return f.tags.Has(name) }
+}
+
+// HasTagValue selects fields whose tag called name has the given value.
+func HasTagValue(name, value string) Criterion {
+	return func(f *Field) bool { return f.hasTagValue(name, value, false, false) }
+}
+
+// HasTagPrefix selects fields with a tag whose name starts with prefix.
+func HasTagPrefix(prefix string) Criterion {
+	return func(f *Field) bool {
+		for _, t := range f.tags {
+			if strings.HasPrefix(t.name, prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// HasParameter selects fields whose tag called name has the given parameter,
+// e.g. HasParameter("json", "omitempty").
+func HasParameter(name, parameter string) Criterion {
+	return func(f *Field) bool {
+		t := f.tags.Get(name)
+		return t != nil && t.parameters.Has(parameter)
+	}
+}
+
+// IsExported selects exported fields.
+func IsExported() Criterion {
+	return func(f *Field) bool { return f.Exported() }
+}
+
+// Not selects the fields that c doesn't.
+func Not(c Criterion) Criterion {
+	return func(f *Field) bool { return !c(f) }
+}
+
+// AnyOf selects the fields that any of criteria select.
+func AnyOf(criteria ...Criterion) Criterion {
+	return func(f *Field) bool {
+		for _, c := range criteria {
+			if c(f) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+var (
+	// ErrNoMatchingField is returned by FieldList.First and Single when no
+	// field matches.
+	ErrNoMatchingField = errors.New("no matching field")
+	// ErrMultipleMatchingFields is returned by FieldList.Single when more
+	// than one field matches.
+	ErrMultipleMatchingFields = errors.New("more than one matching field")
+)
+
+// First returns the first field that matches all of criteria.
+func (l FieldList) First(criteria ...Criterion) (*Field, error) {
+	for i := range l {
+		if matchesAll(&l[i], criteria) {
+			return &l[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("reflectutil.FieldList.First: %w", ErrNoMatchingField)
+}
+
+// Single returns the only field that matches all of criteria, failing if
+// there isn't exactly one, e.g. to find a struct's primary key with
+// l.Single(HasParameter("db", "pk")).
+func (l FieldList) Single(criteria ...Criterion) (*Field, error) {
+	var r *Field
+	var names []string
+
+	for i := range l {
+		if !matchesAll(&l[i], criteria) {
+			continue
+		}
+
+		if r == nil {
+			r = &l[i]
+		}
+
+		names = append(names, l[i].name)
+	}
+
+	switch len(names) {
+	case 0:
+		return nil, fmt.Errorf("reflectutil.FieldList.Single: %w", ErrNoMatchingField)
+	case 1:
+		return r, nil
+	default:
+		return nil, fmt.Errorf("reflectutil.FieldList.Single: %w: %s", ErrMultipleMatchingFields, strings.Join(names, ", "))
+	}
+}
+
+func matchesAll(f *Field, criteria []Criterion) bool {
+	for _, c := range criteria {
+		if !c(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FieldSelector picks out the fields that match every one of its criteria.
+// The criteria are evaluated once per struct type, and the matching positions
+// remembered, so applying a selector to a type it has seen before is just a
+// lookup. A FieldSelector is safe for concurrent use.
+type FieldSelector struct {
+	criteria []Criterion
+
+	compiled struct {
+		sync.RWMutex
+		m map[reflect.Type][]int
+	}
+}
+
+// CompileSelector returns a FieldSelector for the fields matching all of
+// criteria. With no criteria, every field is selected.
+func CompileSelector(criteria ...Criterion) *FieldSelector {
+	s := &FieldSelector{criteria: criteria}
+	s.compiled.m = make(map[reflect.Type][]int)
+	return s
+}
+
+// indexes returns the positions in d's FieldList of the selected fields.
+func (s *FieldSelector) indexes(d *StructDescription) []int {
+	s.compiled.RLock()
+	r, ok := s.compiled.m[d.typ]
+	s.compiled.RUnlock()
+
+	if ok {
+		return r
+	}
+
+	r = []int{}
+
+	for i := range d.fields {
+		if matchesAll(&d.fields[i], s.criteria) {
+			r = append(r, i)
+		}
+	}
+
+	s.compiled.Lock()
+	s.compiled.m[d.typ] = r
+	s.compiled.Unlock()
+
+	return r
+}
+
+// Apply returns the selected fields of d, in order.
+func (s *FieldSelector) Apply(d *StructDescription) FieldList {
+	indexes := s.indexes(d)
+
+	r := make(FieldList, len(indexes))
+	for i, n := range indexes {
+		r[i] = d.fields[n]
+	}
+
+	return r
+}
+
+// FieldValue pairs a field with its value in a particular struct.
+type FieldValue struct {
+	Field *Field
+	Value reflect.Value
+}
+
+// Values returns the selected fields of v, a struct or pointer to one, along
+// with their values. Fields promoted through nil embedded pointers are left
+// out. The values are settable if v is a pointer.
+func (s *FieldSelector) Values(v interface{}) ([]FieldValue, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.FieldSelector.Values: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.FieldSelector.Values: %w", err)
+	}
+
+	indexes := s.indexes(d)
+
+	r := make([]FieldValue, 0, len(indexes))
+	for _, n := range indexes {
+		f := &d.fields[n]
+
+		if fv, ok := fieldValue(rv, f.index); ok {
+			r = append(r, FieldValue{Field: f, Value: fv})
+		}
+	}
+
+	return r, nil
+}
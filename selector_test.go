@@ -0,0 +1,105 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type selectorTestBase struct {
+	ID string `db:"id" api:"public"`
+}
+
+type selectorTestUser struct {
+	*selectorTestBase
+	Name     string `db:"name" api:"public" json:"name,omitempty"`
+	Password string `db:"password" api:"internal"`
+	Age      int    `json:"age" swagger_min:"0"`
+	private  string `db:"private"`
+}
+
+func TestFieldSelector(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(selectorTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		name     string
+		criteria []Criterion
+		result   []string
+	}{
+		{"all", nil, []string{"selectorTestBase", "ID", "Name", "Password", "Age", "private"}},
+		{"tag", []Criterion{HasTag("db")}, []string{"ID", "Name", "Password", "private"}},
+		{"tag and exported", []Criterion{HasTag("db"), IsExported()}, []string{"ID", "Name", "Password"}},
+		{"value", []Criterion{HasTagValue("api", "public")}, []string{"ID", "Name"}},
+		{"not", []Criterion{HasTag("db"), Not(HasTagValue("api", "internal"))}, []string{"ID", "Name", "private"}},
+		{"prefix", []Criterion{HasTagPrefix("swagger_")}, []string{"Age"}},
+		{"parameter", []Criterion{HasParameter("json", "omitempty")}, []string{"Name"}},
+		{"any", []Criterion{AnyOf(HasTagValue("api", "internal"), HasTag("swagger_min"))}, []string{"Password", "Age"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := CompileSelector(tc.criteria...)
+			a := assert.New(t)
+			a.Equal(tc.result, s.Apply(d).Names())
+			a.Equal(tc.result, s.Apply(d).Names())
+		})
+	}
+}
+
+func TestFieldSelectorValues(t *testing.T) {
+	a := assert.New(t)
+
+	s := CompileSelector(HasTagValue("api", "public"))
+
+	u := selectorTestUser{Name: "Jo"}
+
+	values, err := s.Values(&u)
+	if !a.NoError(err) || !a.Len(values, 1) {
+		return
+	}
+
+	a.Equal("Name", values[0].Field.Name())
+	values[0].Value.SetString("Bo")
+	a.Equal("Bo", u.Name)
+
+	u.selectorTestBase = &selectorTestBase{ID: "1"}
+	values, err = s.Values(u)
+	if a.NoError(err) && a.Len(values, 2) {
+		a.Equal("1", values[0].Value.String())
+	}
+
+	_, err = s.Values(1)
+	a.Error(err)
+}
+
+func TestFieldListFirstAndSingle(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(selectorTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	f, err := d.Fields().First(HasTag("db"), IsExported())
+	if a.NoError(err) {
+		a.Equal("ID", f.Name())
+	}
+
+	_, err = d.Fields().First(HasTag("nope"))
+	a.ErrorIs(err, ErrNoMatchingField)
+
+	f, err = d.Fields().Single(HasTagValue("api", "internal"))
+	if a.NoError(err) {
+		a.Equal("Password", f.Name())
+	}
+
+	_, err = d.Fields().Single(HasTagValue("api", "public"))
+	a.ErrorIs(err, ErrMultipleMatchingFields)
+	a.ErrorContains(err, "ID, Name")
+
+	_, err = d.Fields().Single(HasTag("nope"))
+	a.ErrorIs(err, ErrNoMatchingField)
+}
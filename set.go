@@ -0,0 +1,548 @@
+package reflectutil
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// SetFields stores each of values in the matching field of v, which must be a
+// pointer to a struct. Keys are matched against the named tag (including
+// aliases), falling back to the field name for fields without that tag; with
+// an empty tag, only field names are used. Values are converted to the
+// fields' types, nested maps fill nested structs the same way, and nil
+// embedded pointers are allocated as needed. Keys that don't match a field go
+// to the extra field, if there is one (see Field.IsExtra), and are otherwise
+// ignored unless WithUnknownKeyPolicy says otherwise. Read only fields are
+// subject to WithReadOnlyPolicy. Every key that can't be set is reported,
+// each as a *FieldError, rather than stopping at the first.
+func SetFields(v interface{}, values map[string]interface{}, tag string, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.SetFields: %w", err)
+	}
+
+	if err := setFields(rv, values, tag, getOptions(opts)); err != nil {
+		return fmt.Errorf("reflectutil.SetFields: %w", err)
+	}
+
+	return nil
+}
+
+func setFields(rv reflect.Value, values map[string]interface{}, tag string, o *options) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	fromMap := func(m map[string]interface{}, v reflect.Value, o *options) error { return setFields(v, m, tag, o) }
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	extra := d.fields.ExtraField(tag)
+
+	lookup := func(k string) *Field { return d.fields.getByKey(tag, k) }
+	if o.foldKeys {
+		folded := d.fields.foldedKeys(tag, keys)
+		lookup = func(k string) *Field { return folded[k] }
+	}
+
+	var errs []error
+
+	for _, k := range keys {
+		f := lookup(k)
+		if f != nil && f.IsExtra(tag) {
+			f = nil
+		}
+		if f == nil && extra != nil {
+			if err := setExtra(extra, rv, k, values[k], fromMap, o.forKey(k, extra.name)); err != nil {
+				errs = append(errs, &FieldError{Field: k, Err: err})
+			}
+			continue
+		}
+		if f == nil {
+			if err := o.unknownKey(k); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if ok, err := o.readOnly(f); !ok {
+			if err != nil {
+				errs = append(errs, &FieldError{Field: k, Err: err})
+			}
+			continue
+		}
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = assignValue(f, fv, values[k], fromMap, o.forKey(k, f.name))
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: k, Err: err})
+			continue
+		}
+
+		o.record(f.name, k, "map")
+	}
+
+	return errors.Join(errs...)
+}
+
+// getByKey finds the field known as key in the given tag, or by its name if
+// tag is empty or the field's tag has no value (see keyedByName). Fields the
+// tag excludes with "-" are never found, so input can't reach them.
+func (l FieldList) getByKey(tag, key string) *Field {
+	if tag == "" {
+		return l.Get(key)
+	}
+
+	if f := l.GetByTagValue(tag, key); f != nil {
+		if f.excludedBy(tag) {
+			return nil
+		}
+
+		return f
+	}
+
+	if f := l.Get(key); f != nil && f.keyedByName(tag) {
+		return f
+	}
+
+	return nil
+}
+
+// excludedBy reports whether the field's tag is "-", opting it out of that
+// tag's encoding entirely.
+func (f *Field) excludedBy(tag string) bool {
+	t := f.tags.Get(tag)
+	return t != nil && t.value == "-"
+}
+
+// keyedByName reports whether the field is known by its name in the given
+// tag, because it either has no such tag or the tag has no value (e.g.
+// `json:",omitempty"`), as with EffectiveName.
+func (f *Field) keyedByName(tag string) bool {
+	t := f.tags.Get(tag)
+	return t == nil || t.value == ""
+}
+
+// setFromString parses s into v according to v's type, allocating pointers as
+// needed. f, if given, supplies field-level settings such as the time layout.
+func setFromString(f *Field, v reflect.Value, s string) error {
+	if ok, err := convertWithRegistered(v, reflect.ValueOf(s)); ok {
+		return err
+	}
+
+	if ok, err := setWrapped(v, func(u reflect.Value) error { return setFromString(f, u, s) }); ok {
+		return err
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return setFromString(f, v.Elem(), s)
+	}
+
+	if v.Type() == timeType {
+		layout := DefaultTimeLayout
+		if f != nil {
+			layout = f.TimeLayout()
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) && v.CanAddr() {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			return nil
+		}
+
+		items := SplitList(s, ",")
+		r := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setFromString(f, r.Index(i), item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(r)
+	default:
+		return fmt.Errorf("can't set a %s from a string", v.Type())
+	}
+
+	return nil
+}
+
+// formatString is the inverse of setFromString, rendering v in a form that
+// setFromString can parse back into the same type.
+func formatString(f *Field, v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+
+		return formatString(f, v.Elem())
+	}
+
+	if v.Type() == timeType {
+		layout := DefaultTimeLayout
+		if f != nil {
+			layout = f.TimeLayout()
+		}
+
+		return v.Interface().(time.Time).Format(layout), nil
+	}
+
+	if v.Type().Implements(textMarshalerType) && v.CanInterface() {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+
+		items := make([]string, v.Len())
+		for i := range items {
+			s, err := formatString(f, v.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("item %d: %w", i, err)
+			}
+			items[i] = strings.NewReplacer(`\`, `\\`, ",", `\,`).Replace(s)
+		}
+
+		return strings.Join(items, ","), nil
+	}
+
+	return "", fmt.Errorf("can't format a %s as a string", v.Type())
+}
+
+// structFromMapFunc fills the struct value v from m, using whichever key
+// convention the caller is working with.
+type structFromMapFunc func(m map[string]interface{}, v reflect.Value, o *options) error
+
+// assignValue stores src in v, converting between compatible representations
+// along the way: numbers of different types (as long as the value fits,
+// including json.Number), strings parsed with setFromString, []byte into
+// strings, []interface{} into slices and arrays, maps into maps or (with
+// fromMap) into structs, and anything into a registered Wrapper by way of its
+// underlying type. Pointers are allocated as needed. Every decoder and
+// setter in this package goes through here, so these are the package's
+// coercion rules. With WithStrictTypes, only the structural steps are taken.
+func assignValue(f *Field, v reflect.Value, src interface{}, fromMap structFromMapFunc, o *options) error {
+	if src == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+
+	if sv.Type().AssignableTo(v.Type()) {
+		v.Set(sv)
+		return nil
+	}
+
+	if ok, err := convertWithRegistered(v, sv); ok {
+		return err
+	}
+
+	if ok, err := setWrapped(v, func(u reflect.Value) error { return assignValue(f, u, src, fromMap, o) }); ok {
+		return err
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return assignValue(f, v.Elem(), src, fromMap, o)
+	}
+
+	if sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		if sv.IsNil() {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		return assignValue(f, v, sv.Elem().Interface(), fromMap, o)
+	}
+
+	coerce := o == nil || !o.strict
+
+	switch {
+	case coerce && sv.Type() == jsonNumberType && isNumberKind(v.Kind()):
+		return assignJSONNumber(v, json.Number(sv.String()))
+	case coerce && sv.Kind() == reflect.String && v.Kind() != reflect.String:
+		return setFromString(f, v, sv.String())
+	case coerce && sv.Kind() == reflect.Slice && sv.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.String:
+		v.SetString(string(sv.Bytes()))
+		return nil
+	case coerce && isNumberKind(sv.Kind()) && isNumberKind(v.Kind()):
+		return assignNumber(v, sv)
+	case coerce && sv.Kind() == reflect.Bool && v.Kind() == reflect.Bool:
+		v.SetBool(sv.Bool())
+		return nil
+	case (sv.Kind() == reflect.Slice || sv.Kind() == reflect.Array) && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		n := sv.Len()
+		r := v
+		if v.Kind() == reflect.Slice {
+			r = reflect.MakeSlice(v.Type(), n, n)
+		} else if n > v.Len() {
+			return fmt.Errorf("can't fit %d items into a %s", n, v.Type())
+		}
+
+		for i := 0; i < n; i++ {
+			if err := assignValue(f, r.Index(i), sv.Index(i).Interface(), fromMap, o); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+
+		v.Set(r)
+
+		return nil
+	case sv.Kind() == reflect.Map && v.Kind() == reflect.Map:
+		r := reflect.MakeMapWithSize(v.Type(), sv.Len())
+
+		iter := sv.MapRange()
+		for iter.Next() {
+			k := reflect.New(v.Type().Key()).Elem()
+			if err := assignValue(nil, k, iter.Key().Interface(), fromMap, o); err != nil {
+				return fmt.Errorf("key %v: %w", iter.Key().Interface(), err)
+			}
+
+			e := reflect.New(v.Type().Elem()).Elem()
+			if err := assignValue(nil, e, iter.Value().Interface(), fromMap, o); err != nil {
+				return fmt.Errorf("key %v: %w", iter.Key().Interface(), err)
+			}
+
+			r.SetMapIndex(k, e)
+		}
+
+		v.Set(r)
+
+		return nil
+	case sv.Kind() == reflect.Map && v.Kind() == reflect.Struct && fromMap != nil:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			if !sv.Type().ConvertibleTo(mapStringInterfaceType) {
+				return fmt.Errorf("can't assign a %s to a %s", sv.Type(), v.Type())
+			}
+			m = sv.Convert(mapStringInterfaceType).Interface().(map[string]interface{})
+		}
+
+		return fromMap(m, v, o)
+	case coerce && sv.Type().ConvertibleTo(v.Type()) && sv.Kind() == v.Kind():
+		v.Set(sv.Convert(v.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("can't assign a %s to a %s", sv.Type(), v.Type())
+}
+
+var (
+	mapStringInterfaceType = reflect.TypeOf(map[string]interface{}(nil))
+	jsonNumberType         = reflect.TypeOf(json.Number(""))
+)
+
+// Convert returns value converted to typ by the same rules the package's
+// decoders use. Maps are converted to structs by matching keys to field
+// names.
+func Convert(value interface{}, typ reflect.Type, opts ...Option) (interface{}, error) {
+	v := reflect.New(typ).Elem()
+
+	fromMap := func(m map[string]interface{}, v reflect.Value, o *options) error { return setFields(v, m, "", o) }
+
+	if err := assignValue(nil, v, value, fromMap, getOptions(opts)); err != nil {
+		return nil, fmt.Errorf("reflectutil.Convert: can't convert %T to %s: %w", value, typ, err)
+	}
+
+	return v.Interface(), nil
+}
+
+// assignJSONNumber stores n in the numeric value v, parsing it as an integer
+// if possible so large values don't lose precision on the way through a
+// float64.
+func assignJSONNumber(v reflect.Value, n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		return assignNumber(v, reflect.ValueOf(i))
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return assignNumber(v, reflect.ValueOf(u))
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+
+	return assignNumber(v, reflect.ValueOf(f))
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// assignNumber converts between numeric kinds, refusing conversions that would
+// lose information.
+func assignNumber(v, sv reflect.Value) error {
+	var (
+		f       float64
+		integer bool
+	)
+
+	switch {
+	case sv.CanInt():
+		n := sv.Int()
+		switch {
+		case v.CanInt():
+			if v.OverflowInt(n) {
+				return fmt.Errorf("%d overflows %s", n, v.Type())
+			}
+			v.SetInt(n)
+			return nil
+		case v.CanUint():
+			if n < 0 || v.OverflowUint(uint64(n)) {
+				return fmt.Errorf("%d overflows %s", n, v.Type())
+			}
+			v.SetUint(uint64(n))
+			return nil
+		}
+		f, integer = float64(n), true
+	case sv.CanUint():
+		n := sv.Uint()
+		switch {
+		case v.CanInt():
+			if n > 1<<63-1 || v.OverflowInt(int64(n)) {
+				return fmt.Errorf("%d overflows %s", n, v.Type())
+			}
+			v.SetInt(int64(n))
+			return nil
+		case v.CanUint():
+			if v.OverflowUint(n) {
+				return fmt.Errorf("%d overflows %s", n, v.Type())
+			}
+			v.SetUint(n)
+			return nil
+		}
+		f, integer = float64(n), true
+	default:
+		f = sv.Float()
+	}
+
+	switch {
+	case v.CanFloat():
+		if !integer && v.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %s", f, v.Type())
+		}
+		v.SetFloat(f)
+	case v.CanInt():
+		if f != float64(int64(f)) || v.OverflowInt(int64(f)) {
+			return fmt.Errorf("%v can't be represented by %s", f, v.Type())
+		}
+		v.SetInt(int64(f))
+	case v.CanUint():
+		if f < 0 || f != float64(uint64(f)) || v.OverflowUint(uint64(f)) {
+			return fmt.Errorf("%v can't be represented by %s", f, v.Type())
+		}
+		v.SetUint(uint64(f))
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFromString(t *testing.T) {
+	type Named string
+
+	for _, tc := range []struct {
+		name   string
+		input  string
+		target interface{}
+		result interface{}
+		error  string
+	}{
+		{"string", "x", new(string), "x", ""},
+		{"named string", "x", new(Named), Named("x"), ""},
+		{"bool", "true", new(bool), true, ""},
+		{"int", "-12", new(int), -12, ""},
+		{"hex int", "0x10", new(int64), int64(16), ""},
+		{"int overflow", "300", new(int8), int8(0), "out of range"},
+		{"uint", "12", new(uint16), uint16(12), ""},
+		{"float", "1.5", new(float64), 1.5, ""},
+		{"duration", "1m30s", new(time.Duration), 90 * time.Second, ""},
+		{"time", "2023-04-05T06:07:08Z", new(time.Time), time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC), ""},
+		{"text unmarshaler", "127.0.0.1", new(net.IP), net.IPv4(127, 0, 0, 1), ""},
+		{"bytes", "abc", new([]byte), []byte("abc"), ""},
+		{"slice", "1, 2,3", new([]int), []int{1, 2, 3}, ""},
+		{"bad slice", "1,x", new([]int), []int(nil), "item 1"},
+		{"pointer", "7", new(*int), func() *int { n := 7; return &n }(), ""},
+		{"unsupported", "x", new(map[string]string), map[string]string(nil), "can't set a map[string]string from a string"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			v := reflect.ValueOf(tc.target).Elem()
+
+			err := setFromString(nil, v, tc.input)
+
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal(tc.result, v.Interface())
+		})
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		input  interface{}
+		result string
+		error  string
+	}{
+		{"string", "x", "x", ""},
+		{"bool", true, "true", ""},
+		{"int", -12, "-12", ""},
+		{"uint", uint16(12), "12", ""},
+		{"float", 1.5, "1.5", ""},
+		{"duration", 90 * time.Second, "1m30s", ""},
+		{"time", time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC), "2023-04-05T06:07:08Z", ""},
+		{"text marshaler", net.IPv4(127, 0, 0, 1), "127.0.0.1", ""},
+		{"bytes", []byte("abc"), "abc", ""},
+		{"slice", []string{"a", "b,c"}, `a,b\,c`, ""},
+		{"nil pointer", (*int)(nil), "", ""},
+		{"unsupported", map[string]string{}, "", "can't format a map[string]string as a string"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			s, err := formatString(nil, reflect.ValueOf(tc.input))
+
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal(tc.result, s)
+		})
+	}
+}
+
+func TestAssignValue(t *testing.T) {
+	type Point struct{ X, Y int }
+
+	fromMap := func(m map[string]interface{}, v reflect.Value, o *options) error {
+		for k, e := range m {
+			if err := assignValue(nil, v.FieldByName(k), e, nil, o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	seven := 7
+
+	for _, tc := range []struct {
+		name   string
+		input  interface{}
+		target interface{}
+		result interface{}
+		error  string
+	}{
+		{"nil", nil, func() *string { s := "x"; return &s }(), "", ""},
+		{"assignable", "x", new(string), "x", ""},
+		{"interface target", 1, new(interface{}), 1, ""},
+		{"int32 to int", int32(5), new(int), 5, ""},
+		{"int to uint8", 200, new(uint8), uint8(200), ""},
+		{"int overflow", 300, new(uint8), uint8(0), "300 overflows uint8"},
+		{"negative to uint", -1, new(uint), uint(0), "-1 overflows uint"},
+		{"uint to int", uint64(3), new(int8), int8(3), ""},
+		{"float to int", 3.0, new(int), 3, ""},
+		{"fractional float to int", 3.5, new(int), 0, "3.5 can't be represented by int"},
+		{"int to float", 3, new(float32), float32(3), ""},
+		{"string to int", "12", new(int), 12, ""},
+		{"pointer source", &seven, new(int), 7, ""},
+		{"pointer target", int64(7), new(*int), &seven, ""},
+		{"slice", []interface{}{1, int64(2)}, new([]int), []int{1, 2}, ""},
+		{"array", []interface{}{1, 2}, new([2]int), [2]int{1, 2}, ""},
+		{"array too small", []interface{}{1, 2, 3}, new([2]int), [2]int{}, "can't fit 3 items"},
+		{"bad item", []interface{}{1, "x"}, new([]int), []int(nil), "item 1"},
+		{"map", map[string]interface{}{"a": int32(1)}, new(map[string]int), map[string]int{"a": 1}, ""},
+		{"map to struct", map[string]interface{}{"X": 1, "Y": int64(2)}, new(Point), Point{1, 2}, ""},
+		{"named conversion", time.Duration(5), new(int64), int64(5), ""},
+		{"json number to int", json.Number("12"), new(int), 12, ""},
+		{"json number float to int", json.Number("3.0"), new(int16), int16(3), ""},
+		{"large json number", json.Number("18446744073709551615"), new(uint64), uint64(18446744073709551615), ""},
+		{"fractional json number", json.Number("3.5"), new(int), 0, "3.5 can't be represented by int"},
+		{"json number to float", json.Number("1.5"), new(float64), 1.5, ""},
+		{"json number to string", json.Number("1.5"), new(string), "1.5", ""},
+		{"bytes to string", []byte("abc"), new(string), "abc", ""},
+		{"string to bytes", "abc", new([]byte), []byte("abc"), ""},
+		{"mismatch", true, new(int), 0, "can't assign a bool to a int"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			v := reflect.ValueOf(tc.target).Elem()
+
+			err := assignValue(nil, v, tc.input, fromMap, nil)
+
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal(tc.result, v.Interface())
+		})
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	a := assert.New(t)
+
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type User struct {
+		*Base
+		Name    string   `json:"name,alias:full_name"`
+		Age     uint8    `json:"age"`
+		Tags    []string `json:"tags"`
+		Address *Address `json:"address"`
+		Plain   string
+		Skipped string `json:"-"`
+	}
+
+	var u User
+	err := SetFields(&u, map[string]interface{}{
+		"id":        "12",
+		"full_name": "Jo",
+		"age":       30.0,
+		"tags":      []interface{}{"a", "b"},
+		"address":   map[string]interface{}{"city": "Perth"},
+		"Plain":     "p",
+	}, "json")
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(User{
+		Base:    &Base{ID: 12},
+		Name:    "Jo",
+		Age:     30,
+		Tags:    []string{"a", "b"},
+		Address: &Address{City: "Perth"},
+		Plain:   "p",
+	}, u)
+
+	err = SetFields(&u, map[string]interface{}{"Name": "x"}, "")
+	a.NoError(err)
+	a.Equal("x", u.Name)
+
+	err = SetFields(&u, map[string]interface{}{
+		"age":     300,
+		"name":    []int{1},
+		"missing": 1,
+		"Skipped": "x",
+		"address": map[string]interface{}{"city": 1},
+	}, "json", WithUnknownKeyPolicy(UnknownKeysError))
+	a.ErrorContains(err, "age: 300 overflows uint8")
+	a.ErrorContains(err, "name: can't assign a []int to a string")
+	a.ErrorContains(err, "missing: no such field")
+	a.ErrorContains(err, "Skipped: no such field")
+	a.ErrorContains(err, "address: city: can't assign a int to a string")
+
+	a.Error(SetFields(u, nil, "json"))
+}
+
+func TestSetFieldsExcluded(t *testing.T) {
+	a := assert.New(t)
+
+	type T struct {
+		Name   string `json:"name"`
+		Secret string `json:"-"`
+	}
+
+	var v T
+	a.NoError(SetFields(&v, map[string]interface{}{"-": "pwned", "Secret": "pwned"}, "json"))
+	a.Equal(T{}, v)
+
+	err := SetFields(&v, map[string]interface{}{"-": "pwned"}, "json", WithUnknownKeyPolicy(UnknownKeysError))
+	a.ErrorContains(err, "-: no such field")
+
+	for _, key := range []string{"-", "_", ""} {
+		a.NoError(SetFields(&v, map[string]interface{}{key: "pwned"}, "json", WithFoldedKeys()), key)
+		a.Equal(T{}, v, key)
+	}
+}
+
+func TestSetFieldsEmptyTagValue(t *testing.T) {
+	a := assert.New(t)
+
+	type T struct {
+		Name string `json:",omitempty"`
+	}
+
+	var v T
+	a.NoError(SetFields(&v, map[string]interface{}{"Name": "x"}, "json", WithUnknownKeyPolicy(UnknownKeysError)))
+	a.Equal("x", v.Name)
+
+	m, err := GetFields(v, "json")
+	if a.NoError(err) {
+		var r T
+		a.NoError(SetFields(&r, m, "json", WithUnknownKeyPolicy(UnknownKeysError)))
+		a.Equal(v, r)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	a := assert.New(t)
+
+	type Point struct{ X, Y int }
+
+	for _, tc := range []struct {
+		input  interface{}
+		typ    reflect.Type
+		result interface{}
+		error  string
+	}{
+		{"12", reflect.TypeOf(0), 12, ""},
+		{12, reflect.TypeOf(int8(0)), int8(12), ""},
+		{"1m", reflect.TypeOf(time.Duration(0)), time.Minute, ""},
+		{"true", reflect.TypeOf(false), true, ""},
+		{json.Number("7"), reflect.TypeOf(uint(0)), uint(7), ""},
+		{map[string]interface{}{"X": "1", "Y": 2}, reflect.TypeOf(Point{}), Point{1, 2}, ""},
+		{"x", reflect.TypeOf(0), nil, `reflectutil.Convert: can't convert string to int: strconv.ParseInt: parsing "x": invalid syntax`},
+		{map[string]interface{}{"Z": 1}, reflect.TypeOf(Point{}), Point{}, ""},
+	} {
+		r, err := Convert(tc.input, tc.typ)
+		if tc.error != "" {
+			a.ErrorContains(err, tc.error)
+		} else {
+			a.NoError(err)
+		}
+
+		a.Equal(tc.result, r)
+	}
+}
+
+func TestStrictTypes(t *testing.T) {
+	type Point struct{ X, Y int }
+	type Named int
+
+	for _, tc := range []struct {
+		name   string
+		input  interface{}
+		typ    reflect.Type
+		result interface{}
+		error  string
+	}{
+		{"exact", 12, reflect.TypeOf(0), 12, ""},
+		{"assignable interface", 12, reflect.TypeOf((*interface{})(nil)).Elem(), 12, ""},
+		{"pointer target", "x", reflect.TypeOf((*string)(nil)), func() *string { s := "x"; return &s }(), ""},
+		{"slice items", []interface{}{1, 2}, reflect.TypeOf([]int{}), []int{1, 2}, ""},
+		{"map to struct", map[string]interface{}{"X": 1}, reflect.TypeOf(Point{}), Point{X: 1}, ""},
+		{"string to int", "12", reflect.TypeOf(0), nil, "can't assign a string to a int"},
+		{"widening", int32(1), reflect.TypeOf(int64(0)), nil, "can't assign a int32 to a int64"},
+		{"named", 1, reflect.TypeOf(Named(0)), nil, "can't assign a int to a reflectutil.Named"},
+		{"json number", json.Number("1"), reflect.TypeOf(0), nil, "can't assign a json.Number to a int"},
+		{"bad item", []interface{}{1, "2"}, reflect.TypeOf([]int{}), nil, "item 1: can't assign a string to a int"},
+		{"bad struct field", map[string]interface{}{"X": 1.0}, reflect.TypeOf(Point{}), nil, "X: can't assign a float64 to a int"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			r, err := Convert(tc.input, tc.typ, WithStrictTypes())
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal(tc.result, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package reflectutil
+
+import (
+	"reflect"
+)
+
+// SetReason explains why a field can't be set on a particular value.
+type SetReason string
+
+const (
+	SetUnexported     SetReason = "field is unexported"
+	SetNotAddressable SetReason = "value is not addressable"
+	SetPassedByValue  SetReason = "struct was passed by value, not by pointer"
+	SetNilPointer     SetReason = "field is promoted through a nil pointer that can't be allocated"
+	SetNilStruct      SetReason = "struct pointer is nil"
+)
+
+// SetError is returned when a field can't be written. It's usually wrapped in
+// a *FieldError identifying the field.
+type SetError struct {
+	Reason SetReason
+}
+
+func (e *SetError) Error() string { return "can't set: " + string(e.Reason) }
+
+// CanSetOn reports whether the field can be written on v, which should be the
+// described struct or a pointer to it, and if not, why. Nil embedded struct
+// pointers along the way are fine as long as they could be allocated, since
+// the setters in this package do so.
+func (f *Field) CanSetOn(v reflect.Value) (bool, SetReason) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+		if v.Kind() == reflect.Struct {
+			return false, SetNotAddressable
+		}
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false, SetNilStruct
+		}
+
+		v = v.Elem()
+	} else if v.Kind() == reflect.Struct && !v.CanAddr() {
+		return false, SetPassedByValue
+	}
+
+	if _, reason := fieldForSet(f, v, false); reason != "" {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates nil
+// embedded struct pointers on the way to the field. It fails with a
+// *SetError if the field can't be set.
+func fieldByIndexAlloc(f *Field, v reflect.Value) (reflect.Value, error) {
+	fv, reason := fieldForSet(f, v, true)
+	if reason != "" {
+		return reflect.Value{}, &SetError{Reason: reason}
+	}
+
+	return fv, nil
+}
+
+// fieldForSet finds the field within v. Without alloc, nil pointers are
+// stepped over using a throwaway value so the rest of the path can still be
+// checked.
+func fieldForSet(f *Field, v reflect.Value, alloc bool) (reflect.Value, SetReason) {
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, SetNilPointer
+				}
+
+				if alloc {
+					v.Set(reflect.New(v.Type().Elem()))
+				} else {
+					v = reflect.New(v.Type().Elem())
+				}
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	if !v.CanSet() {
+		if !f.Exported() {
+			return reflect.Value{}, SetUnexported
+		}
+
+		return reflect.Value{}, SetNotAddressable
+	}
+
+	return v, ""
+}
@@ -0,0 +1,92 @@
+package reflectutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settableTestHidden struct {
+	Value string
+}
+
+func TestCanSetOn(t *testing.T) {
+	a := assert.New(t)
+
+	type Inner struct {
+		Name string
+	}
+
+	type settableTestOuter struct {
+		*Inner
+		*settableTestHidden
+		Direct string
+		hidden string
+	}
+
+	d, err := GetDescription(settableTestOuter{})
+	if !a.NoError(err) {
+		return
+	}
+
+	var nilOuter *settableTestOuter
+
+	for _, tc := range []struct {
+		name   string
+		field  string
+		value  reflect.Value
+		ok     bool
+		reason SetReason
+	}{
+		{"pointer", "Direct", reflect.ValueOf(&settableTestOuter{}), true, ""},
+		{"addressable struct", "Direct", reflect.ValueOf(&settableTestOuter{}).Elem(), true, ""},
+		{"by value", "Direct", reflect.ValueOf(settableTestOuter{}), false, SetPassedByValue},
+		{"interface", "Direct", reflect.ValueOf(&[]interface{}{settableTestOuter{}}).Elem().Index(0), false, SetNotAddressable},
+		{"nil struct", "Direct", reflect.ValueOf(nilOuter), false, SetNilStruct},
+		{"unexported", "hidden", reflect.ValueOf(&settableTestOuter{}), false, SetUnexported},
+		{"nil embedded", "Value", reflect.ValueOf(&settableTestOuter{}), false, SetNilPointer},
+		{"allocated embedded", "Value", reflect.ValueOf(&settableTestOuter{settableTestHidden: &settableTestHidden{}}), true, ""},
+		{"nil exported embedded", "Name", reflect.ValueOf(&settableTestOuter{}), true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			ok, reason := d.Field(tc.field).CanSetOn(tc.value)
+			a.Equal(tc.ok, ok)
+			a.Equal(tc.reason, reason)
+		})
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	a := assert.New(t)
+
+	var setError *SetError
+
+	err := FromRedisHash(map[string]string{}, struct{ A string }{})
+	if a.True(errors.As(err, &setError)) {
+		a.Equal(SetPassedByValue, setError.Reason)
+	}
+
+	type outer struct {
+		*settableTestHidden
+	}
+
+	l := ConfigLoader{LookupEnv: func(string) (string, bool) { return "", false }}
+
+	_, err = l.Load(&outer{})
+	a.NoError(err)
+
+	l.File = map[string]interface{}{"Value": "x"}
+
+	var fieldError *FieldError
+	_, err = l.Load(&outer{})
+	if a.True(errors.As(err, &fieldError)) {
+		a.Equal("Value", fieldError.Field)
+	}
+	if a.True(errors.As(err, &setError)) {
+		a.Equal(SetNilPointer, setError.Reason)
+	}
+}
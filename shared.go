@@ -0,0 +1,101 @@
+package reflectutil
+
+import (
+	"reflect"
+	"sync"
+)
+
+// sharedDescriptionOptions holds the options that affect how a nested
+// description is built. Descriptions built with equal options are the same,
+// so they can be shared between every parent that reaches them.
+type sharedDescriptionOptions struct {
+	maxDepth                 int
+	duplicateParameterPolicy DuplicateParameterPolicy
+	embeddedTagPolicy        EmbeddedTagPolicy
+	spans                    bool
+}
+
+type sharedDescriptionKey struct {
+	typ     reflect.Type
+	name    string
+	depth   int
+	options sharedDescriptionOptions
+}
+
+var sharedDescriptions = struct {
+	sync.RWMutex
+	descriptions map[sharedDescriptionKey]*StructDescription
+}{descriptions: make(map[sharedDescriptionKey]*StructDescription)}
+
+// sharedDescriptionKey returns the key to share the nested description of
+// typ, built at depth, under. Descriptions built with type filters or an
+// arena aren't shared.
+func (ctx *describeContext) sharedDescriptionKey(typ reflect.Type, name string, depth int) (sharedDescriptionKey, bool) {
+	o := ctx.options
+	if o.noCache || o.allowedTypes != nil || o.deniedTypes != nil || o.arena != nil {
+		return sharedDescriptionKey{}, false
+	}
+
+	if o.maxDepth < 0 {
+		depth = -1
+	}
+
+	return sharedDescriptionKey{
+		typ:   typ,
+		name:  name,
+		depth: depth,
+		options: sharedDescriptionOptions{
+			maxDepth:                 o.maxDepth,
+			duplicateParameterPolicy: o.duplicateParameterPolicy,
+			embeddedTagPolicy:        o.embeddedTagPolicy,
+			spans:                    o.spans,
+		},
+	}, true
+}
+
+// getNestedDescription describes the struct type of a field, reusing the
+// description already built for the same type with the same options by any
+// other parent. Descriptions that refer back to a description still being
+// built (as recursive types do) depend on where they were reached from, so
+// they're never shared.
+func getNestedDescription(typ reflect.Type, name string, ctx *describeContext, depth int) (*StructDescription, error) {
+	key, ok := ctx.sharedDescriptionKey(typ, name, depth)
+	if ok {
+		sharedDescriptions.RLock()
+		d, found := sharedDescriptions.descriptions[key]
+		sharedDescriptions.RUnlock()
+
+		if found {
+			return d, nil
+		}
+	}
+
+	refs := ctx.inProgressRefs
+
+	d, err := getDescriptionWithContext(typ, name, ctx, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok || ctx.inProgressRefs != refs {
+		return d, nil
+	}
+
+	sharedDescriptions.Lock()
+	defer sharedDescriptions.Unlock()
+
+	if existing, found := sharedDescriptions.descriptions[key]; found {
+		return existing, nil
+	}
+
+	sharedDescriptions.descriptions[key] = d
+
+	return d, nil
+}
+
+func clearSharedDescriptions() {
+	sharedDescriptions.Lock()
+	defer sharedDescriptions.Unlock()
+
+	sharedDescriptions.descriptions = make(map[sharedDescriptionKey]*StructDescription)
+}
@@ -0,0 +1,93 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sharedTestTimestamps struct {
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
+type sharedTestAudit struct {
+	By    string               `json:"by"`
+	Times sharedTestTimestamps `json:"times"`
+}
+
+type sharedTestUser struct {
+	Audit  sharedTestAudit  `json:"audit"`
+	Backup *sharedTestAudit `json:"backup"`
+}
+
+type sharedTestGroup struct {
+	Audit sharedTestAudit `json:"audit"`
+	Meta  struct {
+		Times sharedTestTimestamps
+	}
+}
+
+type sharedTestNode struct {
+	Audit sharedTestAudit `json:"audit"`
+	Next  *sharedTestNode `json:"next"`
+}
+
+func TestSharedNestedDescriptions(t *testing.T) {
+	a := assert.New(t)
+
+	ClearDescriptionCache()
+
+	u, err := GetDescription(sharedTestUser{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	ResetStats()
+
+	g, err := GetDescription(sharedTestGroup{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	audit := u.Field("Audit").Description()
+	a.True(audit == u.Field("Backup").Description())
+	a.True(audit == g.Field("Audit").Description())
+	a.True(audit.Field("Times").Description() == g.Field("Meta").Description().Field("Times").Description())
+	a.Equal(uint64(2), GetStats().DescriptionsBuilt)
+
+	n, err := GetDescription(sharedTestNode{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+	a.True(audit == n.Field("Audit").Description())
+	a.True(n == n.Field("Next").Description())
+
+	limited, err := GetDescription(sharedTestGroup{}, WithNestedDescriptions(1))
+	if !a.NoError(err) {
+		return
+	}
+	a.False(audit == limited.Field("Audit").Description())
+	a.Nil(limited.Field("Audit").Description().Field("Times").Description())
+
+	filtered, err := GetDescription(sharedTestGroup{}, WithNestedDescriptions(-1), WithDeniedTypes(reflect.TypeOf(sharedTestTimestamps{})))
+	if !a.NoError(err) {
+		return
+	}
+	a.False(audit == filtered.Field("Audit").Description())
+
+	uncached, err := GetDescription(sharedTestGroup{}, WithNestedDescriptions(-1), WithoutCache())
+	if !a.NoError(err) {
+		return
+	}
+	a.False(audit == uncached.Field("Audit").Description())
+
+	ClearDescriptionCache()
+
+	g, err = GetDescription(sharedTestGroup{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+	a.False(audit == g.Field("Audit").Description())
+}
@@ -0,0 +1,147 @@
+package reflectutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Size returns the size parameter of the field's tag called tag, e.g. 255 for
+// `db:"name,size:255"`: the maximum length in characters of a string, or in
+// items of a slice, array or map.
+func (f *Field) Size(tag string) (int, bool, error) {
+	return f.sizeParameter(tag, "Size", "size")
+}
+
+// Precision returns the precision parameter of the field's tag called tag,
+// the total number of significant digits a number may have, e.g. 10 for
+// `db:"price,precision:10,scale:2"`.
+func (f *Field) Precision(tag string) (int, bool, error) {
+	return f.sizeParameter(tag, "Precision", "precision")
+}
+
+// Scale returns the scale parameter of the field's tag called tag, the number
+// of those digits that may follow the decimal point.
+func (f *Field) Scale(tag string) (int, bool, error) {
+	return f.sizeParameter(tag, "Scale", "scale")
+}
+
+func (f *Field) sizeParameter(tag, method, name string) (int, bool, error) {
+	t := f.tags.Get(tag)
+	if t == nil {
+		return 0, false, nil
+	}
+
+	p := t.parameters.Get(name)
+	if p == nil {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(p.Value())
+	if err != nil || n < 0 {
+		return 0, false, fmt.Errorf("reflectutil.Field.%s: invalid %s %q for field %s", method, name, p.Value(), f.name)
+	}
+
+	return n, true, nil
+}
+
+// CheckSizes reports every field of v, a struct or pointer to one, whose
+// value doesn't fit the size, precision or scale given by its tag called tag.
+// Nested structs are checked too. Each problem is reported as a *FieldError.
+func CheckSizes(v interface{}, tag string) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.CheckSizes: %w", err)
+	}
+
+	err = walkValue(rv, "", func(f *Field, v reflect.Value, path string) (bool, error) {
+		if err := checkSize(f, v, tag); err != nil {
+			return true, &FieldError{Field: path, Err: err}
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("reflectutil.CheckSizes: %w", err)
+	}
+
+	return nil
+}
+
+func checkSize(f *Field, v reflect.Value, tag string) error {
+	size, hasSize, err := f.Size(tag)
+	if err != nil {
+		return err
+	}
+
+	precision, hasPrecision, err := f.Precision(tag)
+	if err != nil {
+		return err
+	}
+
+	scale, hasScale, err := f.Scale(tag)
+	if err != nil {
+		return err
+	}
+
+	if hasPrecision && hasScale && scale > precision {
+		return fmt.Errorf("scale %d is larger than precision %d", scale, precision)
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if hasSize {
+		n := -1
+		switch v.Kind() {
+		case reflect.String:
+			n = utf8.RuneCountInString(v.String())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			n = v.Len()
+		}
+
+		if n > size {
+			return fmt.Errorf("length %d is over the size limit of %d", n, size)
+		}
+	}
+
+	if !hasPrecision && !hasScale {
+		return nil
+	}
+
+	var s string
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if math.IsInf(v.Float(), 0) || math.IsNaN(v.Float()) {
+			return fmt.Errorf("%v can't be stored with a fixed precision", v.Float())
+		}
+		s = strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	default:
+		return nil
+	}
+
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	whole = strings.TrimLeft(whole, "0")
+
+	if hasScale && len(fraction) > scale {
+		return fmt.Errorf("%s has more than %d digits after the decimal point", s, scale)
+	}
+
+	if hasPrecision && len(whole) > precision-scale {
+		return fmt.Errorf("%s has more than %d digits before the decimal point", s, precision-scale)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sizesTestItem struct {
+	SKU string `db:"sku,size:4"`
+}
+
+type sizesTestOrder struct {
+	Name     string          `db:"name,size:5"`
+	Tags     []string        `db:"tags,size:2"`
+	Price    float64         `db:"price,precision:5,scale:2"`
+	Quantity int             `db:"quantity,precision:3"`
+	Note     *string         `db:"note,size:3"`
+	Item     sizesTestItem   `db:"item"`
+	Items    []sizesTestItem `db:"items"`
+	Free     string
+}
+
+func TestSizeParameters(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(sizesTestOrder{})
+	if !a.NoError(err) {
+		return
+	}
+
+	n, ok, err := d.Field("Name").Size("db")
+	a.Equal([]interface{}{5, true, nil}, []interface{}{n, ok, err})
+
+	n, ok, err = d.Field("Price").Precision("db")
+	a.Equal([]interface{}{5, true, nil}, []interface{}{n, ok, err})
+
+	n, ok, err = d.Field("Price").Scale("db")
+	a.Equal([]interface{}{2, true, nil}, []interface{}{n, ok, err})
+
+	_, ok, err = d.Field("Free").Size("db")
+	a.False(ok)
+	a.NoError(err)
+
+	_, ok, err = d.Field("Name").Precision("db")
+	a.False(ok)
+	a.NoError(err)
+
+	bad, err := GetDescription(struct {
+		A string `db:"a,size:big"`
+	}{})
+	if a.NoError(err) {
+		_, _, err = bad.Field("A").Size("db")
+		a.EqualError(err, `reflectutil.Field.Size: invalid size "big" for field A`)
+	}
+}
+
+func TestCheckSizes(t *testing.T) {
+	a := assert.New(t)
+
+	note := "ok"
+	a.NoError(CheckSizes(&sizesTestOrder{
+		Name:     "héllo",
+		Tags:     []string{"a", "b"},
+		Price:    -123.45,
+		Quantity: 999,
+		Note:     &note,
+		Item:     sizesTestItem{SKU: "ab"},
+	}, "db"))
+
+	note = "long"
+	err := CheckSizes(sizesTestOrder{
+		Name:     "toolong",
+		Tags:     []string{"a", "b", "c"},
+		Price:    1234.5,
+		Quantity: 1000,
+		Note:     &note,
+		Item:     sizesTestItem{SKU: "abcde"},
+		Items:    []sizesTestItem{{SKU: "ok"}, {SKU: "toolong"}},
+	}, "db")
+	a.ErrorContains(err, "Name: length 7 is over the size limit of 5")
+	a.ErrorContains(err, "Tags: length 3 is over the size limit of 2")
+	a.ErrorContains(err, "Price: 1234.5 has more than 3 digits before the decimal point")
+	a.ErrorContains(err, "Quantity: 1000 has more than 3 digits before the decimal point")
+	a.ErrorContains(err, "Note: length 4 is over the size limit of 3")
+	a.ErrorContains(err, "Item.SKU: length 5 is over the size limit of 4")
+	a.ErrorContains(err, "Items[1].SKU: length 7 is over the size limit of 4")
+
+	err = CheckSizes(sizesTestOrder{Price: 1.234}, "db")
+	a.ErrorContains(err, "Price: 1.234 has more than 2 digits after the decimal point")
+
+	err = CheckSizes(struct {
+		A float64 `db:"a,precision:2,scale:3"`
+	}{}, "db")
+	a.ErrorContains(err, "A: scale 3 is larger than precision 2")
+}
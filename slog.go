@@ -0,0 +1,112 @@
+//go:build go1.21
+
+package reflectutil
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+var logValuerType = reflect.TypeOf((*slog.LogValuer)(nil)).Elem()
+
+// LogValue renders v, a struct or pointer to one, as a slog group of its
+// exported fields. Fields are named by their log tag (or the field name), a
+// log tag of "-" leaves a field out, as does an omitempty parameter when the
+// field is empty, and sensitive fields are logged as RedactionMask. Nested
+// structs become nested groups. If v can't be described, the result is a
+// string starting with "!ERROR:".
+func LogValue(v interface{}) slog.Value {
+	rv, err := structValue(v)
+	if err == nil {
+		var attrs []slog.Attr
+		if attrs, err = logAttrs(rv); err == nil {
+			return slog.GroupValue(attrs...)
+		}
+	}
+
+	return slog.StringValue(fmt.Sprintf("!ERROR:reflectutil.LogValue: %v", err))
+}
+
+// Loggable wraps v so that it is rendered with LogValue when logged, e.g.
+// slog.Any("user", reflectutil.Loggable(u)).
+func Loggable(v interface{}) slog.LogValuer { return loggable{v} }
+
+type loggable struct{ v interface{} }
+
+func (l loggable) LogValue() slog.Value { return LogValue(l.v) }
+
+func logAttrs(rv reflect.Value) ([]slog.Attr, error) {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var attrs []slog.Attr
+
+	for i := range d.fields {
+		f := &d.fields[i]
+		if !f.Exported() || (f.embedded && derefType(f.typ).Kind() == reflect.Struct) {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok {
+			continue
+		}
+
+		key := f.name
+		if t := f.tags.Get("log"); t != nil {
+			if t.value == "-" || (t.parameters.Has("omitempty") && fv.IsZero()) {
+				continue
+			}
+			if t.value != "" {
+				key = t.value
+			}
+		}
+
+		if f.Sensitive() {
+			attrs = append(attrs, slog.String(key, RedactionMask))
+			continue
+		}
+
+		v, err := logValue(f, fv)
+		if err != nil {
+			return nil, &FieldError{Field: f.name, Err: err}
+		}
+
+		attrs = append(attrs, slog.Attr{Key: key, Value: v})
+	}
+
+	return attrs, nil
+}
+
+func logValue(f *Field, v reflect.Value) (slog.Value, error) {
+	if v.Type().Implements(logValuerType) && v.CanInterface() {
+		return slog.AnyValue(v.Interface()), nil
+	}
+
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return slog.AnyValue(nil), nil
+		}
+
+		return logValue(f, v.Elem())
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return slog.AnyValue(v.Interface()), nil
+	case v.Type() == durationType:
+		return slog.DurationValue(time.Duration(v.Int())), nil
+	case isConfigSection(v):
+		attrs, err := logAttrs(v)
+		if err != nil {
+			return slog.Value{}, err
+		}
+		return slog.GroupValue(attrs...), nil
+	}
+
+	return slog.AnyValue(attributeValue(f, v)), nil
+}
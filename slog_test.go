@@ -0,0 +1,57 @@
+//go:build go1.21
+
+package reflectutil
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slogTestAddress struct {
+	City string `log:"city"`
+}
+
+type slogTestUser struct {
+	ID       int              `log:"id"`
+	Password string           `log:"password" sensitive:"true"`
+	Email    string           `json:"email,redact"`
+	Timeout  time.Duration    `log:"timeout"`
+	Address  *slogTestAddress `log:"address"`
+	Nickname string           `log:"nickname,omitempty"`
+	Internal string           `log:"-"`
+	Tags     []string
+}
+
+func TestLogValue(t *testing.T) {
+	a := assert.New(t)
+
+	u := slogTestUser{
+		ID:       7,
+		Password: "hunter2",
+		Email:    "jo@example.com",
+		Timeout:  time.Second,
+		Address:  &slogTestAddress{City: "Perth"},
+		Internal: "x",
+		Tags:     []string{"a", "b"},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("hi", "user", Loggable(&u))
+
+	a.Equal(`level=INFO msg=hi user.id=7 user.password=*** user.Email=*** user.timeout=1s user.address.city=Perth user.Tags="[a b]"`+"\n", buf.String())
+
+	a.Equal(slog.KindGroup, LogValue(u).Kind())
+	a.Contains(LogValue(1).String(), "!ERROR:")
+}
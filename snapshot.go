@@ -0,0 +1,73 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarshalText renders the description as deterministic, line oriented text
+// meant for golden files: the type, then each field with its type, index and
+// tags, and each tag with its parameters, one per line. Nested descriptions
+// (see WithNestedDescriptions) are rendered inline under their field, except
+// for embedded fields, whose promoted fields are already listed. A
+// description that refers back to one being rendered is marked recursive
+// rather than repeated.
+//
+//	type reflectutil.user
+//	  field Name string index=0
+//	    tag json "name"
+//	      param omitempty
+//
+// Any change to a field's name, type, position or tags changes the output.
+func (s *StructDescription) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "type %s\n", s.typ)
+	s.marshalText(&b, "  ", map[*StructDescription]bool{s: true})
+	return []byte(b.String()), nil
+}
+
+func (s *StructDescription) marshalText(b *strings.Builder, indent string, seen map[*StructDescription]bool) {
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		fmt.Fprintf(b, "%sfield %s %s index=%s", indent, f.name, f.typ, formatIndex(f.index))
+		if f.embedded {
+			b.WriteString(" embedded")
+		}
+		b.WriteString("\n")
+
+		for _, t := range f.tags {
+			fmt.Fprintf(b, "%s  tag %s %s\n", indent, t.name, strconv.Quote(t.value))
+
+			for _, p := range t.parameters {
+				if p.value == "" {
+					fmt.Fprintf(b, "%s    param %s\n", indent, p.name)
+				} else {
+					fmt.Fprintf(b, "%s    param %s %s\n", indent, p.name, strconv.Quote(p.value))
+				}
+			}
+		}
+
+		if f.description != nil && !f.embedded {
+			if seen[f.description] {
+				fmt.Fprintf(b, "%s  type %s recursive\n", indent, f.description.typ)
+				continue
+			}
+
+			fmt.Fprintf(b, "%s  type %s\n", indent, f.description.typ)
+			seen[f.description] = true
+			f.description.marshalText(b, indent+"    ", seen)
+			delete(seen, f.description)
+		}
+	}
+}
+
+func formatIndex(index []int) string {
+	r := make([]string, len(index))
+	for i, n := range index {
+		r[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(r, ".")
+}
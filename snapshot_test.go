@@ -0,0 +1,54 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapshotTestAddress struct {
+	City string `json:"city"`
+}
+
+type snapshotTestBase struct {
+	ID string `json:"id"`
+}
+
+type snapshotTestUser struct {
+	snapshotTestBase
+	Name    string               `json:"name,omitempty" validate:"min:3"`
+	Address *snapshotTestAddress `json:"address"`
+	Manager *snapshotTestUser    `json:"manager"`
+}
+
+func TestMarshalText(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(snapshotTestUser{}, WithNestedDescriptions(-1))
+	if !a.NoError(err) {
+		return
+	}
+
+	b, err := d.MarshalText()
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(`type reflectutil.snapshotTestUser
+  field snapshotTestBase reflectutil.snapshotTestBase index=0 embedded
+  field ID string index=0.0
+    tag json "id"
+  field Name string index=1
+    tag json "name"
+      param omitempty
+    tag validate "min:3"
+  field Address *reflectutil.snapshotTestAddress index=2
+    tag json "address"
+    type reflectutil.snapshotTestAddress
+      field City string index=0
+        tag json "city"
+  field Manager *reflectutil.snapshotTestUser index=3
+    tag json "manager"
+    type reflectutil.snapshotTestUser recursive
+`, string(b))
+}
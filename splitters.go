@@ -0,0 +1,50 @@
+package reflectutil
+
+import (
+	"sync"
+)
+
+var valueSeparators = struct {
+	sync.RWMutex
+	seps map[string]string
+}{seps: map[string]string{}}
+
+// RegisterValueSeparator declares that the values of tags called name are
+// lists separated by sep, e.g. RegisterValueSeparator("binding", "|") for
+// `binding:"required|email"`, so Tag.Values splits them. An empty sep makes
+// the tag's values scalar again.
+func RegisterValueSeparator(name, sep string) {
+	valueSeparators.Lock()
+	defer valueSeparators.Unlock()
+
+	if sep == "" {
+		delete(valueSeparators.seps, name)
+	} else {
+		valueSeparators.seps[name] = sep
+	}
+}
+
+// ValueSeparator returns the separator registered for tags called name.
+func ValueSeparator(name string) (string, bool) {
+	valueSeparators.RLock()
+	defer valueSeparators.RUnlock()
+
+	sep, ok := valueSeparators.seps[name]
+	return sep, ok
+}
+
+// Values returns the tag's value split on the separator registered for its
+// name with RegisterValueSeparator, following the same rules as ValueList.
+// Tags without a registered separator have a single value, or none if it's
+// empty.
+func (t *Tag) Values() []string {
+	if sep, ok := ValueSeparator(t.name); ok {
+		return t.ValueList(sep)
+	}
+
+	if t.value == "" {
+		return []string{}
+	}
+
+	return []string{t.value}
+}
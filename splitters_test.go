@@ -0,0 +1,40 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueSeparators(t *testing.T) {
+	a := assert.New(t)
+
+	RegisterValueSeparator("splittersTestPipe", "|")
+	RegisterValueSeparator("splittersTestSpace", " ")
+	defer RegisterValueSeparator("splittersTestPipe", "")
+	defer RegisterValueSeparator("splittersTestSpace", "")
+
+	d, err := GetDescription(struct {
+		A string `splittersTestPipe:"required | email,p" splittersTestSpace:"read  write" other:"a|b"`
+		B string `splittersTestPipe:"" other:""`
+	}{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"required", "email"}, d.Field("A").Tag("splittersTestPipe").Values())
+	a.Equal([]string{"read", "write"}, d.Field("A").Tag("splittersTestSpace").Values())
+	a.Equal([]string{"a|b"}, d.Field("A").Tag("other").Values())
+	a.Equal([]string{}, d.Field("B").Tag("splittersTestPipe").Values())
+	a.Equal([]string{}, d.Field("B").Tag("other").Values())
+
+	sep, ok := ValueSeparator("splittersTestPipe")
+	a.True(ok)
+	a.Equal("|", sep)
+
+	RegisterValueSeparator("splittersTestPipe", "")
+
+	_, ok = ValueSeparator("splittersTestPipe")
+	a.False(ok)
+	a.Equal([]string{"required | email"}, d.Field("A").Tag("splittersTestPipe").Values())
+}
@@ -0,0 +1,58 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+var stats struct {
+	descriptionsBuilt atomic.Uint64
+	tagsParsed        atomic.Uint64
+	parseErrors       atomic.Uint64
+	cacheHits         atomic.Uint64
+	cacheMisses       atomic.Uint64
+}
+
+// Stats is a snapshot of the package-wide counters. Its String method renders
+// it as JSON, so it can be published with expvar.Func or used as an expvar.Var
+// directly.
+type Stats struct {
+	DescriptionsBuilt uint64 `json:"descriptionsBuilt"`
+	TagsParsed        uint64 `json:"tagsParsed"`
+	ParseErrors       uint64 `json:"parseErrors"`
+	CacheHits         uint64 `json:"cacheHits"`
+	CacheMisses       uint64 `json:"cacheMisses"`
+}
+
+func (s Stats) String() string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+func GetStats() Stats {
+	return Stats{
+		DescriptionsBuilt: stats.descriptionsBuilt.Load(),
+		TagsParsed:        stats.tagsParsed.Load(),
+		ParseErrors:       stats.parseErrors.Load(),
+		CacheHits:         stats.cacheHits.Load(),
+		CacheMisses:       stats.cacheMisses.Load(),
+	}
+}
+
+func ResetStats() {
+	stats.descriptionsBuilt.Store(0)
+	stats.tagsParsed.Store(0)
+	stats.parseErrors.Store(0)
+	stats.cacheHits.Store(0)
+	stats.cacheMisses.Store(0)
+}
+
+// StatsVar satisfies expvar.Var, reporting the current counters every time it
+// is read - e.g. expvar.Publish("reflectutil", reflectutil.StatsVar{}).
+type StatsVar struct{}
+
+func (StatsVar) String() string { return GetStats().String() }
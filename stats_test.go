@@ -0,0 +1,40 @@
+package reflectutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		A string `a:"x" b:"y,p"`
+		B string `c:"z"`
+	}
+
+	ClearDescriptionCache()
+	ResetStats()
+
+	_, err := GetDescription(S{})
+	a.NoError(err)
+
+	_, err = GetDescription(&S{})
+	a.NoError(err)
+
+	_, err = ParseTagList(`k:"x`)
+	a.Error(err)
+
+	a.Equal(Stats{DescriptionsBuilt: 1, TagsParsed: 3, ParseErrors: 1, CacheHits: 1, CacheMisses: 1}, GetStats())
+
+	var decoded Stats
+	if a.NoError(json.Unmarshal([]byte(StatsVar{}.String()), &decoded)) {
+		a.Equal(GetStats(), decoded)
+	}
+
+	ResetStats()
+
+	a.Equal(Stats{}, GetStats())
+}
@@ -0,0 +1,81 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StructFields returns the description's fields as reflect.StructFields, in
+// the same order and with the same indexes as reflect.VisibleFields, so
+// promoted fields are included. Tags are regenerated from the parsed tags
+// rather than copied, so they're always well formed.
+func (s *StructDescription) StructFields() []reflect.StructField {
+	r := make([]reflect.StructField, len(s.fields))
+
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		r[i] = reflect.StructField{
+			Name:      f.name,
+			Type:      f.typ,
+			Tag:       f.tags.StructTag(),
+			Index:     append([]int(nil), f.index...),
+			Anonymous: f.embedded,
+		}
+
+		if !f.Exported() && f.owner != nil {
+			r[i].PkgPath = f.owner.PkgPath()
+		}
+	}
+
+	return r
+}
+
+// StructTag formats the list back into struct tag syntax, e.g.
+// `json:"name,omitempty" db:"name"`.
+func (l TagList) StructTag() reflect.StructTag {
+	var b strings.Builder
+
+	for i := range l {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(l[i].name)
+		b.WriteByte(':')
+		b.WriteString(strconv.Quote(l[i].rawValue()))
+	}
+
+	return reflect.StructTag(b.String())
+}
+
+// DescribeStructFields builds a description from fields produced elsewhere,
+// e.g. by reflect.VisibleFields or a code generator. Fields without an Index
+// are given their position in fs. The description has no name or type.
+func DescribeStructFields(fs []reflect.StructField, opts ...Option) (*StructDescription, error) {
+	specs := make([]FieldSpec, len(fs))
+
+	for i, sf := range fs {
+		index := sf.Index
+		if len(index) == 0 {
+			index = []int{i}
+		}
+
+		specs[i] = FieldSpec{
+			Name:     sf.Name,
+			Index:    index,
+			Type:     sf.Type,
+			Tag:      sf.Tag,
+			Embedded: sf.Anonymous,
+		}
+	}
+
+	d, err := NewStructDescription("", nil, specs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.DescribeStructFields: %w", err)
+	}
+
+	return d, nil
+}
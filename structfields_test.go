@@ -0,0 +1,80 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structFieldsTestBase struct {
+	ID int `db:"id"`
+}
+
+type structFieldsTestUser struct {
+	structFieldsTestBase
+	Name  string `json:"name,omitempty" db:"name"`
+	Email string `json:"email"  db:"email,unique"`
+	notes string
+}
+
+func TestStructFields(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(structFieldsTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	fs := d.StructFields()
+	expected := reflect.VisibleFields(reflect.TypeOf(structFieldsTestUser{}))
+
+	if !a.Len(fs, len(expected)) {
+		return
+	}
+
+	for i := range fs {
+		a.Equal(expected[i].Name, fs[i].Name)
+		a.Equal(expected[i].Type, fs[i].Type)
+		a.Equal(expected[i].Index, fs[i].Index)
+		a.Equal(expected[i].Anonymous, fs[i].Anonymous)
+		a.Equal(expected[i].PkgPath, fs[i].PkgPath)
+	}
+
+	a.Equal(reflect.StructTag(`json:"email" db:"email,unique"`), fs[3].Tag)
+	a.Equal("email,unique", fs[3].Tag.Get("db"))
+	a.Equal(reflect.StructTag(""), fs[4].Tag)
+}
+
+func TestDescribeStructFields(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(structFieldsTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	r, err := DescribeStructFields(d.StructFields())
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal(d.Fields().Names(), r.Fields().Names())
+	a.Equal([]int{0, 0}, r.Field("ID").Index())
+	a.True(r.Field("structFieldsTestBase").Embedded())
+	a.Equal("unique", r.Field("Email").Tag("db").Parameters()[0].Name())
+
+	r, err = DescribeStructFields([]reflect.StructField{
+		{Name: "A", Type: reflect.TypeOf(""), Tag: `json:"a"`},
+		{Name: "B", Type: reflect.TypeOf(0)},
+	})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]int{1}, r.Field("B").Index())
+	a.Equal("a", r.Field("A").Tag("json").Value())
+
+	_, err = DescribeStructFields([]reflect.StructField{{Name: "A", Type: reflect.TypeOf(""), Tag: `json:"a`}})
+	a.Error(err)
+}
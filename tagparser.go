@@ -4,25 +4,41 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
-func ParseTagList(input string) (TagList, error) {
-	tags := TagList{}
+func ParseTagList(input string, opts ...Option) (TagList, error) {
+	tags, err := parseTagList(input, getOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ParseTagList: %w", err)
+	}
+
+	return tags, nil
+}
 
+func parseTagList(input string, o *options) (TagList, error) {
 	tagPositions, err := parseTagPositionList(input)
 	if err != nil {
-		return nil, fmt.Errorf("reflectutil.ParseTagList: could not parse struct tags: %w", err)
+		stats.parseErrors.Add(1)
+		return nil, fmt.Errorf("reflectutil.parseTagList: could not parse struct tags: %w", err)
 	}
 
-	for _, rawTag := range tagPositions.getNamesAndValues(input) {
+	tags := o.arena.tagList(len(tagPositions))
+
+	for i, rawTag := range tagPositions.getNamesAndValues(input) {
 		unquoted, err := rawTag.unquotedValue()
 		if err != nil {
-			return nil, fmt.Errorf("reflectutil.ParseTagList: could not unquote value for tag %s: %w", rawTag.name, err)
+			stats.parseErrors.Add(1)
+			return nil, fmt.Errorf("reflectutil.parseTagList: could not unquote value for tag %s: %w", rawTag.name, err)
 		}
 
-		tag, err := ParseTag(rawTag.name, unquoted)
+		tag, err := parseTag(rawTag.name, unquoted, o)
 		if err != nil {
-			return nil, fmt.Errorf("reflectutil.ParseTagList: could not parse value for tag %s: %w", rawTag.name, err)
+			return nil, fmt.Errorf("reflectutil.parseTagList: could not parse value for tag %s: %w", rawTag.name, err)
+		}
+
+		if o.spans {
+			tag.span = tagPositions[i].span(input, rawTag.value, tag.parameters)
 		}
 
 		tags = append(tags, *tag)
@@ -31,16 +47,184 @@ func ParseTagList(input string) (TagList, error) {
 	return tags, nil
 }
 
-func ParseTag(name, tagValue string) (*Tag, error) {
-	value, parameters, err := parseValueAndParameterList(tagValue)
+func ParseTag(name, tagValue string, opts ...Option) (*Tag, error) {
+	tag, err := parseTag(name, tagValue, getOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ParseTag: %w", err)
+	}
+
+	return tag, nil
+}
+
+func parseTag(name, tagValue string, o *options) (*Tag, error) {
+	value, parameters, err := parseValueAndParameterList(tagValue, o.arena)
+	if err != nil {
+		stats.parseErrors.Add(1)
+		return nil, fmt.Errorf("reflectutil.parseTag: couldn't get value and parameters: %w", err)
+	}
+
+	if o.spans {
+		tagValueSpans(tagValue, value, parameters)
+	}
+
+	parameters, duplicates, err := applyDuplicateParameterPolicy(parameters, o.duplicateParameterPolicy)
 	if err != nil {
-		return nil, fmt.Errorf("reflectutil.ParseTag: couldn't get value and parameters: %w", err)
+		stats.parseErrors.Add(1)
+		return nil, fmt.Errorf("reflectutil.parseTag: %w", err)
+	}
+
+	stats.tagsParsed.Add(1)
+
+	tag := &Tag{name: name, value: value, parameters: parameters, duplicateParameters: duplicates}
+	if o.spans {
+		tag.span = Span{0, len(tagValue)}
+	}
+
+	return tag, nil
+}
+
+// Span is a half-open range of byte offsets.
+type Span struct{ Start, End int }
+
+func (s Span) String() string { return fmt.Sprintf("%d-%d", s.Start, s.End) }
+
+// tagValueSpans sets the span of each parameter, relative to tagValue. The
+// parameters are still in the order they were written, so each is found by
+// searching on from the previous one.
+func tagValueSpans(tagValue, value string, parameters ParameterList) {
+	offset := len(value)
+
+	for i := range parameters {
+		raw := parameters[i].rawValue()
+		if n := strings.Index(tagValue[offset:], raw); n != -1 {
+			offset += n
+		}
+
+		parameters[i].span = Span{offset, offset + len(raw)}
+		offset += len(raw)
+	}
+}
+
+// span returns the tag's position in input, and moves its parameters' spans
+// from offsets within the unquoted value to offsets within input.
+func (t tagPosition) span(input, rawValue string, parameters ParameterList) Span {
+	if t.colon == 0 {
+		return Span{t.nameStart, t.nameEnd + 1}
+	}
+
+	offsets := unquotedOffsets(rawValue)
+	for i := range parameters {
+		p := &parameters[i]
+		p.span = Span{t.valueStart + offsets[p.span.Start], t.valueStart + offsets[p.span.End]}
+	}
+
+	return Span{t.nameStart, t.valueEnd + 1}
+}
+
+// unquotedOffsets returns, for each byte of the unquoted form of the quoted
+// string s, the offset within s of the character it came from, followed by
+// the offset of the closing quote.
+func unquotedOffsets(s string) []int {
+	r := make([]int, 0, len(s))
+
+	in, pos := s[1:len(s)-1], 1
+	for len(in) > 0 {
+		c, multibyte, tail, err := strconv.UnquoteChar(in, '"')
+		if err != nil {
+			break
+		}
+
+		n := 1
+		if multibyte {
+			n = utf8.RuneLen(c)
+		}
+		for ; n > 0; n-- {
+			r = append(r, pos)
+		}
+
+		pos += len(in) - len(tail)
+		in = tail
 	}
 
-	return &Tag{name: name, value: value, parameters: parameters}, nil
+	return append(r, pos)
+}
+
+type DuplicateParameterPolicy int
+
+const (
+	// DuplicateParametersKeepAll keeps every repeated parameter, in order.
+	// ParameterList.Get returns the first of them.
+	DuplicateParametersKeepAll DuplicateParameterPolicy = iota
+	// DuplicateParametersKeepFirst keeps only the first of each repeated
+	// parameter.
+	DuplicateParametersKeepFirst
+	// DuplicateParametersKeepLast keeps only the last of each repeated
+	// parameter.
+	DuplicateParametersKeepLast
+	// DuplicateParametersError fails parsing if any parameter is repeated.
+	DuplicateParametersError
+)
+
+func (p DuplicateParameterPolicy) String() string {
+	switch p {
+	case DuplicateParametersKeepAll:
+		return "KeepAll"
+	case DuplicateParametersKeepFirst:
+		return "KeepFirst"
+	case DuplicateParametersKeepLast:
+		return "KeepLast"
+	case DuplicateParametersError:
+		return "Error"
+	default:
+		return fmt.Sprintf("[UNKNOWN POLICY %d]", int(p))
+	}
 }
 
-func parseValueAndParameterList(tagValue string) (string, ParameterList, error) {
+func applyDuplicateParameterPolicy(parameters ParameterList, policy DuplicateParameterPolicy) (ParameterList, bool, error) {
+	seen := make(map[string]int, len(parameters))
+	duplicates := false
+
+	for _, p := range parameters {
+		seen[p.name]++
+		if seen[p.name] > 1 {
+			duplicates = true
+		}
+	}
+
+	if !duplicates {
+		return parameters, false, nil
+	}
+
+	switch policy {
+	case DuplicateParametersKeepFirst:
+		r := make(ParameterList, 0, len(seen))
+		for _, p := range parameters {
+			if seen[p.name] > 0 {
+				r = append(r, p)
+				seen[p.name] = 0
+			}
+		}
+		return r, true, nil
+	case DuplicateParametersKeepLast:
+		r := make(ParameterList, 0, len(seen))
+		for _, p := range parameters {
+			if seen[p.name]--; seen[p.name] == 0 {
+				r = append(r, p)
+			}
+		}
+		return r, true, nil
+	case DuplicateParametersError:
+		for _, p := range parameters {
+			if seen[p.name] > 1 {
+				return nil, true, fmt.Errorf("reflectutil.applyDuplicateParameterPolicy: parameter %s appears %d times", p.name, seen[p.name])
+			}
+		}
+	}
+
+	return parameters, true, nil
+}
+
+func parseValueAndParameterList(tagValue string, arena *describeArena) (string, ParameterList, error) {
 	if tagValue == "" {
 		return "", ParameterList{}, nil
 	}
@@ -54,7 +238,7 @@ func parseValueAndParameterList(tagValue string) (string, ParameterList, error)
 		return valueAndParameters[0], ParameterList{}, nil
 	}
 
-	parameters, err := parseParameterList(valueAndParameters[1])
+	parameters, err := parseParameterList(valueAndParameters[1], arena)
 	if err != nil {
 		return "", nil, fmt.Errorf("reflectutil.parseValueAndParameterList: couldn't get parameters: %w", err)
 	}
@@ -62,10 +246,29 @@ func parseValueAndParameterList(tagValue string) (string, ParameterList, error)
 	return valueAndParameters[0], parameters, nil
 }
 
-func parseParameterList(input string) (ParameterList, error) {
-	parameters := ParameterList{}
+func parseParameterList(input string, arena *describeArena) (ParameterList, error) {
+	parameters := arena.parameterList(strings.Count(input, ",") + 1)
+
+	for len(input) > 0 {
+		e := input
+		if i := strings.IndexAny(input, ",:"); i != -1 && input[i] == ':' && i+1 < len(input) && isParameterQuote(input[i+1]) {
+			n, err := quotedParameterLength(input[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("reflectutil.parseParameterList: parameter %s: %w", input[:i], err)
+			}
+
+			e, input = input[:i+1+n], input[i+1+n:]
+			if input != "" && input[0] != ',' {
+				return nil, fmt.Errorf("reflectutil.parseParameterList: parameter %s: unexpected %q after quoted value", e[:i], input[0])
+			}
+		} else if i := strings.IndexByte(input, ','); i != -1 {
+			e, input = input[:i], input[i:]
+		} else {
+			input = ""
+		}
+
+		input = strings.TrimPrefix(input, ",")
 
-	for _, e := range strings.Split(input, ",") {
 		if e == "" {
 			continue
 		}
@@ -80,6 +283,103 @@ func parseParameterList(input string) (ParameterList, error) {
 	return parameters, nil
 }
 
+func isParameterQuote(c byte) bool { return c == '"' || c == '\'' }
+
+// quotedParameterLength returns the length of the quoted string at the start
+// of s, including its quotes. Backslashes escape the character after them.
+func quotedParameterLength(s string) (int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[0]:
+			if _, ok := unquoteParameterValue(s[:i+1]); !ok {
+				return 0, fmt.Errorf("invalid quoted value %s", s[:i+1])
+			}
+
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated quoted value")
+}
+
+// unquoteParameterValue unquotes a double quoted value with Go's rules, or a
+// single quoted one by removing the backslashes before escaped characters.
+func unquoteParameterValue(s string) (string, bool) {
+	if len(s) < 2 || !isParameterQuote(s[0]) || s[len(s)-1] != s[0] {
+		return "", false
+	}
+
+	if s[0] == '"' {
+		r, err := strconv.Unquote(s)
+		return r, err == nil
+	}
+
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, "\\") {
+		return s, true
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String(), true
+}
+
+// SplitList splits s on sep. A backslash escapes the character after it, so
+// an item can contain the separator by preceding it with a backslash.
+// Surrounding whitespace is trimmed from each item (unless the separator is
+// itself whitespace) and empty items are dropped, so repeated separators are
+// harmless.
+func SplitList(s, sep string) []string {
+	r := []string{}
+
+	if sep == "" {
+		if s = strings.TrimSpace(s); s != "" {
+			r = append(r, s)
+		}
+		return r
+	}
+
+	trim := strings.TrimSpace(sep) != ""
+
+	var current strings.Builder
+
+	flush := func() {
+		item := current.String()
+		if trim {
+			item = strings.TrimSpace(item)
+		}
+		if item != "" {
+			r = append(r, item)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			current.WriteByte(s[i])
+		case strings.HasPrefix(s[i:], sep):
+			flush()
+			i += len(sep) - 1
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+
+	flush()
+
+	return r
+}
+
 func validTagNameCharacter(c rune) bool {
 	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_'
 }
@@ -164,11 +464,32 @@ func (l tagPositionList) getNamesAndValues(s string) []tagNameAndValue {
 func parseTagPositionList(tag string) (tagPositionList, error) {
 	positions := make(tagPositionList, 0)
 
+	for i := 0; ; {
+		current, next, ok, err := scanTagPosition(tag, i)
+		if err != nil {
+			return nil, fmt.Errorf("reflectutil.parseTagPositionList: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		positions = append(positions, current)
+		i = next
+	}
+
+	return positions, nil
+}
+
+// scanTagPosition finds the next tag in tag, starting at offset. It returns
+// the tag's position and the offset to resume scanning from, or false once
+// there are no more tags.
+func scanTagPosition(tag string, offset int) (tagPosition, int, bool, error) {
 	state := parseTagPositionListStateInitial
 
 	var current tagPosition
 
-	for i, c := range tag {
+	for j, c := range tag[offset:] {
+		i := offset + j
 	start:
 		switch state {
 		case parseTagPositionListStateInitial:
@@ -191,10 +512,7 @@ func parseTagPositionList(tag string) (tagPositionList, error) {
 				continue
 			case c == ' ':
 				current.nameEnd = i - 1
-				positions = append(positions, current)
-				current = tagPosition{}
-				state = parseTagPositionListStateInitial
-				goto start
+				return current, i, true, nil
 			}
 		case parseTagPositionListStateExpectValue:
 			switch {
@@ -209,10 +527,7 @@ func parseTagPositionList(tag string) (tagPositionList, error) {
 			switch {
 			case c == '"':
 				current.valueEnd = i
-				positions = append(positions, current)
-				current = tagPosition{}
-				state = parseTagPositionListStateInitial
-				continue
+				return current, i + 1, true, nil
 			case c == '\\':
 				state = parseTagPositionListStateReadingEscapedCharacter
 				continue
@@ -224,18 +539,16 @@ func parseTagPositionList(tag string) (tagPositionList, error) {
 			continue
 		}
 
-		return nil, fmt.Errorf("reflectutil.parseTagPositionList: unexpected '%c' at %d in state %s", c, i, state)
+		return tagPosition{}, 0, false, fmt.Errorf("unexpected '%c' at %d in state %s", c, i, state)
 	}
 
 	switch state {
 	case parseTagPositionListStateInitial:
-		// nothing
+		return tagPosition{}, len(tag), false, nil
 	case parseTagPositionListStateReadingName:
 		current.nameEnd = len(tag) - 1
-		positions = append(positions, current)
+		return current, len(tag), true, nil
 	default:
-		return nil, fmt.Errorf("reflectutil.parseTagPositionList: unexpected eof in state %s", state)
+		return tagPosition{}, 0, false, fmt.Errorf("unexpected eof in state %s", state)
 	}
-
-	return positions, nil
 }
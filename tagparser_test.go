@@ -26,16 +26,20 @@ func init() {
 		for _, parameters := range []struct {
 			description, value string
 			result             ParameterList
+			duplicates         bool
 		}{
-			{"no parameters", "", ParameterList{}},
-			{"one parameter with key and no value", "p", ParameterList{{"p", ""}}},
-			{"one parameter with key and value", "p:v", ParameterList{{"p", "v"}}},
-			{"two parameters with different keys and no values", "p1,p2", ParameterList{{"p1", ""}, {"p2", ""}}},
-			{"two parameters with different keys and the same values", "p1:v,p2:v", ParameterList{{"p1", "v"}, {"p2", "v"}}},
-			{"two parameters with different keys and different values", "p1:v1,p2:v2", ParameterList{{"p1", "v1"}, {"p2", "v2"}}},
-			{"two parameters with the same key and no value", "p,p", ParameterList{{"p", ""}, {"p", ""}}},
-			{"two parameters with the same key and the same values", "p:v,p:v", ParameterList{{"p", "v"}, {"p", "v"}}},
-			{"two parameters with the same key and no value", "p:v1,p:v2", ParameterList{{"p", "v1"}, {"p", "v2"}}},
+			{"no parameters", "", ParameterList{}, false},
+			{"one parameter with key and no value", "p", ParameterList{{name: "p", value: ""}}, false},
+			{"one parameter with key and value", "p:v", ParameterList{{name: "p", value: "v"}}, false},
+			{"two parameters with different keys and no values", "p1,p2", ParameterList{{name: "p1", value: ""}, {name: "p2", value: ""}}, false},
+			{"two parameters with different keys and the same values", "p1:v,p2:v", ParameterList{{name: "p1", value: "v"}, {name: "p2", value: "v"}}, false},
+			{"two parameters with different keys and different values", "p1:v1,p2:v2", ParameterList{{name: "p1", value: "v1"}, {name: "p2", value: "v2"}}, false},
+			{"two parameters with the same key and no value", "p,p", ParameterList{{name: "p", value: ""}, {name: "p", value: ""}}, true},
+			{"two parameters with the same key and the same values", "p:v,p:v", ParameterList{{name: "p", value: "v"}, {name: "p", value: "v"}}, true},
+			{"two parameters with the same key and no value", "p:v1,p:v2", ParameterList{{name: "p", value: "v1"}, {name: "p", value: "v2"}}, true},
+			{"a double quoted parameter value", `p:"a, b",q`, ParameterList{{name: "p", value: `"a, b"`}, {name: "q", value: ""}}, false},
+			{"a single quoted parameter value", `p:'a, b',q`, ParameterList{{name: "p", value: `'a, b'`}, {name: "q", value: ""}}, false},
+			{"a quoted parameter value with escapes", `p:"say \"hi\", ok"`, ParameterList{{name: "p", value: `"say \"hi\", ok"`}}, false},
 		} {
 			input := value.value
 			if parameters.value != "" {
@@ -46,9 +50,10 @@ func init() {
 				name:  value.description + " with " + parameters.description,
 				input: input,
 				tag: &Tag{
-					name:       "x",
-					value:      value.result,
-					parameters: parameters.result,
+					name:                "x",
+					value:               value.result,
+					parameters:          parameters.result,
+					duplicateParameters: parameters.duplicates,
 				},
 			})
 		}
@@ -73,6 +78,133 @@ func TestParseTag(t *testing.T) {
 	}
 }
 
+func TestQuotedParameterValues(t *testing.T) {
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		input, raw, value string
+	}{
+		{`msg:"hello, world"`, `"hello, world"`, "hello, world"},
+		{`msg:'hello, world'`, `'hello, world'`, "hello, world"},
+		{`msg:'it\'s, ok'`, `'it\'s, ok'`, "it's, ok"},
+		{`msg:"tab\there"`, `"tab\there"`, "tab\there"},
+		{`msg:""`, `""`, ""},
+		{`msg:plain`, "plain", "plain"},
+		{`msg:a"b`, `a"b`, `a"b`},
+	} {
+		tag, err := ParseTag("validate", "required,"+tc.input+",email")
+		if !a.NoError(err, tc.input) {
+			continue
+		}
+
+		a.Equal("required", tag.Value(), tc.input)
+		a.Equal([]string{"msg", "email"}, tag.Parameters().Names(), tc.input)
+		a.Equal(tc.raw, tag.Parameter("msg").RawValue(), tc.input)
+		a.Equal(tc.value, tag.Parameter("msg").Value(), tc.input)
+	}
+
+	for _, tc := range []struct {
+		input, error string
+	}{
+		{`msg:"hello`, "parameter msg: unterminated quoted value"},
+		{`msg:"hello"x`, "parameter msg: unexpected 'x' after quoted value"},
+		{`msg:"\q"`, `parameter msg: invalid quoted value "\q"`},
+	} {
+		_, err := ParseTag("validate", "required,"+tc.input)
+		a.ErrorContains(err, tc.error, tc.input)
+	}
+
+	d, err := GetDescription(struct {
+		A string `validate:"required,msg:\"hello, world\""`
+	}{})
+	if a.NoError(err) {
+		a.Equal("hello, world", d.Field("A").Tag("validate").Parameter("msg").Value())
+		a.Equal(`validate:"required,msg:\"hello, world\""`, string(d.Field("A").Tags().StructTag()))
+	}
+}
+
+func TestTagSpans(t *testing.T) {
+	a := assert.New(t)
+
+	input := `json:"name,omitempty"  validate:"required,msg:\"caf\u00e9, ok\",max:10" db:"-" x`
+
+	tags, err := ParseTagList(input, WithTagSpans())
+	if !a.NoError(err) {
+		return
+	}
+
+	text := func(s Span) string { return input[s.Start:s.End] }
+
+	a.Equal(`json:"name,omitempty"`, text(tags[0].Span()))
+	a.Equal("omitempty", text(tags[0].Parameters()[0].Span()))
+	a.Equal(`validate:"required,msg:\"caf\u00e9, ok\",max:10"`, text(tags[1].Span()))
+	a.Equal(`msg:\"caf\u00e9, ok\"`, text(tags[1].Parameters()[0].Span()))
+	a.Equal("max:10", text(tags[1].Parameters()[1].Span()))
+	a.Equal(Span{Start: 64, End: 70}, tags[1].Parameters()[1].Span())
+	a.Equal(`db:"-"`, text(tags[2].Span()))
+	a.Equal("x", text(tags[3].Span()))
+
+	tag, err := ParseTag("x", "v,a,,b:1,a", WithTagSpans(), WithDuplicateParameterPolicy(DuplicateParametersKeepLast))
+	if a.NoError(err) {
+		a.Equal(Span{0, 10}, tag.Span())
+		a.Equal([]Span{{5, 8}, {9, 10}}, []Span{tag.Parameters()[0].Span(), tag.Parameters()[1].Span()})
+	}
+
+	tags, err = ParseTagList(input)
+	if a.NoError(err) {
+		a.Equal(Span{}, tags[1].Span())
+		a.Equal(Span{}, tags[1].Parameters()[1].Span())
+	}
+
+	a.Equal("3-7", Span{3, 7}.String())
+}
+
+func TestDuplicateParameterPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy DuplicateParameterPolicy
+		result ParameterList
+		error  string
+	}{
+		{DuplicateParametersKeepAll, ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "a", value: "2"}, {name: "c", value: ""}, {name: "b", value: "x"}}, ""},
+		{DuplicateParametersKeepFirst, ParameterList{{name: "a", value: "1"}, {name: "b", value: ""}, {name: "c", value: ""}}, ""},
+		{DuplicateParametersKeepLast, ParameterList{{name: "a", value: "2"}, {name: "c", value: ""}, {name: "b", value: "x"}}, ""},
+		{DuplicateParametersError, nil, "parameter a appears 2 times"},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			a := assert.New(t)
+
+			tag, err := ParseTag("x", "v,a:1,b,a:2,c,b:x", WithDuplicateParameterPolicy(tc.policy))
+
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+				a.Nil(tag)
+				return
+			}
+
+			if a.NoError(err) {
+				a.Equal(tc.result, tag.Parameters())
+				a.True(tag.HasDuplicateParameters())
+			}
+		})
+	}
+
+	t.Run("through GetDescription", func(t *testing.T) {
+		a := assert.New(t)
+
+		type S struct {
+			A string `x:"v,p:1,p:2"`
+		}
+
+		_, err := GetDescription(S{}, WithDuplicateParameterPolicy(DuplicateParametersError))
+		a.ErrorContains(err, "parameter p appears 2 times")
+
+		d, err := GetDescription(S{}, WithDuplicateParameterPolicy(DuplicateParametersKeepLast))
+		if a.NoError(err) {
+			a.Equal(ParameterList{{name: "p", value: "2"}}, d.Field("A").Tag("x").Parameters())
+		}
+	})
+}
+
 func BenchmarkParseTag(b *testing.B) {
 	for _, tc := range parseTagTestCases {
 		b.Run(tc.name, func(b *testing.B) {
@@ -329,3 +461,39 @@ func BenchmarkParseTagListAndGetNamesAndValues(b *testing.B) {
 		})
 	}
 }
+
+func TestSplitList(t *testing.T) {
+	for _, tc := range []struct {
+		input, sep string
+		result     []string
+	}{
+		{"", " ", []string{}},
+		{"read write admin", " ", []string{"read", "write", "admin"}},
+		{"read   write ", " ", []string{"read", "write"}},
+		{"a|b|c", "|", []string{"a", "b", "c"}},
+		{"a | b ||c", "|", []string{"a", "b", "c"}},
+		{`a\|b|c`, "|", []string{"a|b", "c"}},
+		{`a\\|b`, "|", []string{`a\`, "b"}},
+		{"a::b", "::", []string{"a", "b"}},
+		{" a ", "", []string{"a"}},
+	} {
+		t.Run(tc.input+" "+tc.sep, func(t *testing.T) {
+			assert.Equal(t, tc.result, SplitList(tc.input, tc.sep))
+		})
+	}
+}
+
+func TestValueList(t *testing.T) {
+	a := assert.New(t)
+
+	tags, err := ParseTagList(`scopes:"read write admin" env:"A,B" roles:",allow:a|b"`)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]string{"read", "write", "admin"}, tags.Get("scopes").ValueList(" "))
+	a.Equal([]string{"A", "B"}, tags.Get("env").ValueList(","))
+	a.Equal([]string{"A"}, tags.Get("env").ValueList(" "))
+	a.Equal([]string{"allow:a|b"}, tags.Get("roles").ValueList(","))
+	a.Equal([]string{"a", "b"}, tags.Get("roles").Parameter("allow").ValueList("|"))
+}
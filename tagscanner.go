@@ -0,0 +1,113 @@
+package reflectutil
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TagScanner walks the tags of a raw struct tag string one at a time, without
+// allocating, for callers that only need one or two tags and would rather not
+// parse the rest. It uses the same parser as ParseTagList, but leaves values
+// and parameters alone until asked for.
+//
+//	s := reflectutil.ScanTags(string(field.Tag))
+//	for s.Next() {
+//		if s.Name() == "json" {
+//			...
+//		}
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type TagScanner struct {
+	input   string
+	offset  int
+	current tagPosition
+	err     error
+}
+
+// ScanTags returns a TagScanner for input, e.g. string(reflect.StructTag).
+func ScanTags(input string) TagScanner {
+	return TagScanner{input: input}
+}
+
+// Next advances to the next tag, returning false when there are no more or
+// the input is malformed (see Err).
+func (s *TagScanner) Next() bool {
+	if s.err != nil || s.offset >= len(s.input) {
+		return false
+	}
+
+	current, next, ok, err := scanTagPosition(s.input, s.offset)
+	if err != nil {
+		s.err = fmt.Errorf("reflectutil.TagScanner.Next: %w", err)
+		return false
+	}
+
+	s.current, s.offset = current, next
+
+	return ok
+}
+
+// Err returns the error that stopped the scan, if any.
+func (s *TagScanner) Err() error { return s.err }
+
+// Name returns the current tag's name.
+func (s *TagScanner) Name() string { return s.current.getName(s.input) }
+
+// RawValue returns the current tag's value as it appears in the input, quotes
+// and escapes included. It is empty for a tag with no value.
+func (s *TagScanner) RawValue() string { return s.current.getValue(s.input) }
+
+// Value returns the current tag's unquoted value. It only allocates if the
+// value contains escape sequences.
+func (s *TagScanner) Value() (string, error) {
+	v := s.RawValue()
+	if v == "" {
+		return "", nil
+	}
+
+	u, err := strconv.Unquote(v)
+	if err != nil {
+		return "", fmt.Errorf("reflectutil.TagScanner.Value: could not unquote value for tag %s: %w", s.Name(), err)
+	}
+
+	return u, nil
+}
+
+// Span returns the byte offsets of the current tag's name and raw value
+// within the input, each as a half-open range. The value's range is empty for
+// a tag with no value.
+func (s *TagScanner) Span() (nameStart, nameEnd, valueStart, valueEnd int) {
+	nameStart, nameEnd = s.current.nameStart, s.current.nameEnd+1
+	if s.current.colon != 0 {
+		valueStart, valueEnd = s.current.valueStart, s.current.valueEnd+1
+	} else {
+		valueStart, valueEnd = nameEnd, nameEnd
+	}
+
+	return nameStart, nameEnd, valueStart, valueEnd
+}
+
+// LookupTag returns the unquoted value of the first tag called name in input,
+// scanning no further than it has to. Unlike reflect.StructTag.Lookup, it
+// reports malformed input up to the tag rather than ignoring it.
+func LookupTag(input, name string) (string, bool, error) {
+	s := ScanTags(input)
+	for s.Next() {
+		if s.Name() == name {
+			v, err := s.Value()
+			if err != nil {
+				return "", false, fmt.Errorf("reflectutil.LookupTag: %w", err)
+			}
+
+			return v, true, nil
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return "", false, fmt.Errorf("reflectutil.LookupTag: %w", err)
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,77 @@
+package reflectutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagScanner(t *testing.T) {
+	a := assert.New(t)
+
+	input := `json:"name,omitempty" flag db:"a\"b"`
+
+	var names, raw, values []string
+	var spans [][4]int
+
+	s := ScanTags(input)
+	for s.Next() {
+		names = append(names, s.Name())
+		raw = append(raw, s.RawValue())
+
+		v, err := s.Value()
+		a.NoError(err)
+		values = append(values, v)
+
+		ns, ne, vs, ve := s.Span()
+		spans = append(spans, [4]int{ns, ne, vs, ve})
+	}
+	a.NoError(s.Err())
+
+	a.Equal([]string{"json", "flag", "db"}, names)
+	a.Equal([]string{`"name,omitempty"`, "", `"a\"b"`}, raw)
+	a.Equal([]string{"name,omitempty", "", `a"b`}, values)
+	a.Equal([][4]int{{0, 4, 5, 21}, {22, 26, 26, 26}, {27, 29, 30, 36}}, spans)
+
+	s = ScanTags(`a:"b" c:`)
+	a.True(s.Next())
+	a.False(s.Next())
+	a.EqualError(s.Err(), "reflectutil.TagScanner.Next: unexpected eof in state ExpectValue")
+
+	s = ScanTags("")
+	a.False(s.Next())
+	a.NoError(s.Err())
+}
+
+func TestLookupTag(t *testing.T) {
+	a := assert.New(t)
+
+	v, ok, err := LookupTag(`json:"name" db:"id"`, "db")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal("id", v)
+
+	_, ok, err = LookupTag(`json:"name"`, "db")
+	a.NoError(err)
+	a.False(ok)
+
+	v, ok, err = LookupTag(`json:"name" $`, "json")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal("name", v)
+
+	_, _, err = LookupTag(`json:"name" $`, "db")
+	a.Error(err)
+}
+
+func TestTagScannerAllocations(t *testing.T) {
+	a := assert.New(t)
+
+	input := `json:"name,omitempty" db:"id" validate:"required"`
+
+	a.Zero(testing.AllocsPerRun(100, func() {
+		if _, ok, err := LookupTag(input, "validate"); !ok || err != nil {
+			panic("not found")
+		}
+	}))
+}
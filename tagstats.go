@@ -0,0 +1,100 @@
+package reflectutil
+
+import (
+	"sort"
+)
+
+// TagUsage reports how widely a tag name is used across the registered
+// types.
+type TagUsage struct {
+	Name string
+	// Fields is the number of fields with the tag, and Types the number of
+	// types with at least one such field.
+	Fields     int
+	Types      int
+	Parameters []ParameterUsage
+}
+
+// ParameterUsage reports how widely a parameter is used on a particular tag.
+type ParameterUsage struct {
+	Name   string
+	Fields int
+	Types  int
+}
+
+// TagStats reports every tag name that appears on the fields of the
+// registered types (see RegisterType), with the parameters used with each,
+// all sorted by name. It's meant for finding uses of deprecated tags and
+// measuring migrations away from them.
+func TagStats() []TagUsage {
+	return tagStats(RegisteredTypes())
+}
+
+func tagStats(descriptions []*StructDescription) []TagUsage {
+	type usage struct {
+		fields int
+		types  int
+		params map[string]*usage
+	}
+
+	tags := make(map[string]*usage)
+
+	for _, d := range descriptions {
+		seen := make(map[*usage]bool)
+
+		count := func(u *usage) {
+			u.fields++
+			if !seen[u] {
+				seen[u] = true
+				u.types++
+			}
+		}
+
+		for _, f := range d.fields {
+			seenTags := make(map[string]bool)
+			seenParams := make(map[string]bool)
+
+			for _, t := range f.tags {
+				u := tags[t.name]
+				if u == nil {
+					u = &usage{params: make(map[string]*usage)}
+					tags[t.name] = u
+				}
+
+				if !seenTags[t.name] {
+					seenTags[t.name] = true
+					count(u)
+				}
+
+				for _, p := range t.parameters {
+					pu := u.params[p.name]
+					if pu == nil {
+						pu = &usage{}
+						u.params[p.name] = pu
+					}
+
+					if k := t.name + "\x00" + p.name; !seenParams[k] {
+						seenParams[k] = true
+						count(pu)
+					}
+				}
+			}
+		}
+	}
+
+	r := make([]TagUsage, 0, len(tags))
+
+	for name, u := range tags {
+		e := TagUsage{Name: name, Fields: u.fields, Types: u.types, Parameters: []ParameterUsage{}}
+		for pname, pu := range u.params {
+			e.Parameters = append(e.Parameters, ParameterUsage{Name: pname, Fields: pu.fields, Types: pu.types})
+		}
+		sort.Slice(e.Parameters, func(i, j int) bool { return e.Parameters[i].Name < e.Parameters[j].Name })
+
+		r = append(r, e)
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Name < r[j].Name })
+
+	return r
+}
@@ -0,0 +1,35 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tagStatsTestUser struct {
+	ID    int    `json:"id" db:"id,pk"`
+	Name  string `json:"name,omitempty" db:"name"`
+	Email string `json:"email,omitempty" legacy:"mail"`
+}
+
+type tagStatsTestGroup struct {
+	ID   int    `json:"id,string" db:"id,pk"`
+	Note string `json:"-"`
+	Raw  string
+}
+
+func TestTagStats(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(RegisterType(tagStatsTestUser{}))
+	a.NoError(RegisterType(tagStatsTestGroup{}))
+	defer UnregisterType(reflect.TypeOf(tagStatsTestUser{}))
+	defer UnregisterType(reflect.TypeOf(tagStatsTestGroup{}))
+
+	a.Equal([]TagUsage{
+		{Name: "db", Fields: 3, Types: 2, Parameters: []ParameterUsage{{Name: "pk", Fields: 2, Types: 2}}},
+		{Name: "json", Fields: 5, Types: 2, Parameters: []ParameterUsage{{Name: "omitempty", Fields: 2, Types: 1}, {Name: "string", Fields: 1, Types: 1}}},
+		{Name: "legacy", Fields: 1, Types: 1, Parameters: []ParameterUsage{}},
+	}, TagStats())
+}
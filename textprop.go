@@ -0,0 +1,307 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TextProperty is one content line of an RFC 6350 vCard or RFC 5545
+// iCalendar object, e.g. "DTSTART;TZID=UTC:20240102T030405Z". Value is kept
+// escaped, as it appears in the line.
+type TextProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// String returns the property as a content line, without folding.
+func (p TextProperty) String() string {
+	var b strings.Builder
+
+	b.WriteString(p.Name)
+
+	keys := make([]string, 0, len(p.Params))
+	for k := range p.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := p.Params[k]
+		if strings.ContainsAny(v, ";:,") {
+			v = `"` + v + `"`
+		}
+		b.WriteString(";" + k + "=" + v)
+	}
+
+	b.WriteString(":" + p.Value)
+
+	return b.String()
+}
+
+// ParseTextProperty parses an unfolded content line.
+func ParseTextProperty(line string) (TextProperty, error) {
+	p := TextProperty{}
+
+	quoted := false
+	start := 0
+	var parts []string
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ';' || c == ':'):
+			parts = append(parts, line[start:i])
+			start = i + 1
+
+			if c == ':' {
+				p.Value = line[i+1:]
+
+				if parts[0] == "" {
+					return TextProperty{}, fmt.Errorf("reflectutil.ParseTextProperty: missing name in %q", line)
+				}
+
+				p.Name = parts[0]
+
+				for _, e := range parts[1:] {
+					k, v, ok := strings.Cut(e, "=")
+					if !ok {
+						return TextProperty{}, fmt.Errorf("reflectutil.ParseTextProperty: invalid parameter %q in %q", e, line)
+					}
+
+					if p.Params == nil {
+						p.Params = make(map[string]string)
+					}
+
+					p.Params[k] = strings.Trim(v, `"`)
+				}
+
+				return p, nil
+			}
+		}
+	}
+
+	return TextProperty{}, fmt.Errorf("reflectutil.ParseTextProperty: missing value in %q", line)
+}
+
+var (
+	textPropertyEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	textPropertyUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+// TextPropertyName returns the property name given by the field's tag called
+// tag, e.g. "FN" for `vcard:"FN"`. Names are upper cased.
+func (f *Field) TextPropertyName(tag string) (string, bool) {
+	t := f.tags.Get(tag)
+	if t == nil || t.value == "" || t.value == "-" {
+		return "", false
+	}
+
+	return strings.ToUpper(t.value), true
+}
+
+// ToTextProperties returns a property for each field of v with a tag called
+// tag (usually "vcard" or "ics"), in field order. Values are formatted as
+// usual - times using the field's format parameter - and escaped; slices
+// become comma separated value lists, or one property per item with a multi
+// parameter (`vcard:"EMAIL,multi"`). Nil pointers are left out, as are zero
+// values of fields with an omitempty parameter.
+func ToTextProperties(v interface{}, tag string) ([]TextProperty, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToTextProperties: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return nil, fmt.Errorf("reflectutil.ToTextProperties: %w", err)
+	}
+
+	var r []TextProperty
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		name, ok := f.TextPropertyName(tag)
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok || (fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			continue
+		}
+
+		t := f.tags.Get(tag)
+		if t.parameters.Has("omitempty") && fv.IsZero() {
+			continue
+		}
+
+		values, err := textPropertyValues(f, fv)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		if t.parameters.Has("multi") {
+			for _, s := range values {
+				r = append(r, TextProperty{Name: name, Value: s})
+			}
+		} else {
+			r = append(r, TextProperty{Name: name, Value: strings.Join(values, ",")})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("reflectutil.ToTextProperties: %w", err)
+	}
+
+	return r, nil
+}
+
+func textPropertyValues(f *Field, v reflect.Value) ([]string, error) {
+	if !isTextPropertyList(v.Type()) {
+		s, err := formatString(f, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{textPropertyEscaper.Replace(s)}, nil
+	}
+
+	r := make([]string, v.Len())
+	for i := range r {
+		s, err := formatString(f, v.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		r[i] = textPropertyEscaper.Replace(s)
+	}
+
+	return r, nil
+}
+
+func isTextPropertyList(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8
+}
+
+// FromTextProperties fills v, which must be a pointer to a struct, from
+// props, matching property names to tags called tag without regard to case.
+// Values are unescaped and parsed as usual. Slice fields collect the items of
+// every matching property; other fields take the last one. Properties that no
+// field asked for (like BEGIN and END) are subject to WithUnknownKeyPolicy.
+func FromTextProperties(props []TextProperty, v interface{}, tag string, opts ...Option) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromTextProperties: %w", err)
+	}
+
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.FromTextProperties: %w", err)
+	}
+
+	byName := make(map[string][]string)
+	for _, p := range props {
+		name := strings.ToUpper(p.Name)
+		byName[name] = append(byName[name], p.Value)
+	}
+
+	o := getOptions(opts)
+
+	used := make(map[string]bool)
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		name, ok := f.TextPropertyName(tag)
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		values, ok := byName[name]
+		if !ok {
+			continue
+		}
+
+		used[name] = true
+
+		fv, err := fieldByIndexAlloc(f, rv)
+		if err == nil {
+			err = setFromTextProperty(f, fv, values)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Err: err})
+			continue
+		}
+
+		o.record(f.name, name, tag)
+	}
+
+	if err := unknownKeys(o, byName, used); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reflectutil.FromTextProperties: %w", err)
+	}
+
+	return nil
+}
+
+func setFromTextProperty(f *Field, v reflect.Value, values []string) error {
+	if !isTextPropertyList(derefType(v.Type())) {
+		return setFromString(f, v, textPropertyUnescaper.Replace(values[len(values)-1]))
+	}
+
+	var items []string
+	for _, s := range values {
+		for _, e := range splitTextPropertyList(s) {
+			items = append(items, textPropertyUnescaper.Replace(e))
+		}
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	r := reflect.MakeSlice(v.Type(), len(items), len(items))
+	for i, s := range items {
+		if err := setFromString(f, r.Index(i), s); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	v.Set(r)
+
+	return nil
+}
+
+// splitTextPropertyList splits s on commas that aren't escaped, leaving the
+// items escaped.
+func splitTextPropertyList(s string) []string {
+	var r []string
+
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			r = append(r, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(r, s[start:])
+}
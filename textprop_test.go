@@ -0,0 +1,106 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type textPropTestContact struct {
+	Name       string   `vcard:"FN"`
+	Emails     []string `vcard:"EMAIL,multi"`
+	Categories []string `vcard:"CATEGORIES"`
+	Note       string   `vcard:"NOTE,omitempty"`
+	Age        *int     `vcard:"X-AGE"`
+	Internal   string
+}
+
+type textPropTestEvent struct {
+	Summary string    `ics:"SUMMARY"`
+	Start   time.Time `ics:"DTSTART,format:20060102T150405Z"`
+}
+
+func TestTextProperty(t *testing.T) {
+	a := assert.New(t)
+
+	p, err := ParseTextProperty(`DTSTART;TZID="Europe/Paris";VALUE=DATE-TIME:20240102T030405`)
+	a.NoError(err)
+	a.Equal(TextProperty{Name: "DTSTART", Params: map[string]string{"TZID": "Europe/Paris", "VALUE": "DATE-TIME"}, Value: "20240102T030405"}, p)
+	a.Equal("DTSTART;TZID=Europe/Paris;VALUE=DATE-TIME:20240102T030405", p.String())
+
+	p, err = ParseTextProperty(`NOTE:a\, b: c`)
+	a.NoError(err)
+	a.Equal(TextProperty{Name: "NOTE", Value: `a\, b: c`}, p)
+
+	a.Equal(`X;A="x:y":1`, TextProperty{Name: "X", Params: map[string]string{"A": "x:y"}, Value: "1"}.String())
+
+	_, err = ParseTextProperty("NOTE")
+	a.EqualError(err, `reflectutil.ParseTextProperty: missing value in "NOTE"`)
+	_, err = ParseTextProperty(":x")
+	a.EqualError(err, `reflectutil.ParseTextProperty: missing name in ":x"`)
+	_, err = ParseTextProperty("NOTE;X:x")
+	a.EqualError(err, `reflectutil.ParseTextProperty: invalid parameter "X" in "NOTE;X:x"`)
+}
+
+func TestToTextProperties(t *testing.T) {
+	a := assert.New(t)
+
+	r, err := ToTextProperties(textPropTestContact{
+		Name:       "Smith, Jo",
+		Emails:     []string{"jo@example.com", "j@example.com"},
+		Categories: []string{"work", "a,b"},
+		Internal:   "x",
+	}, "vcard")
+	a.NoError(err)
+	a.Equal([]TextProperty{
+		{Name: "FN", Value: `Smith\, Jo`},
+		{Name: "EMAIL", Value: "jo@example.com"},
+		{Name: "EMAIL", Value: "j@example.com"},
+		{Name: "CATEGORIES", Value: `work,a\,b`},
+	}, r)
+
+	r, err = ToTextProperties(&textPropTestEvent{Summary: "Lunch;\nfood", Start: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, "ics")
+	a.NoError(err)
+	a.Equal([]TextProperty{
+		{Name: "SUMMARY", Value: `Lunch\;\nfood`},
+		{Name: "DTSTART", Value: "20240102T030405Z"},
+	}, r)
+}
+
+func TestFromTextProperties(t *testing.T) {
+	a := assert.New(t)
+
+	var c textPropTestContact
+	a.NoError(FromTextProperties([]TextProperty{
+		{Name: "BEGIN", Value: "VCARD"},
+		{Name: "fn", Value: `Smith\, Jo`},
+		{Name: "EMAIL", Value: "jo@example.com"},
+		{Name: "EMAIL", Value: "j@example.com"},
+		{Name: "CATEGORIES", Value: `work,a\,b`},
+		{Name: "X-AGE", Value: "30"},
+		{Name: "END", Value: "VCARD"},
+	}, &c, "vcard"))
+
+	age := 30
+	a.Equal(textPropTestContact{
+		Name:       "Smith, Jo",
+		Emails:     []string{"jo@example.com", "j@example.com"},
+		Categories: []string{"work", "a,b"},
+		Age:        &age,
+	}, c)
+
+	var e textPropTestEvent
+	a.NoError(FromTextProperties([]TextProperty{{Name: "SUMMARY", Value: `Lunch\;\nfood`}, {Name: "DTSTART", Value: "20240102T030405Z"}}, &e, "ics"))
+	a.Equal(textPropTestEvent{Summary: "Lunch;\nfood", Start: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, e)
+
+	var keys []string
+	a.NoError(FromTextProperties([]TextProperty{{Name: "BEGIN", Value: "VEVENT"}, {Name: "DTSTART", Value: "x"}}, &textPropTestContact{}, "vcard", WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"BEGIN", "DTSTART"}, keys)
+
+	err := FromTextProperties([]TextProperty{{Name: "DTSTART", Value: "2024"}}, &e, "ics")
+	var fieldError *FieldError
+	if a.ErrorAs(err, &fieldError) {
+		a.Equal("Start", fieldError.Field)
+	}
+}
@@ -0,0 +1,59 @@
+package reflectutil
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeLayout is used for fields that don't carry a format parameter.
+var DefaultTimeLayout = time.RFC3339
+
+// TimeLayouts maps the names accepted by the format parameter to layouts, so
+// layouts containing commas (which would otherwise start a new parameter) can
+// still be used, e.g. `json:"created,format:RFC1123"`. Any format value that
+// isn't in this map is used as a layout directly.
+var TimeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// TimeLayout returns the layout given by the first format parameter on any of
+// the field's tags, or DefaultTimeLayout.
+func (f *Field) TimeLayout() string {
+	for _, t := range f.tags {
+		if p := t.parameters.Get("format"); p != nil && p.Value() != "" {
+			if layout, ok := TimeLayouts[p.Value()]; ok {
+				return layout
+			}
+
+			return p.Value()
+		}
+	}
+
+	return DefaultTimeLayout
+}
+
+func (f *Field) ParseTime(s string) (time.Time, error) {
+	t, err := time.Parse(f.TimeLayout(), s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reflectutil.Field.ParseTime(%s): %w", f.name, err)
+	}
+
+	return t, nil
+}
+
+func (f *Field) FormatTime(t time.Time) string {
+	return t.Format(f.TimeLayout())
+}
@@ -0,0 +1,64 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeFormat(t *testing.T) {
+	type S struct {
+		Default time.Time
+		Named   time.Time `json:"named,format:RFC1123"`
+		Layout  time.Time `form:"layout,format:2006-01-02 15:04"`
+		Second  time.Time `json:"second" csv:"second,format:DateOnly"`
+		Quoted  time.Time `form:"quoted,format:'Mon, 02 Jan 2006'"`
+	}
+
+	d, err := GetDescription(S{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	when := time.Date(2023, 4, 5, 6, 7, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		field, layout, formatted string
+	}{
+		{"Default", time.RFC3339, "2023-04-05T06:07:00Z"},
+		{"Named", time.RFC1123, "Wed, 05 Apr 2023 06:07:00 UTC"},
+		{"Layout", "2006-01-02 15:04", "2023-04-05 06:07"},
+		{"Second", time.DateOnly, "2023-04-05"},
+		{"Quoted", "Mon, 02 Jan 2006", "Wed, 05 Apr 2023"},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			a := assert.New(t)
+
+			f := d.Field(tc.field)
+
+			a.Equal(tc.layout, f.TimeLayout())
+			a.Equal(tc.formatted, f.FormatTime(when))
+
+			parsed, err := f.ParseTime(tc.formatted)
+			if a.NoError(err) {
+				a.Equal(f.FormatTime(when), f.FormatTime(parsed))
+			}
+
+			_, err = f.ParseTime("not a time")
+			a.ErrorContains(err, "reflectutil.Field.ParseTime("+tc.field+")")
+		})
+	}
+}
+
+func TestTimeFormatQuotedSetFields(t *testing.T) {
+	a := assert.New(t)
+
+	var v struct {
+		When time.Time `form:"when,format:'Mon, 02 Jan 2006'"`
+	}
+
+	if a.NoError(SetFields(&v, map[string]interface{}{"when": "Wed, 05 Apr 2023"}, "form")) {
+		a.Equal(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), v.When)
+	}
+}
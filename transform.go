@@ -0,0 +1,110 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Transformer is a named step in a `transform:"trim,lower"` pipeline.
+type Transformer func(s string) string
+
+var transformers = struct {
+	sync.RWMutex
+	fns map[string]Transformer
+}{fns: map[string]Transformer{
+	"trim":   strings.TrimSpace,
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"squash": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+}}
+
+// RegisterTransformer makes fn available to transform tags under name,
+// replacing any existing transformer with that name. The built in
+// transformers are trim, lower, upper and squash (which collapses runs of
+// whitespace into single spaces).
+func RegisterTransformer(name string, fn Transformer) {
+	transformers.Lock()
+	defer transformers.Unlock()
+
+	transformers.fns[name] = fn
+}
+
+func getTransformer(name string) (Transformer, bool) {
+	transformers.RLock()
+	defer transformers.RUnlock()
+
+	fn, ok := transformers.fns[name]
+	return fn, ok
+}
+
+// Transforms returns the names of the transformation steps declared on the
+// field's transform tag, in the order they should run.
+func (f *Field) Transforms() []string {
+	if t := f.tags.Get("transform"); t != nil {
+		return t.ValueList(",")
+	}
+
+	return nil
+}
+
+// ApplyTransforms runs the transform tag pipelines of every string field (and
+// string pointer or slice field) in v, which must be a pointer to a struct.
+// Nested structs are transformed too. Unknown transformer names are reported
+// as *FieldError values.
+func ApplyTransforms(v interface{}) error {
+	rv, err := settableStructValue(v)
+	if err != nil {
+		return fmt.Errorf("reflectutil.ApplyTransforms: %w", err)
+	}
+
+	if err := walkValue(rv, "", applyFieldTransforms); err != nil {
+		return fmt.Errorf("reflectutil.ApplyTransforms: %w", err)
+	}
+
+	return nil
+}
+
+func applyFieldTransforms(f *Field, v reflect.Value, path string) (bool, error) {
+	steps := f.Transforms()
+	if len(steps) == 0 {
+		return true, nil
+	}
+
+	fns := make([]Transformer, len(steps))
+	for i, name := range steps {
+		fn, ok := getTransformer(name)
+		if !ok {
+			return false, &FieldError{Field: path, Err: fmt.Errorf("unknown transformer %q", name)}
+		}
+		fns[i] = fn
+	}
+
+	apply := func(v reflect.Value) {
+		s := v.String()
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		v.SetString(s)
+	}
+
+	switch {
+	case !v.CanSet():
+		return false, nil
+	case v.Kind() == reflect.String:
+		apply(v)
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		if !v.IsNil() {
+			apply(v.Elem())
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			apply(v.Index(i))
+		}
+	default:
+		return false, &FieldError{Field: path, Err: fmt.Errorf("can't transform field of type %s", v.Type())}
+	}
+
+	return false, nil
+}
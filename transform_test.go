@@ -0,0 +1,86 @@
+package reflectutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	RegisterTransformer("reverse", func(s string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	})
+
+	type Address struct {
+		City string `transform:"trim,upper"`
+	}
+
+	type Base struct {
+		Code string `transform:"trim"`
+	}
+
+	type Request struct {
+		Base
+		Email    string   `transform:"trim,lower"`
+		Name     *string  `transform:"squash"`
+		Tags     []string `transform:"trim,lower"`
+		Plain    string
+		Reversed string `transform:"reverse"`
+		Address  Address
+		Others   []*Address
+	}
+
+	t.Run("applies pipelines", func(t *testing.T) {
+		a := assert.New(t)
+
+		name := "  Jane    Doe "
+		r := Request{
+			Base:     Base{Code: " x "},
+			Email:    "  Jane@Example.COM ",
+			Name:     &name,
+			Tags:     []string{" A ", "b"},
+			Plain:    " untouched ",
+			Reversed: "abc",
+			Address:  Address{City: " perth "},
+			Others:   []*Address{{City: "sydney "}, nil},
+		}
+
+		a.NoError(ApplyTransforms(&r))
+
+		a.Equal("x", r.Code)
+		a.Equal("jane@example.com", r.Email)
+		a.Equal("Jane Doe", *r.Name)
+		a.Equal([]string{"a", "b"}, r.Tags)
+		a.Equal(" untouched ", r.Plain)
+		a.Equal("cba", r.Reversed)
+		a.Equal("PERTH", r.Address.City)
+		a.Equal("SYDNEY", r.Others[0].City)
+	})
+
+	t.Run("reports bad fields", func(t *testing.T) {
+		a := assert.New(t)
+
+		type Bad struct {
+			A string `transform:"trim,nope"`
+			B int    `transform:"trim"`
+		}
+
+		err := ApplyTransforms(&Bad{})
+
+		var fieldError *FieldError
+		if a.True(errors.As(err, &fieldError)) {
+			a.Equal("A", fieldError.Field)
+		}
+		a.ErrorContains(err, `A: unknown transformer "nope"`)
+		a.ErrorContains(err, "B: can't transform field of type int")
+	})
+
+	t.Run("requires a pointer", func(t *testing.T) {
+		assert.ErrorContains(t, ApplyTransforms(Request{}), "pointer to a struct")
+	})
+}
@@ -0,0 +1,99 @@
+package reflectutil
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// PkgPath returns the import path of the package the described type was
+// declared in, or "" for anonymous and predeclared types.
+func (s *StructDescription) PkgPath() string {
+	if s.typ == nil {
+		return ""
+	}
+
+	return s.typ.PkgPath()
+}
+
+// FullName returns the description's name qualified with its package's
+// import path, e.g. "github.com/me/pkg.User".
+func (s *StructDescription) FullName() string {
+	if p := s.PkgPath(); p != "" {
+		return p + "." + s.name
+	}
+
+	return s.name
+}
+
+// SameType reports whether both descriptions are of the same type, as
+// identified by FullName rather than by reflect.Type, so descriptions that
+// were built without one can still be compared.
+func (s *StructDescription) SameType(other *StructDescription) bool {
+	if s.typ != nil && other.typ != nil {
+		return s.typ == other.typ
+	}
+
+	return s.FullName() == other.FullName()
+}
+
+// TypePkgPath returns the import path of the package the field's type was
+// declared in, looking through pointers, so a *pkg.User field gives "pkg".
+func (f *Field) TypePkgPath() string {
+	if f.typ == nil {
+		return ""
+	}
+
+	return derefType(f.typ).PkgPath()
+}
+
+// TypeFullName returns the field's type as TypeFullName would.
+func (f *Field) TypeFullName() string {
+	if f.typ == nil {
+		return ""
+	}
+
+	return TypeFullName(f.typ)
+}
+
+// TypeFullName is like typ.String(), but qualifies named types with their full
+// import path rather than just the package name, e.g.
+// "map[string]*github.com/me/pkg.User", so types from different packages that
+// happen to share a package name can be told apart.
+func TypeFullName(typ reflect.Type) string {
+	if typ.Name() != "" {
+		if p := typ.PkgPath(); p != "" {
+			return p + "." + typ.Name()
+		}
+
+		return typ.Name()
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return "*" + TypeFullName(typ.Elem())
+	case reflect.Slice:
+		return "[]" + TypeFullName(typ.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(typ.Len()) + "]" + TypeFullName(typ.Elem())
+	case reflect.Map:
+		return "map[" + TypeFullName(typ.Key()) + "]" + TypeFullName(typ.Elem())
+	case reflect.Chan:
+		switch typ.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + TypeFullName(typ.Elem())
+		case reflect.SendDir:
+			return "chan<- " + TypeFullName(typ.Elem())
+		default:
+			return "chan " + TypeFullName(typ.Elem())
+		}
+	default:
+		return typ.String()
+	}
+}
+
+// SameTypeName reports whether a and b have the same fully qualified name.
+// Unlike comparing reflect.Types, this also works for types loaded from
+// different sources, such as a description built with NewStructDescription.
+func SameTypeName(a, b reflect.Type) bool {
+	return TypeFullName(a) == TypeFullName(b)
+}
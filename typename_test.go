@@ -0,0 +1,72 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeNameTestUser struct {
+	Name    string
+	Created *time.Time
+	Friends map[string][]*typeNameTestUser
+	Events  <-chan [2]int
+}
+
+func TestTypeFullName(t *testing.T) {
+	a := assert.New(t)
+
+	for _, tc := range []struct {
+		v        interface{}
+		expected string
+	}{
+		{"", "string"},
+		{time.Time{}, "time.Time"},
+		{typeNameTestUser{}, "fknsrs.biz/p/reflectutil.typeNameTestUser"},
+		{map[string][]*typeNameTestUser{}, "map[string][]*fknsrs.biz/p/reflectutil.typeNameTestUser"},
+		{make(<-chan [2]int), "<-chan [2]int"},
+		{make(chan<- error), "chan<- error"},
+		{struct{ A int }{}, "struct { A int }"},
+	} {
+		a.Equal(tc.expected, TypeFullName(reflect.TypeOf(tc.v)))
+	}
+
+	a.True(SameTypeName(reflect.TypeOf(time.Time{}), reflect.TypeOf(time.Now())))
+	a.False(SameTypeName(reflect.TypeOf(time.Time{}), reflect.TypeOf(typeNameTestUser{})))
+}
+
+func TestDescriptionFullName(t *testing.T) {
+	a := assert.New(t)
+
+	d, err := GetDescription(typeNameTestUser{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("fknsrs.biz/p/reflectutil", d.PkgPath())
+	a.Equal("fknsrs.biz/p/reflectutil.typeNameTestUser", d.FullName())
+
+	a.Equal("", d.Field("Name").TypePkgPath())
+	a.Equal("time", d.Field("Created").TypePkgPath())
+	a.Equal("*time.Time", d.Field("Created").TypeFullName())
+	a.Equal("map[string][]*fknsrs.biz/p/reflectutil.typeNameTestUser", d.Field("Friends").TypeFullName())
+
+	generated, err := NewStructDescription("typeNameTestUser", nil, nil)
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("", generated.PkgPath())
+	a.False(d.SameType(generated))
+	a.True(d.SameType(d))
+
+	anon, err := GetDescription(struct{ A int }{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal("", anon.PkgPath())
+	a.Equal("", anon.FullName())
+}
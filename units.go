@@ -0,0 +1,136 @@
+package reflectutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// ParseByteSize parses sizes like "512", "10MB" or "1.5GiB". Units are
+// case-insensitive; KB, MB etc. are powers of 1000 and KiB, MiB etc. are
+// powers of 1024.
+func ParseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(c rune) bool {
+		return !(c >= '0' && c <= '9') && c != '.' && c != '-' && c != '+'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.ParseByteSize(%q): invalid number: %w", s, err)
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf("reflectutil.ParseByteSize(%q): unknown unit %q", s, strings.TrimSpace(s[i:]))
+	}
+
+	r := n * unit
+	if r > math.MaxInt64 || r < math.MinInt64 {
+		return 0, fmt.Errorf("reflectutil.ParseByteSize(%q): value out of range", s)
+	}
+
+	return int64(r), nil
+}
+
+// ParsePercentage parses "75%" as 0.75. Values without a percent sign are
+// taken as plain ratios, so "0.75" is also 0.75.
+func ParsePercentage(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+		scale = 100
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.ParsePercentage: %w", err)
+	}
+
+	return n / scale, nil
+}
+
+func (p *Parameter) Int() (int64, error) {
+	n, err := strconv.ParseInt(p.Value(), 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Parameter.Int(%s): %w", p.name, err)
+	}
+
+	return n, nil
+}
+
+func (p *Parameter) Float() (float64, error) {
+	n, err := strconv.ParseFloat(p.Value(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Parameter.Float(%s): %w", p.name, err)
+	}
+
+	return n, nil
+}
+
+// Bool treats a parameter with no value (e.g. `omitempty`) as true.
+func (p *Parameter) Bool() (bool, error) {
+	if p.Value() == "" {
+		return true, nil
+	}
+
+	b, err := strconv.ParseBool(p.Value())
+	if err != nil {
+		return false, fmt.Errorf("reflectutil.Parameter.Bool(%s): %w", p.name, err)
+	}
+
+	return b, nil
+}
+
+// Bytes parses the value as a byte size, e.g. `max:10MB`.
+func (p *Parameter) Bytes() (int64, error) {
+	n, err := ParseByteSize(p.Value())
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Parameter.Bytes(%s): %w", p.name, err)
+	}
+
+	return n, nil
+}
+
+// Duration parses the value with time.ParseDuration, e.g. `ttl:5m`.
+func (p *Parameter) Duration() (time.Duration, error) {
+	d, err := time.ParseDuration(p.Value())
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Parameter.Duration(%s): %w", p.name, err)
+	}
+
+	return d, nil
+}
+
+// Ratio parses the value as a percentage or ratio, e.g. `ratio:75%`.
+func (p *Parameter) Ratio() (float64, error) {
+	n, err := ParsePercentage(p.Value())
+	if err != nil {
+		return 0, fmt.Errorf("reflectutil.Parameter.Ratio(%s): %w", p.name, err)
+	}
+
+	return n, nil
+}
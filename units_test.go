@@ -0,0 +1,133 @@
+package reflectutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		result int64
+		error  string
+	}{
+		{"0", 0, ""},
+		{"512", 512, ""},
+		{"512B", 512, ""},
+		{"10MB", 10000000, ""},
+		{"10mb", 10000000, ""},
+		{"1.5KiB", 1536, ""},
+		{"2 GiB", 2 << 30, ""},
+		{"1TB", 1000000000000, ""},
+		{"", 0, "invalid number"},
+		{"MB", 0, "invalid number"},
+		{"10XB", 0, `unknown unit "XB"`},
+		{"100000PB", 0, "out of range"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			a := assert.New(t)
+
+			n, err := ParseByteSize(tc.input)
+
+			if tc.error != "" {
+				a.ErrorContains(err, tc.error)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal(tc.result, n)
+		})
+	}
+}
+
+func TestParsePercentage(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		result float64
+		error  bool
+	}{
+		{"75%", 0.75, false},
+		{" 12.5 % ", 0.125, false},
+		{"0.5", 0.5, false},
+		{"%", 0, true},
+		{"x", 0, true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			a := assert.New(t)
+
+			n, err := ParsePercentage(tc.input)
+
+			if tc.error {
+				a.Error(err)
+			} else {
+				a.NoError(err)
+			}
+
+			a.InDelta(tc.result, n, 1e-9)
+		})
+	}
+}
+
+func TestTypedParameters(t *testing.T) {
+	a := assert.New(t)
+
+	tag, err := ParseTag("x", ",max:10MB,ttl:5m,ratio:75%,count:0x10,scale:1.5,omitempty,strict:false,bad:zz")
+	if !a.NoError(err) {
+		return
+	}
+
+	bytes, err := tag.Parameter("max").Bytes()
+	a.NoError(err)
+	a.Equal(int64(10000000), bytes)
+
+	ttl, err := tag.Parameter("ttl").Duration()
+	a.NoError(err)
+	a.Equal(5*time.Minute, ttl)
+
+	ratio, err := tag.Parameter("ratio").Ratio()
+	a.NoError(err)
+	a.InDelta(0.75, ratio, 1e-9)
+
+	count, err := tag.Parameter("count").Int()
+	a.NoError(err)
+	a.Equal(int64(16), count)
+
+	scale, err := tag.Parameter("scale").Float()
+	a.NoError(err)
+	a.Equal(1.5, scale)
+
+	omitempty, err := tag.Parameter("omitempty").Bool()
+	a.NoError(err)
+	a.True(omitempty)
+
+	strict, err := tag.Parameter("strict").Bool()
+	a.NoError(err)
+	a.False(strict)
+
+	quoted, err := ParseTag("x", ",max:'10MB',ttl:\"5m\"")
+	if a.NoError(err) {
+		bytes, err := quoted.Parameter("max").Bytes()
+		a.NoError(err)
+		a.Equal(int64(10000000), bytes)
+
+		ttl, err := quoted.Parameter("ttl").Duration()
+		a.NoError(err)
+		a.Equal(5*time.Minute, ttl)
+	}
+
+	bad := tag.Parameter("bad")
+	_, err = bad.Bytes()
+	a.ErrorContains(err, "reflectutil.Parameter.Bytes(bad)")
+	_, err = bad.Duration()
+	a.ErrorContains(err, "reflectutil.Parameter.Duration(bad)")
+	_, err = bad.Ratio()
+	a.ErrorContains(err, "reflectutil.Parameter.Ratio(bad)")
+	_, err = bad.Int()
+	a.ErrorContains(err, "reflectutil.Parameter.Int(bad)")
+	_, err = bad.Float()
+	a.ErrorContains(err, "reflectutil.Parameter.Float(bad)")
+	_, err = bad.Bool()
+	a.ErrorContains(err, "reflectutil.Parameter.Bool(bad)")
+}
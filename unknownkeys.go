@@ -0,0 +1,105 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// UnknownKeyPolicy controls what decoders do with input keys that don't
+// match any field.
+type UnknownKeyPolicy int
+
+const (
+	// UnknownKeysIgnore skips unknown keys.
+	UnknownKeysIgnore UnknownKeyPolicy = iota
+	// UnknownKeysError reports each unknown key as a *FieldError wrapping
+	// ErrUnknownKey.
+	UnknownKeysError
+	// UnknownKeysCollect records unknown keys (see WithUnknownKeyCollector)
+	// without failing.
+	UnknownKeysCollect
+)
+
+func (p UnknownKeyPolicy) String() string {
+	switch p {
+	case UnknownKeysIgnore:
+		return "Ignore"
+	case UnknownKeysError:
+		return "Error"
+	case UnknownKeysCollect:
+		return "Collect"
+	default:
+		return fmt.Sprintf("[UNKNOWN POLICY %d]", int(p))
+	}
+}
+
+// ErrUnknownKey is wrapped by the errors reported under UnknownKeysError.
+var ErrUnknownKey = errors.New("no such field")
+
+// unknownKey applies the unknown key policy to key, returning an error if
+// the policy calls for one. Collected keys include the path of any nested
+// maps they were found in.
+func (o *options) unknownKey(key string) error {
+	if o == nil {
+		return nil
+	}
+
+	switch o.unknownKeys {
+	case UnknownKeysError:
+		return &FieldError{Field: key, Err: ErrUnknownKey}
+	case UnknownKeysCollect:
+		if o.unknownKeyCollector != nil {
+			*o.unknownKeyCollector = append(*o.unknownKeyCollector, o.keyPrefix+key)
+		}
+	}
+
+	return nil
+}
+
+// unknownKeys applies the unknown key policy to every key of m, a map with
+// string keys, that isn't in used, in sorted order.
+func unknownKeys(o *options, m interface{}, used map[string]bool) error {
+	if o == nil || o.unknownKeys == UnknownKeysIgnore {
+		return nil
+	}
+
+	var errs []error
+	for _, k := range unusedKeys(m, used) {
+		if err := o.unknownKey(k); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func unusedKeys(m interface{}, used map[string]bool) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		if !used[k.String()] {
+			keys = append(keys, k.String())
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// forKey returns a copy of o for decoding the value found under key into the
+// field called field, so that collected keys and recorded provenance carry
+// their full paths.
+func (o *options) forKey(key, field string) *options {
+	if o == nil || (o.unknownKeys != UnknownKeysCollect && o.provenance == nil) {
+		return o
+	}
+
+	c := *o
+	c.keyPrefix += key + "."
+	if field != "" {
+		c.fieldPrefix += field + "."
+	}
+
+	return &c
+}
@@ -0,0 +1,107 @@
+package reflectutil
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unknownKeysTestAddress struct {
+	City string `json:"city" bson:"city" config:"city"`
+}
+
+type unknownKeysTestUser struct {
+	Name    string                 `json:"name" bson:"name" redis:"name" dynamodbav:"name" config:"name" query:"name"`
+	Address unknownKeysTestAddress `json:"address" bson:"address" config:"address"`
+}
+
+func TestUnknownKeyPolicyString(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("Ignore", UnknownKeysIgnore.String())
+	a.Equal("Error", UnknownKeysError.String())
+	a.Equal("Collect", UnknownKeysCollect.String())
+	a.Equal("[UNKNOWN POLICY 9]", UnknownKeyPolicy(9).String())
+}
+
+func TestUnknownKeysSetFields(t *testing.T) {
+	a := assert.New(t)
+
+	values := map[string]interface{}{
+		"name":    "Jo",
+		"age":     30,
+		"address": map[string]interface{}{"city": "Perth", "zip": "6000"},
+	}
+
+	var u unknownKeysTestUser
+	a.NoError(SetFields(&u, values, "json"))
+	a.Equal(unknownKeysTestUser{Name: "Jo", Address: unknownKeysTestAddress{City: "Perth"}}, u)
+
+	err := SetFields(&u, values, "json", WithUnknownKeyPolicy(UnknownKeysError))
+	a.True(errors.Is(err, ErrUnknownKey))
+	a.ErrorContains(err, "age: no such field")
+	a.ErrorContains(err, "zip: no such field")
+
+	var keys []string
+	a.NoError(SetFields(&u, values, "json", WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"address.zip", "age"}, keys)
+}
+
+func TestUnknownKeysDecoders(t *testing.T) {
+	a := assert.New(t)
+
+	var u unknownKeysTestUser
+	var keys []string
+
+	a.NoError(FromBSONMap(map[string]interface{}{
+		"name":    "Jo",
+		"age":     int32(30),
+		"address": map[string]interface{}{"city": "Perth", "zip": "6000"},
+	}, &u, WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"address.zip", "age"}, keys)
+
+	keys = nil
+	a.NoError(FromRedisHash(map[string]string{"name": "Jo", "age": "30"}, &u, WithUnknownKeyCollector(&keys)))
+	a.Equal([]string{"age"}, keys)
+
+	err := FromDynamoDBItem(map[string]interface{}{
+		"name": map[string]interface{}{"S": "Jo"},
+		"age":  map[string]interface{}{"N": "30"},
+	}, &u, WithUnknownKeyPolicy(UnknownKeysError))
+	a.True(errors.Is(err, ErrUnknownKey))
+
+	keys = nil
+	l := ConfigLoader{
+		File: map[string]interface{}{
+			"name":    "Jo",
+			"debug":   true,
+			"address": map[string]interface{}{"city": "Perth", "zip": "6000"},
+		},
+		LookupEnv: func(string) (string, bool) { return "", false },
+		Options:   []Option{WithUnknownKeyCollector(&keys)},
+	}
+	_, err = l.Load(&u)
+	a.NoError(err)
+	a.Equal([]string{"address.zip", "debug"}, keys)
+}
+
+func TestUnknownKeysBindRequest(t *testing.T) {
+	a := assert.New(t)
+
+	r := httptest.NewRequest("POST", "/?name=Jo&page=2", strings.NewReader("sort=asc"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var u unknownKeysTestUser
+	var keys []string
+	a.NoError(BindRequest(r, map[string]string{"id": "1"}, &u, WithUnknownKeyCollector(&keys)))
+	a.Equal("Jo", u.Name)
+	a.Equal([]string{"path.id", "query.page", "form.sort"}, keys)
+
+	r = httptest.NewRequest("GET", "/?name=Jo&page=2", nil)
+	err := BindRequest(r, nil, &u, WithUnknownKeyPolicy(UnknownKeysError))
+	a.True(errors.Is(err, ErrUnknownKey))
+	a.ErrorContains(err, `query "page": no such field`)
+}
@@ -0,0 +1,131 @@
+package reflectutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// structValue dereferences v until it reaches a struct.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv, ok := v.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(v)
+	}
+
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("reflectutil.structValue: got nil %s", rv.Type())
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("reflectutil.structValue: input should be struct or pointer to struct; got %s", rv.Kind())
+	}
+
+	return rv, nil
+}
+
+// fieldValue returns the value of the field at index within v. If the field is
+// promoted through a nil embedded pointer, ok is false.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	fv, err := fieldByIndexErr(v, index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return fv, true
+}
+
+// walkFunc is called for every field visited by walkValue. Returning true
+// makes walkValue descend into the field's value if it holds structs.
+type walkFunc func(f *Field, v reflect.Value, path string) (bool, error)
+
+// walkValue visits each field of the struct value rv, descending into nested
+// structs, pointers to structs, and slices or arrays of those when fn asks it
+// to. Embedded fields are visited but never descended into, since their
+// promoted fields are visited directly. Errors returned by fn are collected
+// and joined rather than stopping the walk.
+func walkValue(rv reflect.Value, prefix string, fn walkFunc) error {
+	d, err := GetDescription(rv.Type())
+	if err != nil {
+		return fmt.Errorf("reflectutil.walkValue: %w", err)
+	}
+
+	var errs []error
+
+	for i := range d.fields {
+		f := &d.fields[i]
+
+		fv, ok := fieldValue(rv, f.index)
+		if !ok {
+			continue
+		}
+
+		path := prefix + f.name
+
+		descend, err := fn(f, fv, path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if descend && !f.embedded {
+			if err := walkNested(fv, path, fn); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func walkNested(v reflect.Value, path string, fn walkFunc) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return walkValue(v, path+".", fn)
+	case reflect.Slice, reflect.Array:
+		if !isStructLike(v.Type().Elem()) {
+			return nil
+		}
+
+		var errs []error
+		for i := 0; i < v.Len(); i++ {
+			if err := walkNested(v.Index(i), path+"["+strconv.Itoa(i)+"]", fn); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+func isStructLike(typ reflect.Type) bool {
+	return derefType(typ).Kind() == reflect.Struct || typ.Kind() == reflect.Interface
+}
+
+// settableStructValue is like structValue, but requires v to be (or point to)
+// a struct that can be modified in place.
+func settableStructValue(v interface{}) (reflect.Value, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if !rv.CanSet() {
+		return reflect.Value{}, fmt.Errorf("reflectutil.settableStructValue: input should be a pointer to a struct; got %T: %w", v, &SetError{Reason: SetPassedByValue})
+	}
+
+	return rv, nil
+}
@@ -0,0 +1,133 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WarningKind identifies a suspicious but legal construct in a struct tag.
+type WarningKind int
+
+const (
+	// WarningSpaceAfterColon is whitespace between a tag's name and its
+	// value, e.g. `json: "name"`, which reflect.StructTag doesn't accept.
+	WarningSpaceAfterColon WarningKind = iota + 1
+	// WarningMissingSpace is a tag that follows the previous one without a
+	// space, e.g. `json:"a"db:"a"`.
+	WarningMissingSpace
+	// WarningNoValue is a tag name on its own, e.g. `json`, which
+	// reflect.StructTag ignores.
+	WarningNoValue
+	// WarningDuplicateTag is a tag name that appears more than once on a
+	// field. Only the first is seen by most code.
+	WarningDuplicateTag
+	// WarningEmptyParameterName is a parameter with no name, e.g. the ":x" in
+	// `db:"a,:x"`.
+	WarningEmptyParameterName
+	// WarningDashWithParameters is a "-" value with parameters, e.g.
+	// `json:"-,omitempty"`, which encoding/json takes to mean a field named
+	// "-" rather than an excluded one.
+	WarningDashWithParameters
+)
+
+func (k WarningKind) String() string {
+	switch k {
+	case WarningSpaceAfterColon:
+		return "space after colon"
+	case WarningMissingSpace:
+		return "missing space between tags"
+	case WarningNoValue:
+		return "tag has no value"
+	case WarningDuplicateTag:
+		return "duplicate tag"
+	case WarningEmptyParameterName:
+		return "empty parameter name"
+	case WarningDashWithParameters:
+		return "\"-\" with parameters"
+	default:
+		return fmt.Sprintf("[UNKNOWN WARNING %d]", int(k))
+	}
+}
+
+// Warning reports a suspicious but legal construct in a field's tags.
+type Warning struct {
+	// Field is the field's path, e.g. "Base.ID".
+	Field string
+	Tag   string
+	Kind  WarningKind
+	// Span is the position of the problem within the field's struct tag, if
+	// the raw tag is available.
+	Span Span
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: tag %s: %s", w.Field, w.Tag, w.Kind)
+}
+
+// Warnings checks every field's tags for constructs that parse, but probably
+// don't mean what was intended, so they can be reported without failing the
+// description. Checks that need the raw struct tag are skipped for
+// descriptions that don't have a struct type.
+func (s *StructDescription) Warnings() []Warning {
+	var r []Warning
+
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		path := strings.Join(append(append([]string(nil), f.path...), f.name), ".")
+
+		if s.typ != nil && s.typ.Kind() == reflect.Struct {
+			r = append(r, rawTagWarnings(path, string(s.typ.FieldByIndex(f.index).Tag))...)
+		}
+
+		seen := make(map[string]bool, len(f.tags))
+
+		for j := range f.tags {
+			t := &f.tags[j]
+
+			if seen[t.name] {
+				r = append(r, Warning{Field: path, Tag: t.name, Kind: WarningDuplicateTag, Span: t.span})
+			}
+			seen[t.name] = true
+
+			for _, p := range t.parameters {
+				if p.name == "" {
+					r = append(r, Warning{Field: path, Tag: t.name, Kind: WarningEmptyParameterName, Span: p.span})
+				}
+			}
+
+			if t.value == "-" && len(t.parameters) > 0 {
+				r = append(r, Warning{Field: path, Tag: t.name, Kind: WarningDashWithParameters, Span: t.span})
+			}
+		}
+	}
+
+	return r
+}
+
+func rawTagWarnings(path, tag string) []Warning {
+	positions, err := parseTagPositionList(tag)
+	if err != nil {
+		return nil
+	}
+
+	var r []Warning
+
+	for i, p := range positions {
+		name := p.getName(tag)
+
+		switch {
+		case p.colon == 0:
+			r = append(r, Warning{Field: path, Tag: name, Kind: WarningNoValue, Span: Span{p.nameStart, p.nameEnd + 1}})
+		case p.valueStart > p.colon+1:
+			r = append(r, Warning{Field: path, Tag: name, Kind: WarningSpaceAfterColon, Span: Span{p.colon + 1, p.valueStart}})
+		}
+
+		if i > 0 && positions[i-1].colon != 0 && p.nameStart == positions[i-1].valueEnd+1 {
+			r = append(r, Warning{Field: path, Tag: name, Kind: WarningMissingSpace, Span: Span{p.nameStart, p.nameStart}})
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,62 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarnings(t *testing.T) {
+	a := assert.New(t)
+
+	str := reflect.TypeOf("")
+
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "Clean", Type: str, Tag: `json:"clean,omitempty" db:"clean"`},
+		{Name: "Spaced", Type: str, Tag: `json: "spaced"`},
+		{Name: "Joined", Type: str, Tag: `json:"joined"db:"joined"`},
+		{Name: "Bare", Type: str, Tag: `json:"bare" required`},
+		{Name: "Twice", Type: str, Tag: `db:"a" db:"b"`},
+		{Name: "EmptyParam", Type: str, Tag: `db:"e,:x"`},
+		{Name: "Dash", Type: str, Tag: `json:"-,omitempty"`},
+	})
+
+	d, err := GetDescription(reflect.New(typ).Interface())
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Equal([]Warning{
+		{Field: "Spaced", Tag: "json", Kind: WarningSpaceAfterColon, Span: Span{5, 6}},
+		{Field: "Joined", Tag: "db", Kind: WarningMissingSpace, Span: Span{13, 13}},
+		{Field: "Bare", Tag: "required", Kind: WarningNoValue, Span: Span{12, 20}},
+		{Field: "Twice", Tag: "db", Kind: WarningDuplicateTag},
+		{Field: "EmptyParam", Tag: "db", Kind: WarningEmptyParameterName},
+		{Field: "Dash", Tag: "json", Kind: WarningDashWithParameters},
+	}, d.Warnings())
+
+	a.Equal("Spaced: tag json: space after colon", d.Warnings()[0].String())
+
+	d, err = GetDescription(reflect.New(typ).Interface(), WithTagSpans())
+	if a.NoError(err) {
+		a.Equal(Span{7, 13}, d.Warnings()[3].Span)
+	}
+
+	type base struct {
+		ID string `db:"id" db:"pk"`
+	}
+	type outer struct{ base }
+
+	d, err = GetDescription(outer{})
+	if a.NoError(err) {
+		a.Equal([]Warning{{Field: "base.ID", Tag: "db", Kind: WarningDuplicateTag}}, d.Warnings())
+	}
+
+	d, err = NewStructDescription("Generated", nil, []FieldSpec{{Name: "A", Tag: `json:"-,x"`}})
+	if a.NoError(err) {
+		a.Equal([]Warning{{Field: "A", Tag: "json", Kind: WarningDashWithParameters}}, d.Warnings())
+	}
+
+	a.Equal("[UNKNOWN WARNING 0]", WarningKind(0).String())
+}
@@ -0,0 +1,121 @@
+package reflectutil
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Wrapper describes a type that wraps another, such as Option[T], null.String
+// or decimal.Decimal, so that it can be treated as its underlying type.
+type Wrapper struct {
+	// Type is the wrapper type itself.
+	Type reflect.Type
+	// Underlying is the type being wrapped, e.g. string for null.String.
+	Underlying reflect.Type
+	// Nullable marks the wrapper as being able to hold no value.
+	Nullable bool
+	// Precision is an optional precision annotation for numeric wrappers, in
+	// significant digits. Zero means unspecified.
+	Precision int
+	// Unwrap returns the wrapped value, and false if the wrapper holds no
+	// value.
+	Unwrap func(v reflect.Value) (reflect.Value, bool)
+	// Wrap builds a wrapper value from a value of the underlying type. If
+	// it's set, SetFields and the other setters fill wrapper fields by
+	// converting their input to the underlying type and wrapping the result.
+	Wrap func(v reflect.Value) reflect.Value
+}
+
+// WrapperResolver recognises a family of wrapper types, e.g. every
+// instantiation of a generic Option[T].
+type WrapperResolver func(typ reflect.Type) (*Wrapper, bool)
+
+var wrappers = struct {
+	sync.RWMutex
+	types     map[reflect.Type]*Wrapper
+	resolvers []WrapperResolver
+}{types: make(map[reflect.Type]*Wrapper)}
+
+// RegisterWrapper registers w for w.Type, replacing any wrapper already
+// registered for that type.
+func RegisterWrapper(w Wrapper) {
+	wrappers.Lock()
+	defer wrappers.Unlock()
+
+	wrappers.types[w.Type] = &w
+}
+
+// RegisterWrapperResolver adds fn to the resolvers consulted, in the order
+// they were registered, for types without a wrapper of their own.
+func RegisterWrapperResolver(fn WrapperResolver) {
+	wrappers.Lock()
+	defer wrappers.Unlock()
+
+	wrappers.resolvers = append(wrappers.resolvers, fn)
+}
+
+// LookupWrapper returns the registered wrapper for typ, if there is one.
+func LookupWrapper(typ reflect.Type) (*Wrapper, bool) {
+	wrappers.RLock()
+	w, ok := wrappers.types[typ]
+	resolvers := wrappers.resolvers
+	wrappers.RUnlock()
+
+	if ok {
+		return w, true
+	}
+
+	for _, fn := range resolvers {
+		if w, ok := fn(typ); ok && w != nil {
+			return w, true
+		}
+	}
+
+	return nil, false
+}
+
+// Wrapper returns the registered wrapper for the field's type, if any.
+func (f *Field) Wrapper() *Wrapper {
+	w, _ := LookupWrapper(f.typ)
+	return w
+}
+
+// UnderlyingType returns the type wrapped by the field's type if it is a
+// registered wrapper, or the field's type otherwise.
+func (f *Field) UnderlyingType() reflect.Type {
+	if w, ok := LookupWrapper(f.typ); ok && w.Underlying != nil {
+		return w.Underlying
+	}
+
+	return f.typ
+}
+
+// UnwrapValue returns the value wrapped by v if its type is a registered
+// wrapper, and false if the wrapper is empty. Values of other types are
+// returned as they are.
+func UnwrapValue(v reflect.Value) (reflect.Value, bool) {
+	if w, ok := LookupWrapper(v.Type()); ok && w.Unwrap != nil {
+		return w.Unwrap(v)
+	}
+
+	return v, true
+}
+
+// setWrapped stores a value in v through its registered wrapper, if it has one
+// that can Wrap, with set filling in a value of the underlying type. It
+// returns false if v's type isn't such a wrapper.
+func setWrapped(v reflect.Value, set func(u reflect.Value) error) (bool, error) {
+	w, ok := LookupWrapper(v.Type())
+	if !ok || w.Wrap == nil || w.Underlying == nil {
+		return false, nil
+	}
+
+	u := reflect.New(w.Underlying).Elem()
+	if err := set(u); err != nil {
+		return true, err
+	}
+
+	v.Set(w.Wrap(u))
+
+	return true, nil
+}
@@ -0,0 +1,146 @@
+package reflectutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type wrapperTestOption[T any] struct {
+	value T
+	set   bool
+}
+
+func (o *wrapperTestOption[T]) wrap(v reflect.Value) {
+	o.value, o.set = v.Interface().(T), true
+}
+
+type wrapperTestDecimal struct{ digits string }
+
+type wrapperTestReentrant struct{ n int }
+
+func init() {
+	RegisterWrapper(Wrapper{
+		Type:       reflect.TypeOf(wrapperTestDecimal{}),
+		Underlying: reflect.TypeOf(""),
+		Precision:  38,
+		Unwrap: func(v reflect.Value) (reflect.Value, bool) {
+			return reflect.ValueOf(v.Interface().(wrapperTestDecimal).digits), true
+		},
+		Wrap: func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(wrapperTestDecimal{digits: v.String()})
+		},
+	})
+
+	RegisterWrapperResolver(func(typ reflect.Type) (*Wrapper, bool) {
+		if typ.Kind() != reflect.Struct || typ.NumField() != 2 || typ.Field(0).Name != "value" || typ.Field(1).Name != "set" {
+			return nil, false
+		}
+
+		return &Wrapper{
+			Type:       typ,
+			Underlying: typ.Field(0).Type,
+			Nullable:   true,
+			Unwrap: func(v reflect.Value) (reflect.Value, bool) {
+				return v.Field(0), v.Field(1).Bool()
+			},
+			Wrap: func(v reflect.Value) reflect.Value {
+				p := reflect.New(typ)
+				p.Interface().(interface{ wrap(reflect.Value) }).wrap(v)
+				return p.Elem()
+			},
+		}, true
+	})
+
+	// Registers a wrapper from inside a resolver, which must not deadlock.
+	RegisterWrapperResolver(func(typ reflect.Type) (*Wrapper, bool) {
+		if typ != reflect.TypeOf(wrapperTestReentrant{}) {
+			return nil, false
+		}
+
+		w := Wrapper{Type: typ, Underlying: reflect.TypeOf(0)}
+		RegisterWrapper(w)
+
+		return &w, true
+	})
+}
+
+func TestWrappers(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		Plain    string
+		Amount   wrapperTestDecimal
+		Optional wrapperTestOption[int]
+	}
+
+	d, err := GetDescription(S{})
+	if !a.NoError(err) {
+		return
+	}
+
+	a.Nil(d.Field("Plain").Wrapper())
+	a.Equal(reflect.TypeOf(""), d.Field("Plain").UnderlyingType())
+	a.False(d.Field("Plain").Nullable())
+
+	if w := d.Field("Amount").Wrapper(); a.NotNil(w) {
+		a.Equal(38, w.Precision)
+	}
+	a.Equal(reflect.TypeOf(""), d.Field("Amount").UnderlyingType())
+	a.False(d.Field("Amount").Nullable())
+
+	a.NotNil(d.Field("Optional").Wrapper())
+	a.Equal(reflect.TypeOf(0), d.Field("Optional").UnderlyingType())
+	a.True(d.Field("Optional").Nullable())
+
+	v, ok := UnwrapValue(reflect.ValueOf(wrapperTestDecimal{"1.50"}))
+	a.True(ok)
+	a.Equal("1.50", v.Interface())
+
+	v, ok = UnwrapValue(reflect.ValueOf(wrapperTestOption[int]{value: 3, set: true}))
+	a.True(ok)
+	a.Equal(int64(3), v.Int())
+
+	_, ok = UnwrapValue(reflect.ValueOf(wrapperTestOption[int]{}))
+	a.False(ok)
+
+	v, ok = UnwrapValue(reflect.ValueOf("x"))
+	a.True(ok)
+	a.Equal("x", v.Interface())
+}
+
+func TestWrapperSet(t *testing.T) {
+	a := assert.New(t)
+
+	type S struct {
+		Amount   wrapperTestDecimal
+		Optional wrapperTestOption[int]
+		Pointer  *wrapperTestOption[string]
+	}
+
+	var v S
+	if a.NoError(SetFields(&v, map[string]interface{}{"Amount": "1.50", "Optional": 3.0, "Pointer": "x"}, "")) {
+		a.Equal(S{
+			Amount:   wrapperTestDecimal{"1.50"},
+			Optional: wrapperTestOption[int]{value: 3, set: true},
+			Pointer:  &wrapperTestOption[string]{value: "x", set: true},
+		}, v)
+	}
+
+	v = S{}
+	f, err := GetDescription(S{})
+	if a.NoError(err) {
+		a.NoError(setFromString(f.Field("Optional"), reflect.ValueOf(&v).Elem().Field(1), "7"))
+		a.Equal(wrapperTestOption[int]{value: 7, set: true}, v.Optional)
+	}
+
+	a.ErrorContains(SetFields(&v, map[string]interface{}{"Optional": "x"}, ""), "Optional")
+}
+
+func TestLookupWrapperReentrant(t *testing.T) {
+	w, ok := LookupWrapper(reflect.TypeOf(wrapperTestReentrant{}))
+	if assert.True(t, ok) {
+		assert.Equal(t, reflect.TypeOf(0), w.Underlying)
+	}
+}